@@ -0,0 +1,225 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package prng
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/sixafter/prng-chacha/internal/randutil"
+)
+
+var (
+	ErrIntMaxInvalid     = fmt.Errorf("prng: max must be greater than zero")
+	ErrPrimeBitsTooSmall = fmt.Errorf("prng: prime size must be at least 2 bits")
+)
+
+// primeRounds is the number of Miller-Rabin rounds used by Prime, matching crypto/rand.
+const primeRounds = 20
+
+// Bytes is a buffered adapter over a random source. Each Read is served from an
+// internal buffer that is refilled from the source in batches, so a sequence of small
+// reads, such as those issued by crypto/rand.Int, costs few reads of the source.
+// Requests larger than the buffer go straight to the source. Bytes are cleared from
+// the buffer as they are consumed.
+//
+// A Bytes is not safe for concurrent use. Call Wipe when done with it to clear any
+// unread bytes.
+//
+// Example usage:
+//
+//	b := prng.NewBytes(prng.Reader)
+//	defer b.Wipe()
+//	n, err := rand.Int(b, max)
+type Bytes struct {
+	r randutil.Reader
+}
+
+// NewBytes returns a Bytes adapter reading from src.
+func NewBytes(src io.Reader) *Bytes {
+	b := &Bytes{}
+	b.r.Reset(src)
+	return b
+}
+
+// Read fills p with random bytes from the buffer, refilling it from the source as
+// needed. It returns an error only if the source fails.
+func (b *Bytes) Read(p []byte) (int, error) {
+	return b.r.Read(p)
+}
+
+// Reset discards any unread bytes and switches the adapter to src.
+func (b *Bytes) Reset(src io.Reader) {
+	b.r.Reset(src)
+}
+
+// Wipe clears any unread bytes from the buffer.
+func (b *Bytes) Wipe() {
+	b.r.Wipe()
+}
+
+// Int returns a uniformly distributed random value in [0, max) drawn from Reader. Unlike
+// crypto/rand.Int, it returns ErrIntMaxInvalid instead of panicking if max is not positive.
+//
+// All of the entropy for one call is read from a single PRNG instance taken from the
+// pool once, rather than through Reader on every attempt.
+//
+// Example usage:
+//
+//	n, err := prng.Int(big.NewInt(1000))
+//	if err != nil {
+//	    // Handle error
+//	}
+func Int(max *big.Int) (*big.Int, error) {
+	if max.Sign() <= 0 {
+		return nil, ErrIntMaxInvalid
+	}
+
+	var n *big.Int
+	err := withHeld(Reader, func(src io.Reader) error {
+		var err error
+		n, err = randutil.Int(src, max)
+		return err
+	})
+	return n, err
+}
+
+// Prime returns a number of the given bit length that is prime with high probability,
+// drawn from Reader. As with crypto/rand.Prime, the top two bits are set, so the product
+// of two such primes has exactly 2·bits bits, and each candidate passes
+// big.Int.ProbablyPrime(20).
+//
+// Since Go 1.26, crypto/rand.Prime ignores the reader passed to it unless the
+// cryptocustomrand GODEBUG setting is enabled, so Prime is the way to generate primes
+// from this package. All of the entropy for one call, typically hundreds of candidates,
+// is read from a single PRNG instance taken from the pool once.
+//
+// It returns ErrPrimeBitsTooSmall if bits is less than 2.
+func Prime(bits int) (*big.Int, error) {
+	if bits < 2 {
+		return nil, ErrPrimeBitsTooSmall
+	}
+
+	var p *big.Int
+	err := withHeld(Reader, func(src io.Reader) error {
+		var err error
+		p, err = prime(src, bits)
+		return err
+	})
+	return p, err
+}
+
+// prime draws random candidates of the given bit length from src until one is
+// probably prime. It follows the candidate construction of crypto/rand.Prime.
+func prime(src io.Reader, bits int) (*big.Int, error) {
+	// top is the number of bits used in the most significant byte.
+	top := uint(bits % 8)
+	if top == 0 {
+		top = 8
+	}
+
+	buf := make([]byte, (bits+7)/8)
+	defer clear(buf)
+	p := new(big.Int)
+
+	for {
+		if _, err := io.ReadFull(src, buf); err != nil {
+			return nil, err
+		}
+
+		// Clear bits in the first byte to make sure the candidate has a size <= bits.
+		buf[0] &= uint8(int(1<<top) - 1)
+		// Set the top two bits. If top is 1, the second bit is in the next byte.
+		if top >= 2 {
+			buf[0] |= 3 << (top - 2)
+		} else {
+			buf[0] |= 1
+			if len(buf) > 1 {
+				buf[1] |= 0x80
+			}
+		}
+		// Make the value odd since an even number this large certainly isn't prime.
+		buf[len(buf)-1] |= 1
+
+		p.SetBytes(buf)
+		if p.ProbablyPrime(primeRounds) {
+			return p, nil
+		}
+	}
+}
+
+// heldReader reads from one PRNG instance taken from a pool for the duration of a helper
+// call, skipping the shard selection and pool round trip of reader.Read on every attempt.
+type heldReader struct {
+	owner *reader
+	shard int
+	p     *prng
+}
+
+// Read fills buf from the held instance and records it as reader.Read would: the bytes
+// count against the instance's shard and, when latency tracking is enabled, a sample of
+// reads is timed. The pool get is counted once, by withHeld.
+func (h *heldReader) Read(buf []byte) (int, error) {
+	if len(buf) == 0 {
+		return 0, nil
+	}
+
+	var start time.Time
+	timed := sampled(h.owner.config.Load().LatencySampleInterval)
+	if timed {
+		start = time.Now()
+	}
+
+	n, err := h.p.Read(buf)
+	h.owner.stats[h.shard].bytesGenerated.Add(uint64(n))
+	if err == nil && timed {
+		h.owner.latency.read[readSizeClass(n)].observe(time.Since(start))
+	}
+	return n, err
+}
+
+// heldPool holds heldReader values for reuse by Int and Prime.
+var heldPool = sync.Pool{
+	New: func() any {
+		return &heldReader{}
+	},
+}
+
+// withHeld calls fn with a source for all of the entropy of one helper call. If src is
+// one of the package's readers, that source is a single PRNG instance held for the
+// whole call; otherwise it is src itself.
+func withHeld(src io.Reader, fn func(io.Reader) error) error {
+	r, ok := src.(*reader)
+	if !ok {
+		return fn(src)
+	}
+	if r.selfTest != nil {
+		if err := r.selfTest(); err != nil {
+			return err
+		}
+	}
+
+	shard := 0
+	if n := len(r.pools); n > 1 {
+		shard = shardIndex(n)
+	}
+	if r.config.Load().LatencySampleInterval > 0 {
+		r.stats[shard].poolGets.Add(1)
+	}
+	p := r.pools[shard].Get().(*prng)
+	defer r.pools[shard].Put(p)
+
+	h := heldPool.Get().(*heldReader)
+	*h = heldReader{owner: r, shard: shard, p: p}
+	defer func() {
+		*h = heldReader{}
+		heldPool.Put(h)
+	}()
+	return fn(h)
+}
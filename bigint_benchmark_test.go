@@ -0,0 +1,85 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package prng
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+// bigIntBounds are the bounds used by the Int benchmarks. Each is just above a power of
+// two, so nearly half of the candidates are rejected and several reads are needed.
+var bigIntBounds = []struct {
+	name string
+	max  *big.Int
+}{
+	{"Bits_64", new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 63), big.NewInt(1))},
+	{"Bits_256", new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(1))},
+	{"Bits_2048", new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 2047), big.NewInt(1))},
+}
+
+// BenchmarkBigInt_Int_Native measures Int, which reads through a single held instance
+// and a buffer.
+func BenchmarkBigInt_Int_Native(b *testing.B) {
+	for _, bc := range bigIntBounds {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				_, _ = Int(bc.max)
+			}
+		})
+	}
+}
+
+// BenchmarkBigInt_Int_CryptoRand measures crypto/rand.Int fed directly by Reader, which
+// costs one pooled Read per attempt.
+func BenchmarkBigInt_Int_CryptoRand(b *testing.B) {
+	for _, bc := range bigIntBounds {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				_, _ = rand.Int(Reader, bc.max)
+			}
+		})
+	}
+}
+
+// BenchmarkBigInt_Int_Native_Parallel measures Int with RunParallel.
+func BenchmarkBigInt_Int_Native_Parallel(b *testing.B) {
+	max := bigIntBounds[1].max
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = Int(max)
+		}
+	})
+}
+
+// BenchmarkBigInt_Int_CryptoRand_Parallel measures crypto/rand.Int fed by Reader with
+// RunParallel.
+func BenchmarkBigInt_Int_CryptoRand_Parallel(b *testing.B) {
+	max := bigIntBounds[1].max
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = rand.Int(Reader, max)
+		}
+	})
+}
+
+// BenchmarkBigInt_Prime measures Prime across common sizes. Primality testing
+// dominates, so the figures mostly reflect math/big.
+func BenchmarkBigInt_Prime(b *testing.B) {
+	for _, bits := range []int{256, 512, 1024} {
+		b.Run("Bits_"+itoa(bits), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				_, _ = Prime(bits)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package prng
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/sixafter/prng-chacha/internal/randutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// readCounter wraps a reader and counts Read calls.
+type readCounter struct {
+	r     io.Reader
+	calls int
+}
+
+func (c *readCounter) Read(p []byte) (int, error) {
+	c.calls++
+	return c.r.Read(p)
+}
+
+// Test_PRNG_Int verifies that Int stays in range, covers a small range, and rejects
+// non-positive bounds.
+func Test_PRNG_Int(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	max := big.NewInt(300) // 9 bits, so some candidates are rejected
+	seen := make(map[int64]bool)
+	for i := 0; i < 20000; i++ {
+		n, err := Int(max)
+		is.NoError(err)
+		is.GreaterOrEqual(n.Sign(), 0)
+		is.Equal(-1, n.Cmp(max))
+		seen[n.Int64()] = true
+	}
+	is.Len(seen, 300, "every value in [0, 300) should occur")
+
+	huge := new(big.Int).Lsh(big.NewInt(1), 4096)
+	n, err := Int(huge)
+	is.NoError(err)
+	is.Equal(-1, n.Cmp(huge))
+
+	for _, bad := range []*big.Int{big.NewInt(0), big.NewInt(-5)} {
+		n, err := Int(bad)
+		is.ErrorIs(err, ErrIntMaxInvalid)
+		is.Nil(n)
+	}
+}
+
+// Test_PRNG_Int_Stats verifies that bytes read through a held instance are counted.
+func Test_PRNG_Int_Stats(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	rdr, err := NewReader()
+	is.NoError(err)
+	r := rdr.(*reader)
+
+	var n *big.Int
+	calls := 0
+	err = withHeld(r, func(src io.Reader) error {
+		_, isHeld := src.(*heldReader)
+		is.True(isHeld, "a package reader should be held")
+		var err error
+		for ; calls < 10; calls++ {
+			n, err = randutil.Int(src, big.NewInt(1<<48-1))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	is.NoError(err)
+	is.NotNil(n)
+	// Each call reads 6 bytes; only 2^48-1 itself would be rejected.
+	is.Equal(uint64(calls*6), rdr.(StatsReporter).Stats().BytesGenerated)
+}
+
+// Test_PRNG_Int_Latency verifies that a held instance records one pool get per call and
+// times its reads when latency tracking is enabled.
+func Test_PRNG_Int_Latency(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	rdr, err := NewReader(WithLatencyTracking(1))
+	is.NoError(err)
+	r := rdr.(*reader)
+
+	const reads = 10
+	err = withHeld(r, func(src io.Reader) error {
+		for range reads {
+			if _, err := randutil.Int(src, big.NewInt(1<<48-1)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	is.NoError(err)
+
+	stats := r.Stats()
+	is.Equal(uint64(1), stats.Latency.PoolGets, "a held call should take one instance")
+	// Each read is 6 bytes, in the smallest size class; only 2^48-1 itself would be rejected.
+	is.Equal(uint64(reads), stats.Latency.Read[0].Count, "every read through the held instance should be timed")
+}
+
+// Test_PRNG_Prime verifies bit lengths, the top two bits, and primality against
+// big.Int.ProbablyPrime for a range of sizes.
+func Test_PRNG_Prime(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	for _, bits := range []int{2, 3, 7, 8, 9, 16, 17, 64, 127, 256, 512} {
+		for i := 0; i < 5; i++ {
+			p, err := Prime(bits)
+			is.NoError(err)
+			is.Equal(bits, p.BitLen(), "bits=%d", bits)
+			is.Equal(uint(1), p.Bit(bits-2), "second-highest bit should be set for bits=%d", bits)
+			is.True(p.ProbablyPrime(primeRounds), "%v should be prime", p)
+		}
+	}
+
+	p, err := Prime(1)
+	is.ErrorIs(err, ErrPrimeBitsTooSmall)
+	is.Nil(p)
+}
+
+// Test_PRNG_Prime_ReaderError verifies that a failing source is reported.
+func Test_PRNG_Prime_ReaderError(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	err := withHeld(bytes.NewReader(make([]byte, 10)), func(src io.Reader) error {
+		_, err := prime(src, 2048)
+		return err
+	})
+	is.True(errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF), "unexpected error: %v", err)
+}
+
+// Test_PRNG_Bytes verifies that Bytes preserves the source stream, batches small
+// reads, and can be used with crypto/rand.Int.
+func Test_PRNG_Bytes(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	src := make([]byte, 4096)
+	_, err := rand.Read(src)
+	is.NoError(err)
+
+	rc := &readCounter{r: bytes.NewReader(src)}
+	b := NewBytes(rc)
+	var got []byte
+	for len(got) < 1024 {
+		p := make([]byte, 3)
+		n, err := b.Read(p)
+		is.NoError(err)
+		is.Equal(3, n)
+		got = append(got, p...)
+	}
+	is.Equal(src[:len(got)], got)
+	is.Equal(5, rc.calls, "1026 bytes should take five 256-byte refills")
+
+	b.Wipe()
+	b.Reset(Reader)
+	n, err := rand.Int(b, big.NewInt(1_000_000))
+	is.NoError(err)
+	is.Equal(-1, n.Cmp(big.NewInt(1_000_000)))
+}
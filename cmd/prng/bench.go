@@ -0,0 +1,410 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package main
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"math/bits"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sixafter/prng-chacha"
+)
+
+// Benchmark sources.
+const (
+	sourcePRNG       = "prng"
+	sourceCryptoRand = "crypto/rand"
+)
+
+// benchResult is the measurement of one benchmark case. Latency quantiles are upper
+// estimates with about 6% resolution.
+type benchResult struct {
+	Source       string  `json:"source"`
+	Shards       int     `json:"shards,omitempty"`
+	Goroutines   int     `json:"goroutines"`
+	ReadSize     int     `json:"read_size"`
+	Reads        uint64  `json:"reads"`
+	Bytes        uint64  `json:"bytes"`
+	Seconds      float64 `json:"seconds"`
+	MiBPerSecond float64 `json:"mib_per_second"`
+	P50Seconds   float64 `json:"p50_seconds"`
+	P90Seconds   float64 `json:"p90_seconds"`
+	P99Seconds   float64 `json:"p99_seconds"`
+	P999Seconds  float64 `json:"p999_seconds"`
+	VsCryptoRand float64 `json:"vs_crypto_rand,omitempty"`
+}
+
+// benchConfig holds the parsed bench flags.
+type benchConfig struct {
+	sizes      []int
+	goroutines []int
+	shards     []int
+	duration   time.Duration
+	warmup     time.Duration
+	every      int
+	baseline   bool
+}
+
+// runBench measures read throughput and latency for every combination of --sizes,
+// --goroutines and --shard-counts, optionally alongside crypto/rand, and writes a
+// table or JSON.
+func runBench(args []string, stdout, stderr io.Writer) error {
+	fs, sf := newFlagSet("bench", "", stderr)
+	procs := runtime.GOMAXPROCS(0)
+	sizes := fs.String("sizes", "16,256,4K,64K", "comma-separated read sizes, with optional K, M or G suffixes")
+	goroutines := fs.String("goroutines", joinInts(distinctPositive(1, procs)), "comma-separated numbers of concurrent readers")
+	shardCounts := fs.String("shard-counts", joinInts(distinctPositive(1, procs/2, procs)), "comma-separated Shards values to compare (use --shards for a single value)")
+	duration := fs.Duration("duration", time.Second, "measurement time per case")
+	warmup := fs.Duration("warmup", 100*time.Millisecond, "unmeasured time per case to fill the pools")
+	every := fs.Int("latency-every", 16, "time one in N reads for latency percentiles")
+	baseline := fs.Bool("baseline", true, "also measure crypto/rand")
+	format := fs.String("format", "table", "output format: table or json")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) > 0 {
+		return usagef("unexpected argument %q", pos[0])
+	}
+
+	cfg := benchConfig{duration: *duration, warmup: *warmup, every: *every, baseline: *baseline}
+	if cfg.sizes, err = parseList(*sizes, "--sizes", parseSizeInt); err != nil {
+		return err
+	}
+	if cfg.goroutines, err = parseList(*goroutines, "--goroutines", strconv.Atoi); err != nil {
+		return err
+	}
+	if cfg.shards, err = benchShards(fs, sf, *shardCounts); err != nil {
+		return err
+	}
+	switch {
+	case cfg.duration <= 0:
+		return usagef("--duration must be positive")
+	case cfg.warmup < 0:
+		return usagef("--warmup must not be negative")
+	case cfg.every <= 0:
+		return usagef("--latency-every must be positive")
+	case *format != "table" && *format != "json":
+		return usagef("--format must be table or json")
+	case sf.seed != "":
+		return usagef("--seed is not supported: bench measures the pooled reader")
+	}
+	opts, err := sf.options(stderr)
+	if err != nil {
+		return err
+	}
+
+	results, err := bench(cfg, opts)
+	if err != nil {
+		return err
+	}
+	if *format == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+	return writeBenchTable(stdout, results)
+}
+
+// benchShards returns the Shards values to measure: --shard-counts, or the single
+// --shards value if only that was set.
+func benchShards(fs *flag.FlagSet, sf *sourceFlags, list string) ([]int, error) {
+	var shardsSet, listSet bool
+	fs.Visit(func(f *flag.Flag) {
+		shardsSet = shardsSet || f.Name == "shards"
+		listSet = listSet || f.Name == "shard-counts"
+	})
+	switch {
+	case shardsSet && listSet:
+		return nil, usagef("--shards cannot be combined with --shard-counts")
+	case shardsSet:
+		if sf.shards <= 0 {
+			return nil, usagef("--shards must be positive")
+		}
+		return []int{sf.shards}, nil
+	}
+	return parseList(list, "--shard-counts", strconv.Atoi)
+}
+
+// bench runs every case of cfg, creating one reader per Shards value with opts, and
+// returns the results with crypto/rand first for each size and goroutine count.
+func bench(cfg benchConfig, opts []prng.Option) ([]benchResult, error) {
+	readers := make([]io.Reader, len(cfg.shards))
+	for i, n := range cfg.shards {
+		r, err := prng.NewReader(append(slices.Clone(opts), prng.WithShards(n))...)
+		if err != nil {
+			return nil, err
+		}
+		readers[i] = r
+	}
+
+	var results []benchResult
+	for _, size := range cfg.sizes {
+		for _, g := range cfg.goroutines {
+			var base *benchResult
+			if cfg.baseline {
+				res, err := measure(rand.Reader, g, size, cfg)
+				if err != nil {
+					return nil, err
+				}
+				res.Source = sourceCryptoRand
+				results = append(results, res)
+				base = &results[len(results)-1]
+			}
+			for i, r := range readers {
+				res, err := measure(r, g, size, cfg)
+				if err != nil {
+					return nil, err
+				}
+				res.Source = sourcePRNG
+				res.Shards = cfg.shards[i]
+				if base != nil && base.MiBPerSecond > 0 {
+					res.VsCryptoRand = res.MiBPerSecond / base.MiBPerSecond
+				}
+				results = append(results, res)
+			}
+		}
+	}
+	return results, nil
+}
+
+// measure reads size-byte buffers from r on g goroutines for cfg.warmup, then for
+// cfg.duration, timing one in cfg.every reads of the measured phase.
+func measure(r io.Reader, g, size int, cfg benchConfig) (benchResult, error) {
+	if cfg.warmup > 0 {
+		if _, err := phase(r, g, size, cfg.warmup, 0); err != nil {
+			return benchResult{}, err
+		}
+	}
+	return phase(r, g, size, cfg.duration, cfg.every)
+}
+
+// phase reads from r on g goroutines for d. If every is positive, one in every reads
+// is timed.
+func phase(r io.Reader, g, size int, d time.Duration, every int) (benchResult, error) {
+	var (
+		stop     atomic.Bool
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		start    = make(chan struct{})
+		res      = benchResult{Goroutines: g, ReadSize: size}
+		latency  histogram
+	)
+
+	for i := 0; i < g; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			buf := make([]byte, size)
+			var (
+				h     histogram
+				reads uint64
+				err   error
+			)
+			<-start
+			for !stop.Load() {
+				if every > 0 && reads%uint64(every) == 0 {
+					t0 := time.Now()
+					_, err = io.ReadFull(r, buf)
+					h.observe(time.Since(t0))
+				} else {
+					_, err = io.ReadFull(r, buf)
+				}
+				if err != nil {
+					stop.Store(true)
+					break
+				}
+				reads++
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			res.Reads += reads
+			latency.merge(&h)
+		}()
+	}
+
+	t0 := time.Now()
+	timer := time.AfterFunc(d, func() { stop.Store(true) })
+	close(start)
+	wg.Wait()
+	timer.Stop()
+	elapsed := time.Since(t0)
+
+	if firstErr != nil {
+		return res, firstErr
+	}
+	res.Bytes = res.Reads * uint64(size)
+	res.Seconds = elapsed.Seconds()
+	res.MiBPerSecond = float64(res.Bytes) / (1 << 20) / res.Seconds
+	res.P50Seconds = latency.quantile(0.5).Seconds()
+	res.P90Seconds = latency.quantile(0.9).Seconds()
+	res.P99Seconds = latency.quantile(0.99).Seconds()
+	res.P999Seconds = latency.quantile(0.999).Seconds()
+	return res, nil
+}
+
+// writeBenchTable writes results as an aligned table.
+func writeBenchTable(w io.Writer, results []benchResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "SOURCE\tSHARDS\tGOROUTINES\tREAD SIZE\tMiB/s\tP50\tP90\tP99\tP99.9\tVS CRYPTO/RAND\t")
+	for _, r := range results {
+		shards, ratio := "-", "-"
+		if r.Source == sourcePRNG {
+			shards = strconv.Itoa(r.Shards)
+		}
+		if r.VsCryptoRand > 0 {
+			ratio = strconv.FormatFloat(r.VsCryptoRand, 'f', 2, 64) + "x"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%.1f\t%s\t%s\t%s\t%s\t%s\t\n",
+			r.Source, shards, r.Goroutines, formatSize(uint64(r.ReadSize)), r.MiBPerSecond,
+			formatLatency(r.P50Seconds), formatLatency(r.P90Seconds),
+			formatLatency(r.P99Seconds), formatLatency(r.P999Seconds), ratio)
+	}
+	return tw.Flush()
+}
+
+// formatLatency formats a latency in seconds with a precision suited to its magnitude.
+func formatLatency(s float64) string {
+	return time.Duration(s * float64(time.Second)).Round(time.Nanosecond).String()
+}
+
+// parseList parses a comma-separated list of positive values with parse, reporting
+// errors against the named flag.
+func parseList(s, name string, parse func(string) (int, error)) ([]int, error) {
+	var out []int
+	for _, field := range strings.Split(s, ",") {
+		n, err := parse(strings.TrimSpace(field))
+		if err != nil || n <= 0 {
+			return nil, usagef("%s: %q is not a positive value", name, field)
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
+
+// parseSizeInt parses a size with parseSize, rejecting values that do not fit in an
+// int.
+func parseSizeInt(s string) (int, error) {
+	n, err := parseSize(s)
+	if err != nil {
+		return 0, err
+	}
+	if n > uint64(maxReadSize) {
+		return 0, errors.New("size too large")
+	}
+	return int(n), nil
+}
+
+// maxReadSize bounds a benchmark read so that each goroutine's buffer stays reasonable.
+const maxReadSize = 1 << 30
+
+// distinctPositive returns the distinct positive values of ns in their original order.
+func distinctPositive(ns ...int) []int {
+	var out []int
+	for _, n := range ns {
+		if n > 0 && !slices.Contains(out, n) {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// joinInts formats ns as a comma-separated list.
+func joinInts(ns []int) string {
+	s := make([]string, len(ns))
+	for i, n := range ns {
+		s[i] = strconv.Itoa(n)
+	}
+	return strings.Join(s, ",")
+}
+
+// Latency histogram layout: values below 2*histSub nanoseconds have a bucket each,
+// larger values have histSub buckets per power of two, so a bucket is at most
+// 1/histSub (about 6%) wider than its lower bound.
+const (
+	histSubBits = 4
+	histSub     = 1 << histSubBits
+	histMaxExp  = 40
+	histBuckets = 2*histSub + histMaxExp*histSub
+)
+
+// histogram is a log-linear latency histogram. It is not safe for concurrent use;
+// each goroutine keeps its own and they are merged afterwards.
+type histogram struct {
+	counts [histBuckets]uint64
+	total  uint64
+}
+
+// histIndex returns the bucket of ns.
+func histIndex(ns uint64) int {
+	if ns < 2*histSub {
+		return int(ns)
+	}
+	e := bits.Len64(ns) - histSubBits - 1
+	if e > histMaxExp {
+		return histBuckets - 1
+	}
+	return 2*histSub + (e-1)*histSub + int(ns>>uint(e)) - histSub
+}
+
+// histUpper returns the exclusive upper bound, in nanoseconds, of bucket i.
+func histUpper(i int) uint64 {
+	if i < 2*histSub {
+		return uint64(i) + 1
+	}
+	e := (i-2*histSub)/histSub + 1
+	m := (i-2*histSub)%histSub + histSub
+	return uint64(m+1) << uint(e)
+}
+
+// observe records d.
+func (h *histogram) observe(d time.Duration) {
+	h.counts[histIndex(uint64(max(d, 0)))]++
+	h.total++
+}
+
+// merge adds the observations of o to h.
+func (h *histogram) merge(o *histogram) {
+	for i, c := range o.counts {
+		h.counts[i] += c
+	}
+	h.total += o.total
+}
+
+// quantile returns an upper estimate of the q-quantile: the upper bound of the bucket
+// containing it, or 0 if there are no observations.
+func (h *histogram) quantile(q float64) time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	rank := max(uint64(math.Ceil(q*float64(h.total))), 1)
+	var seen uint64
+	for i, c := range h.counts {
+		seen += c
+		if seen >= rank {
+			return time.Duration(histUpper(i))
+		}
+	}
+	return time.Duration(histUpper(histBuckets - 1))
+}
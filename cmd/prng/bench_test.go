@@ -0,0 +1,131 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBench_JSON verifies that every case is measured, with crypto/rand first for each
+// size and goroutine count and ratios against it.
+func TestBench_JSON(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	code, out, errOut := execute("bench", "--duration", "20ms", "--warmup", "5ms",
+		"--sizes", "32,1K", "--goroutines", "1,3", "--shard-counts", "1,2", "--format", "json")
+	is.Equal(exitOK, code, errOut)
+
+	var results []benchResult
+	is.NoError(json.Unmarshal([]byte(out), &results))
+	is.Len(results, 2*2*3)
+	for i, r := range results {
+		is.Positive(r.Reads)
+		is.Equal(r.Reads*uint64(r.ReadSize), r.Bytes)
+		is.Positive(r.MiBPerSecond)
+		is.Positive(r.P50Seconds)
+		is.LessOrEqual(r.P50Seconds, r.P90Seconds)
+		is.LessOrEqual(r.P90Seconds, r.P99Seconds)
+		is.LessOrEqual(r.P99Seconds, r.P999Seconds)
+		if i%3 == 0 {
+			is.Equal(sourceCryptoRand, r.Source)
+			is.Zero(r.Shards)
+			is.Zero(r.VsCryptoRand)
+		} else {
+			is.Equal(sourcePRNG, r.Source)
+			is.Equal(i%3, r.Shards)
+			is.InDelta(r.MiBPerSecond/results[i-i%3].MiBPerSecond, r.VsCryptoRand, 1e-9)
+		}
+	}
+	is.Equal([]int{32, 32, 32, 32, 32, 32, 1024, 1024, 1024, 1024, 1024, 1024},
+		func() []int {
+			var s []int
+			for _, r := range results {
+				s = append(s, r.ReadSize)
+			}
+			return s
+		}())
+}
+
+// TestBench_Table verifies the table layout without the crypto/rand baseline.
+func TestBench_Table(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	code, out, _ := execute("bench", "--duration", "10ms", "--warmup", "0", "--sizes", "64K",
+		"--goroutines", "2", "--shards", "3", "--baseline=false")
+	is.Equal(exitOK, code)
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	is.Len(lines, 2)
+	is.Equal([]string{"SOURCE", "SHARDS", "GOROUTINES", "READ", "SIZE", "MiB/s", "P50", "P90", "P99", "P99.9", "VS", "CRYPTO/RAND"},
+		strings.Fields(lines[0]))
+	fields := strings.Fields(lines[1])
+	is.Equal([]string{"prng", "3", "2", "64K"}, fields[:4])
+	is.Equal("-", fields[len(fields)-1])
+}
+
+// TestBench_Usage verifies flag validation.
+func TestBench_Usage(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	for _, args := range [][]string{
+		{"--format", "xml"},
+		{"--sizes", "0"},
+		{"--sizes", "2G"},
+		{"--goroutines", "1,x"},
+		{"--shard-counts", ""},
+		{"--shards", "2", "--shard-counts", "1,2"},
+		{"--duration", "0"},
+		{"--latency-every", "0"},
+		{"--seed", zeroSeed},
+		{"extra"},
+	} {
+		code, _, errOut := execute(append([]string{"bench"}, args...)...)
+		is.Equal(exitUsage, code, "%v: %s", args, errOut)
+	}
+}
+
+// TestHistogram verifies bucket bounds and quantiles.
+func TestHistogram(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	// Every value falls below its bucket's upper bound and at or above the previous one,
+	// and buckets are at most 1/histSub wider than their lower bound.
+	prev := -1
+	for _, ns := range []uint64{0, 1, 31, 32, 33, 34, 63, 64, 100, 1000, 12345, 1 << 20, 1<<40 + 7, 1 << 44} {
+		i := histIndex(ns)
+		is.Less(ns, histUpper(i), ns)
+		if i > 0 {
+			is.GreaterOrEqual(ns, histUpper(i-1), ns)
+			lower := histUpper(i - 1)
+			is.LessOrEqual(float64(histUpper(i)-lower), float64(max(lower, histSub))/histSub, ns)
+		}
+		is.GreaterOrEqual(i, prev)
+		prev = i
+	}
+	is.Equal(histBuckets-1, histIndex(1<<62))
+
+	var h, o histogram
+	is.Zero(h.quantile(0.5))
+	for i := 1; i <= 90; i++ {
+		h.observe(10 * time.Nanosecond)
+	}
+	for i := 1; i <= 10; i++ {
+		o.observe(time.Millisecond)
+	}
+	h.merge(&o)
+	is.Equal(11*time.Nanosecond, h.quantile(0.5))
+	is.Equal(11*time.Nanosecond, h.quantile(0.9))
+	is.InDelta(float64(time.Millisecond), float64(h.quantile(0.99)), float64(time.Millisecond)/histSub)
+	is.GreaterOrEqual(h.quantile(0.99), time.Millisecond)
+}
@@ -0,0 +1,212 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"io"
+	"strconv"
+
+	"github.com/sixafter/prng-chacha/token"
+	"github.com/sixafter/prng-chacha/uuid"
+)
+
+// streamBufferSize is the chunk size used by bytes: large enough that each Read and
+// Write amortizes its overhead, small enough to stay in cache.
+const streamBufferSize = 1 << 20
+
+// runBytes writes --size random bytes, or an unbounded stream if --size is not set.
+func runBytes(args []string, stdout, stderr io.Writer) error {
+	fs, sf := newFlagSet("bytes", "", stderr)
+	size := fs.String("size", "", "number of bytes to write, with an optional K, M, G or T suffix (default: unbounded)")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) > 0 {
+		return usagef("unexpected argument %q", pos[0])
+	}
+
+	limit, unbounded := uint64(0), true
+	if *size != "" {
+		if limit, err = parseSize(*size); err != nil {
+			return usagef("--size: %v", err)
+		}
+		unbounded = false
+	}
+	src, err := sf.source(stderr)
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, streamBufferSize)
+	for unbounded || limit > 0 {
+		chunk := buf
+		if !unbounded && limit < uint64(len(chunk)) {
+			chunk = chunk[:limit]
+		}
+		if _, err := io.ReadFull(src, chunk); err != nil {
+			return err
+		}
+		if _, err := stdout.Write(chunk); err != nil {
+			return err
+		}
+		limit -= uint64(len(chunk))
+	}
+	return nil
+}
+
+// runHex writes -n lines of N hex-encoded random bytes each.
+func runHex(args []string, stdout, stderr io.Writer) error {
+	fs, sf := newFlagSet("hex", "[bytes]", stderr)
+	count := fs.Int("n", 1, "number of values to write")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	size, err := sizeArg(pos, 32)
+	if err != nil {
+		return err
+	}
+	if *count < 0 {
+		return usagef("-n must not be negative")
+	}
+	src, err := sf.source(stderr)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(stdout)
+	raw := make([]byte, size)
+	line := make([]byte, hex.EncodedLen(size)+1)
+	line[len(line)-1] = '\n'
+	for i := 0; i < *count; i++ {
+		if _, err := io.ReadFull(src, raw); err != nil {
+			return err
+		}
+		hex.Encode(line, raw)
+		if _, err := w.Write(line); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// sizeArg returns the optional single positional byte count, or def if there is none.
+func sizeArg(pos []string, def int) (int, error) {
+	switch len(pos) {
+	case 0:
+		return def, nil
+	case 1:
+		n, err := strconv.Atoi(pos[0])
+		if err != nil || n <= 0 {
+			return 0, usagef("byte count must be a positive integer, got %q", pos[0])
+		}
+		return n, nil
+	default:
+		return 0, usagef("unexpected argument %q", pos[1])
+	}
+}
+
+// runUUID writes -n UUIDs of the requested version.
+func runUUID(args []string, stdout, stderr io.Writer) error {
+	fs, sf := newFlagSet("uuid", "", stderr)
+	count := fs.Int("n", 1, "number of UUIDs to write")
+	version := fs.Int("version", 4, "UUID version: 4 (random) or 7 (time-ordered)")
+	monotonic := fs.Bool("monotonic", false, "make version 7 UUIDs strictly increasing")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) > 0 {
+		return usagef("unexpected argument %q", pos[0])
+	}
+	if *count < 0 {
+		return usagef("-n must not be negative")
+	}
+	if *version != 4 && *version != 7 {
+		return usagef("--version must be 4 or 7")
+	}
+	src, err := sf.source(stderr)
+	if err != nil {
+		return err
+	}
+	g, err := uuid.NewGenerator(uuid.WithReader(src), uuid.WithMonotonic(*monotonic))
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(stdout)
+	var line []byte
+	for i := 0; i < *count; i++ {
+		var u uuid.UUID
+		if *version == 7 {
+			u, err = g.NewV7()
+		} else {
+			u, err = g.NewV4()
+		}
+		if err != nil {
+			return err
+		}
+		line, _ = u.AppendText(line[:0])
+		line = append(line, '\n')
+		if _, err := w.Write(line); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// runToken writes -n tokens drawn from --alphabet.
+func runToken(args []string, stdout, stderr io.Writer) error {
+	fs, sf := newFlagSet("token", "", stderr)
+	count := fs.Int("n", 1, "number of tokens to write")
+	alphabet := fs.String("alphabet", token.AlphabetAlphanumeric, "characters to draw from")
+	length := fs.Int("len", 0, "characters per token (default: the token package default)")
+	entropy := fs.Int("entropy", 0, "size tokens to at least this many bits of entropy instead of --len")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) > 0 {
+		return usagef("unexpected argument %q", pos[0])
+	}
+	if *count < 0 {
+		return usagef("-n must not be negative")
+	}
+	src, err := sf.source(stderr)
+	if err != nil {
+		return err
+	}
+
+	opts := []token.Option{token.WithAlphabet(*alphabet), token.WithReader(src)}
+	if *length != 0 {
+		opts = append(opts, token.WithLength(*length))
+	}
+	if *entropy != 0 {
+		opts = append(opts, token.WithEntropy(*entropy))
+	}
+	g, err := token.New(opts...)
+	if err != nil {
+		return usagef("%v", err)
+	}
+
+	w := bufio.NewWriter(stdout)
+	for i := 0; i < *count; i++ {
+		t, err := g.Generate()
+		if err != nil {
+			return err
+		}
+		if _, err := w.WriteString(t); err != nil {
+			return err
+		}
+		if err := w.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
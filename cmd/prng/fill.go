@@ -0,0 +1,121 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/sixafter/prng-chacha/fill"
+)
+
+// progressInterval is the minimum time between progress lines within a pass.
+const progressInterval = time.Second
+
+// runFill overwrites --path with random data --passes times.
+func runFill(args []string, stdout, stderr io.Writer) error {
+	fs, sf := newFlagSet("fill", "", stderr)
+	path := fs.String("path", "", "file or block device to overwrite (required)")
+	passes := fs.Int("passes", 1, "number of overwrite passes")
+	size := fs.String("size", "", "number of bytes to write, with an optional K, M, G or T suffix, creating or extending the file (default: the current length)")
+	sync := fs.Bool("sync", false, "flush the file to stable storage after each pass")
+	blockSize := fs.String("block-size", formatSize(fill.DefaultBufferSize), "size of each generation buffer, with an optional K, M or G suffix")
+	workers := fs.Int("workers", 0, "goroutines generating each buffer (default: the number of shards; 1 with --seed)")
+	progress := fs.Bool("progress", false, "report progress to standard error")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) > 0 {
+		return usagef("unexpected argument %q", pos[0])
+	}
+
+	switch {
+	case *path == "":
+		return usagef("--path is required")
+	case *passes <= 0:
+		return usagef("--passes must be positive")
+	case *workers < 0:
+		return usagef("--workers must not be negative")
+	}
+	var n uint64
+	if *size != "" {
+		if n, err = parseSize(*size); err != nil {
+			return usagef("--size: %v", err)
+		}
+		if n > math.MaxInt64 {
+			return usagef("--size: size %q is too large", *size)
+		}
+		if n == 0 {
+			return usagef("--size must be positive")
+		}
+	}
+	bs, err := parseSizeInt(*blockSize)
+	if err != nil || bs == 0 {
+		return usagef("--block-size must be a positive size up to 1G")
+	}
+	src, err := sf.source(stderr)
+	if err != nil {
+		return err
+	}
+
+	opts := []fill.Option{
+		fill.WithReader(src),
+		fill.WithPasses(*passes),
+		fill.WithSize(int64(n)),
+		fill.WithSync(*sync),
+		fill.WithBufferSize(bs),
+		fill.WithWorkers(*workers),
+	}
+	// A seeded stream is only reproducible when read sequentially.
+	if sf.seed != "" {
+		opts = append(opts, fill.WithWorkers(1))
+	}
+	if *progress {
+		opts = append(opts, fill.WithProgress(progressReporter(stderr)))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	return fill.File(ctx, *path, opts...)
+}
+
+// progressReporter returns a progress callback writing a line to w at the end of each
+// pass, and at most once per progressInterval within a pass.
+func progressReporter(w io.Writer) func(fill.Progress) {
+	start := time.Now()
+	last := start
+	return func(p fill.Progress) {
+		now := time.Now()
+		if !p.Done && now.Sub(last) < progressInterval {
+			return
+		}
+		last = now
+
+		pct := 100.0
+		if p.Size > 0 {
+			pct = 100 * float64(p.PassWritten) / float64(p.Size)
+		}
+		state := ""
+		if p.Done {
+			state = " done"
+		}
+		rate := float64(p.PassWritten) / (1 << 20)
+		if elapsed := now.Sub(start).Seconds(); elapsed > 0 {
+			rate /= elapsed
+		}
+		fmt.Fprintf(w, "pass %d/%d: %d/%d bytes (%.1f%%, %.1f MiB/s)%s\n",
+			p.Pass, p.Passes, p.PassWritten, p.Size, pct, rate, state)
+		if p.Done {
+			start = now
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sixafter/prng-chacha"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFill verifies overwriting a temp file in several passes with progress.
+func TestFill(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "disk.img")
+	is.NoError(os.WriteFile(path, make([]byte, 300000), 0o600))
+
+	code, out, errOut := execute("fill", "--path", path, "--passes", "3", "--sync", "--block-size", "64K", "--progress")
+	is.Equal(exitOK, code, errOut)
+	is.Empty(out)
+	got, err := os.ReadFile(path)
+	is.NoError(err)
+	is.Len(got, 300000)
+	is.False(bytes.Contains(got, make([]byte, 64)))
+	for pass := 1; pass <= 3; pass++ {
+		is.Contains(errOut, "pass "+strconv.Itoa(pass)+"/3: 300000/300000 bytes (100.0%")
+	}
+	is.Equal(3, strings.Count(errOut, " done\n"))
+}
+
+// TestFill_Seed verifies that a seeded fill writes the seeded stream in order.
+func TestFill_Seed(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "new.img")
+	code, _, errOut := execute("fill", "--path", path, "--size", "200K", "--block-size", "64K", "--seed", zeroSeed)
+	is.Equal(exitOK, code, errOut)
+	got, err := os.ReadFile(path)
+	is.NoError(err)
+
+	r, err := prng.NewSeededReader(make([]byte, prng.SeedSize))
+	is.NoError(err)
+	want := make([]byte, 200<<10)
+	_, err = io.ReadFull(r, want)
+	is.NoError(err)
+	is.Equal(want, got)
+}
+
+// TestFill_Usage verifies argument validation.
+func TestFill_Usage(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "f")
+	for _, args := range [][]string{
+		{"fill"},
+		{"fill", "--path", path, "--passes", "0"},
+		{"fill", "--path", path, "--size", "0"},
+		{"fill", "--path", path, "--size", "lots"},
+		{"fill", "--path", path, "--block-size", "0"},
+		{"fill", "--path", path, "--workers", "-1"},
+		{"fill", "--path", path, "extra"},
+	} {
+		code, _, _ := execute(args...)
+		is.Equal(exitUsage, code, args)
+	}
+
+	code, _, errOut := execute("fill", "--path", path)
+	is.Equal(exitError, code)
+	is.Contains(errOut, "no such file")
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+// Command prng writes random data generated by the prng package.
+//
+// Usage:
+//
+//	prng <command> [flags] [arguments]
+//
+// Commands:
+//
+//	bytes   write raw random bytes, unbounded by default, for piping into test
+//	        batteries such as PractRand (prng bytes | RNG_test stdin64)
+//	hex     write hex-encoded random bytes, one value per line
+//	uuid    write version 4 or version 7 UUIDs, one per line
+//	token   write random tokens over an alphabet, one per line
+//	bench   measure read throughput and latency percentiles across read sizes,
+//	        goroutine counts and Shards values, compared with crypto/rand
+//	fill    overwrite a file or disk image with random data, in one or more
+//	        passes (prng fill --path disk.img --passes 3 --sync)
+//
+// Every command accepts flags mapping to the reader options (--shards,
+// --max-bytes-per-key, --key-rotation, --profile and so on), or --seed for a
+// reproducible stream (except bench, which measures the pooled reader). Run
+// "prng <command> -h" for the flags of a command.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Exit codes.
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+// usageError is an error caused by invalid arguments, which exits with exitUsage.
+type usageError struct {
+	msg string
+}
+
+// Error implements the error interface.
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+// usagef returns a usageError with a formatted message.
+func usagef(format string, a ...any) error {
+	return &usageError{msg: fmt.Sprintf(format, a...)}
+}
+
+// command is a subcommand: it parses args and writes its output to stdout.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, stdout, stderr io.Writer) error
+}
+
+// commands lists the subcommands in the order shown by the usage text.
+var commands = []command{
+	{"bytes", "write raw random bytes", runBytes},
+	{"hex", "write hex-encoded random bytes", runHex},
+	{"uuid", "write UUIDs", runUUID},
+	{"token", "write random tokens over an alphabet", runToken},
+	{"bench", "measure throughput and latency against crypto/rand", runBench},
+	{"fill", "overwrite a file with random data", runFill},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command line args and returns the process exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitUsage
+	}
+	switch args[0] {
+	case "-h", "-help", "--help", "help":
+		usage(stdout)
+		return exitOK
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+		err := cmd.run(args[1:], stdout, stderr)
+		var uerr *usageError
+		switch {
+		case err == nil:
+			return exitOK
+		case errors.Is(err, flag.ErrHelp):
+			return exitOK
+		case errors.As(err, &uerr):
+			fmt.Fprintf(stderr, "prng %s: %v\n", cmd.name, err)
+			return exitUsage
+		default:
+			fmt.Fprintf(stderr, "prng %s: %v\n", cmd.name, err)
+			return exitError
+		}
+	}
+
+	fmt.Fprintf(stderr, "prng: unknown command %q\n", args[0])
+	usage(stderr)
+	return exitUsage
+}
+
+// usage writes the list of commands to w.
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: prng <command> [flags] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-8s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "prng <command> -h" for the flags of a command.`)
+}
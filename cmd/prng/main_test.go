@@ -0,0 +1,309 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sixafter/prng-chacha"
+	"github.com/sixafter/prng-chacha/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// zeroSeed is the all-zero seed, whose stream is the RFC 8439 section A.1 keystream.
+var zeroSeed = strings.Repeat("00", prng.SeedSize)
+
+// execute runs the command line and returns the exit code, stdout and stderr.
+func execute(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// TestBytes verifies sized and seeded output.
+func TestBytes(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	code, out, _ := execute("bytes", "--size", "3M")
+	is.Equal(exitOK, code)
+	is.Len(out, 3<<20)
+
+	code, out, _ = execute("bytes", "--size", "1000", "--seed", zeroSeed)
+	is.Equal(exitOK, code)
+	r, err := prng.NewSeededReader(make([]byte, prng.SeedSize))
+	is.NoError(err)
+	want := make([]byte, 1000)
+	_, err = io.ReadFull(r, want)
+	is.NoError(err)
+	is.Equal(string(want), out)
+
+	code, out, _ = execute("bytes", "--size", "0")
+	is.Equal(exitOK, code)
+	is.Empty(out)
+
+	code, _, errOut := execute("bytes", "--size", "lots")
+	is.Equal(exitUsage, code)
+	is.Contains(errOut, "invalid size")
+}
+
+// limitedWriter fails once more than n bytes have been written, like a closed pipe.
+type limitedWriter struct {
+	n int
+}
+
+func (w *limitedWriter) Write(b []byte) (int, error) {
+	if len(b) > w.n {
+		return 0, io.ErrClosedPipe
+	}
+	w.n -= len(b)
+	return len(b), nil
+}
+
+// TestBytes_Unbounded verifies that an unbounded stream runs until the writer fails.
+func TestBytes_Unbounded(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	var stderr bytes.Buffer
+	code := run([]string{"bytes"}, &limitedWriter{n: 5 * streamBufferSize}, &stderr)
+	is.Equal(exitError, code)
+	is.Contains(stderr.String(), io.ErrClosedPipe.Error())
+}
+
+// TestHex verifies line count, length and reproducibility.
+func TestHex(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	code, out, _ := execute("hex", "16", "-n", "3")
+	is.Equal(exitOK, code)
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	is.Len(lines, 3)
+	for _, l := range lines {
+		is.Len(l, 32)
+	}
+
+	_, out, _ = execute("hex", "--seed", zeroSeed)
+	is.Equal("76b8e0ada0f13d90405d6ae55386bd28bdd219b8a08ded1aa836efcc8b770dc7\n", out)
+
+	code, _, _ = execute("hex", "0")
+	is.Equal(exitUsage, code)
+	code, _, _ = execute("hex", "1", "2")
+	is.Equal(exitUsage, code)
+}
+
+// TestUUID verifies versions, counts and monotonic ordering.
+func TestUUID(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	code, out, _ := execute("uuid", "-n", "1000")
+	is.Equal(exitOK, code)
+	lines := strings.Fields(out)
+	is.Len(lines, 1000)
+	for _, l := range lines {
+		u, err := uuid.Parse(l)
+		is.NoError(err)
+		is.Equal(4, u.Version())
+	}
+
+	code, out, _ = execute("uuid", "-n", "500", "--version", "7", "--monotonic")
+	is.Equal(exitOK, code)
+	lines = strings.Fields(out)
+	is.Len(lines, 500)
+	is.True(slices.IsSorted(lines))
+	u, err := uuid.Parse(lines[0])
+	is.NoError(err)
+	is.Equal(7, u.Version())
+
+	code, _, _ = execute("uuid", "--version", "5")
+	is.Equal(exitUsage, code)
+}
+
+// TestToken verifies alphabet and length, and that invalid alphabets are usage errors.
+func TestToken(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	code, out, _ := execute("token", "--alphabet", "abc", "--len", "24", "-n", "5")
+	is.Equal(exitOK, code)
+	lines := strings.Fields(out)
+	is.Len(lines, 5)
+	for _, l := range lines {
+		is.Len(l, 24)
+		is.Empty(strings.Trim(l, "abc"))
+	}
+
+	_, out, _ = execute("token", "--alphabet", "01", "--entropy", "64")
+	is.Len(strings.TrimSpace(out), 64)
+
+	code, _, errOut := execute("token", "--alphabet", "a")
+	is.Equal(exitUsage, code)
+	is.Contains(errOut, "token:")
+}
+
+// TestSourceFlags verifies that each flag maps to its Option and that unset flags keep
+// the defaults.
+func TestSourceFlags(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	var stderr bytes.Buffer
+	fs, sf := newFlagSet("test", "", &stderr)
+	_, err := parse(fs, []string{
+		"--profile", "paranoid",
+		"--shards", "3",
+		"--max-bytes-per-key", "4M",
+		"--zero-buffer",
+		"--buffer-size", "256",
+		"--max-init-retries", "4",
+		"--max-rekey-attempts", "6",
+		"--rekey-backoff", "5ms",
+		"--max-rekey-backoff", "1s",
+		"--latency-sample-interval", "100",
+		"--health-tests",
+		"--self-test",
+	})
+	is.NoError(err)
+	opts, err := sf.options(&stderr)
+	is.NoError(err)
+
+	cfg := prng.DefaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	is.True(cfg.EnableKeyRotation, "from the profile")
+	is.Equal(3, cfg.Shards)
+	is.Equal(uint64(4<<20), cfg.MaxBytesPerKey, "flags override the profile")
+	is.True(cfg.UseZeroBuffer)
+	is.Equal(256, cfg.DefaultBufferSize)
+	is.Equal(4, cfg.MaxInitRetries)
+	is.Equal(6, cfg.MaxRekeyAttempts)
+	is.Equal(5*time.Millisecond, cfg.RekeyBackoff)
+	is.Equal(time.Second, cfg.MaxRekeyBackoff)
+	is.Equal(100, cfg.LatencySampleInterval)
+	is.True(cfg.HealthTests)
+	is.True(cfg.SelfTest)
+
+	// Every registered flag other than -h is a source flag.
+	fs.VisitAll(func(f *flag.Flag) {
+		is.True(isSourceFlag(f.Name), f.Name)
+	})
+
+	fs, sf = newFlagSet("test", "", &stderr)
+	_, err = parse(fs, nil)
+	is.NoError(err)
+	opts, err = sf.options(&stderr)
+	is.NoError(err)
+	is.Empty(opts, "unset flags must not override defaults")
+
+	fs, sf = newFlagSet("test", "", &stderr)
+	_, err = parse(fs, []string{"--profile", "fast"})
+	is.NoError(err)
+	_, err = sf.options(&stderr)
+	is.ErrorContains(err, "unknown profile")
+}
+
+// TestSeedConflicts verifies that --seed rejects reader configuration flags and
+// malformed seeds.
+func TestSeedConflicts(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	code, _, errOut := execute("hex", "--seed", zeroSeed, "--key-rotation")
+	is.Equal(exitUsage, code)
+	is.Contains(errOut, "--key-rotation cannot be combined with --seed")
+
+	code, _, _ = execute("hex", "--seed", "abcd")
+	is.Equal(exitUsage, code)
+}
+
+// TestParse verifies that flags are parsed around positional arguments and that every
+// argument after a "--" terminator is positional.
+func TestParse(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	for _, tc := range []struct {
+		args    []string
+		want    []string
+		verbose bool
+		name    string
+	}{
+		{[]string{"a", "-v", "b"}, []string{"a", "b"}, true, ""},
+		{[]string{"a", "--", "-v", "b"}, []string{"a", "-v", "b"}, false, ""},
+		{[]string{"--", "--name", "x"}, []string{"--name", "x"}, false, ""},
+		{[]string{"--", "a", "-v"}, []string{"a", "-v"}, false, ""},
+		{[]string{"x", "--", "a", "--name", "y"}, []string{"x", "a", "--name", "y"}, false, ""},
+		{[]string{"-v", "a", "--", "--"}, []string{"a", "--"}, true, ""},
+		{[]string{"--name", "--", "-v", "a"}, []string{"a"}, true, "--"},
+		{[]string{"-v", "--", "-v"}, []string{"-v"}, true, ""},
+	} {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		verbose := fs.Bool("v", false, "")
+		name := fs.String("name", "", "")
+
+		got, err := parse(fs, tc.args)
+		is.NoError(err, tc.args)
+		is.Equal(tc.want, got, tc.args)
+		is.Equal(tc.verbose, *verbose, tc.args)
+		is.Equal(tc.name, *name, tc.args)
+	}
+}
+
+// TestParseSize verifies suffix handling and overflow.
+func TestParseSize(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	for in, want := range map[string]uint64{
+		"0": 0, "4096": 4096, "64K": 64 << 10, "1m": 1 << 20, "1G": 1 << 30,
+		"2TiB": 2 << 40, "10KB": 10 << 10, "512B": 512, "8Ki": 8 << 10, "3mib": 3 << 20,
+	} {
+		got, err := parseSize(in)
+		is.NoError(err, in)
+		is.Equal(want, got, in)
+	}
+	for _, in := range []string{"", "-1", "1X", "K", "99999999999T", "5I", "5IB", "5iB", "KiB", "5KIi", "5BB", "5KBB"} {
+		_, err := parseSize(in)
+		is.Error(err, in)
+	}
+	is.Equal("1G", formatSize(1<<30))
+	is.Equal("1000", formatSize(1000))
+}
+
+// TestUsage verifies help, unknown commands and missing arguments.
+func TestUsage(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	code, out, _ := execute("help")
+	is.Equal(exitOK, code)
+	is.Contains(out, "bytes")
+
+	code, _, errOut := execute()
+	is.Equal(exitUsage, code)
+	is.Contains(errOut, "Usage")
+
+	code, _, errOut = execute("nope")
+	is.Equal(exitUsage, code)
+	is.Contains(errOut, `unknown command "nope"`)
+
+	code, _, errOut = execute("uuid", "-h")
+	is.Equal(exitOK, code)
+	is.Contains(errOut, "-monotonic")
+
+	code, _, _ = execute("uuid", "--bogus")
+	is.Equal(exitUsage, code)
+}
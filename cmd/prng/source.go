@@ -0,0 +1,262 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sixafter/prng-chacha"
+)
+
+// sourceFlags holds the flags shared by every command that select and configure the
+// random source.
+type sourceFlags struct {
+	fs *flag.FlagSet
+
+	profile               string
+	shards                int
+	maxBytesPerKey        string
+	keyRotation           bool
+	zeroBuffer            bool
+	bufferSize            int
+	maxInitRetries        int
+	maxRekeyAttempts      int
+	rekeyBackoff          time.Duration
+	maxRekeyBackoff       time.Duration
+	latencySampleInterval int
+	healthTests           bool
+	selfTest              bool
+	verbose               bool
+	seed                  string
+}
+
+// newFlagSet returns a flag set for the named command with the source flags registered.
+func newFlagSet(name, args string, stderr io.Writer) (*flag.FlagSet, *sourceFlags) {
+	fs := flag.NewFlagSet("prng "+name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: prng %s [flags] %s\n\nFlags:\n", name, args)
+		fs.PrintDefaults()
+	}
+
+	def := prng.DefaultConfig()
+	sf := &sourceFlags{fs: fs}
+	fs.StringVar(&sf.profile, "profile", "", "configuration profile applied before other flags: throughput, low-memory or paranoid")
+	fs.IntVar(&sf.shards, "shards", def.Shards, "number of pool shards")
+	fs.StringVar(&sf.maxBytesPerKey, "max-bytes-per-key", formatSize(def.MaxBytesPerKey), "output per key before rekeying, with an optional K, M, G or T suffix")
+	fs.BoolVar(&sf.keyRotation, "key-rotation", def.EnableKeyRotation, "rotate keys after --max-bytes-per-key bytes")
+	fs.BoolVar(&sf.zeroBuffer, "zero-buffer", def.UseZeroBuffer, "XOR the keystream into a zero buffer instead of in place")
+	fs.IntVar(&sf.bufferSize, "buffer-size", def.DefaultBufferSize, "initial zero buffer size in bytes")
+	fs.IntVar(&sf.maxInitRetries, "max-init-retries", def.MaxInitRetries, "instance initialization attempts")
+	fs.IntVar(&sf.maxRekeyAttempts, "max-rekey-attempts", def.MaxRekeyAttempts, "rekey attempts before keeping the current key")
+	fs.DurationVar(&sf.rekeyBackoff, "rekey-backoff", def.RekeyBackoff, "initial backoff between rekey attempts")
+	fs.DurationVar(&sf.maxRekeyBackoff, "max-rekey-backoff", def.MaxRekeyBackoff, "maximum backoff between rekey attempts")
+	fs.IntVar(&sf.latencySampleInterval, "latency-sample-interval", def.LatencySampleInterval, "time one in N reads (0 disables latency tracking)")
+	fs.BoolVar(&sf.healthTests, "health-tests", def.HealthTests, "run SP 800-90B health tests on seeding entropy")
+	fs.BoolVar(&sf.selfTest, "self-test", def.SelfTest, "run the power-on self-test before generating output")
+	fs.BoolVar(&sf.verbose, "verbose", false, "log reader diagnostics to standard error")
+	fs.StringVar(&sf.seed, "seed", "", "64 hex digits: write the reproducible ChaCha20 stream of this seed instead (not for secrets)")
+	return fs, sf
+}
+
+// parse parses args with fs, allowing flags both before and after positional
+// arguments, and returns the positional arguments. Every argument after a "--"
+// terminator is positional, even if it looks like a flag.
+func parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, usagef("%v", err)
+		}
+		if terminated(fs, args[:len(args)-fs.NArg()]) {
+			return append(positional, fs.Args()...), nil
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// terminated reports whether the arguments consumed by one fs.Parse call end with a "--"
+// terminator rather than with "--" given as the value of a flag.
+func terminated(fs *flag.FlagSet, consumed []string) bool {
+	n := len(consumed)
+	if n == 0 || consumed[n-1] != "--" {
+		return false
+	}
+	if n == 1 {
+		return true
+	}
+	prev := consumed[n-2]
+	if !strings.HasPrefix(prev, "-") || strings.Contains(prev, "=") {
+		return true
+	}
+	f := fs.Lookup(strings.TrimLeft(prev, "-"))
+	if f == nil {
+		return true
+	}
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// options returns the prng options for the flags that were set explicitly, with the
+// profile first so that individual flags override it.
+func (sf *sourceFlags) options(stderr io.Writer) ([]prng.Option, error) {
+	var opts []prng.Option
+	switch sf.profile {
+	case "":
+	case "throughput":
+		opts = append(opts, prng.ProfileThroughput())
+	case "low-memory":
+		opts = append(opts, prng.ProfileLowMemory())
+	case "paranoid":
+		opts = append(opts, prng.ProfileParanoid())
+	default:
+		return nil, usagef("unknown profile %q", sf.profile)
+	}
+
+	var err error
+	sf.fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "shards":
+			opts = append(opts, prng.WithShards(sf.shards))
+		case "max-bytes-per-key":
+			n, perr := parseSize(sf.maxBytesPerKey)
+			if perr != nil {
+				err = usagef("--max-bytes-per-key: %v", perr)
+			}
+			opts = append(opts, prng.WithMaxBytesPerKey(n))
+		case "key-rotation":
+			opts = append(opts, prng.WithEnableKeyRotation(sf.keyRotation))
+		case "zero-buffer":
+			opts = append(opts, prng.WithZeroBuffer(sf.zeroBuffer))
+		case "buffer-size":
+			opts = append(opts, prng.WithDefaultBufferSize(sf.bufferSize))
+		case "max-init-retries":
+			opts = append(opts, prng.WithMaxInitRetries(sf.maxInitRetries))
+		case "max-rekey-attempts":
+			opts = append(opts, prng.WithMaxRekeyAttempts(sf.maxRekeyAttempts))
+		case "rekey-backoff":
+			opts = append(opts, prng.WithRekeyBackoff(sf.rekeyBackoff))
+		case "max-rekey-backoff":
+			opts = append(opts, prng.WithMaxRekeyBackoff(sf.maxRekeyBackoff))
+		case "latency-sample-interval":
+			opts = append(opts, prng.WithLatencyTracking(sf.latencySampleInterval))
+		case "health-tests":
+			opts = append(opts, prng.WithHealthTests(sf.healthTests))
+		case "self-test":
+			opts = append(opts, prng.WithSelfTest(sf.selfTest))
+		case "verbose":
+			if sf.verbose {
+				logger := slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+				opts = append(opts, prng.WithLogger(logger))
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
+// source returns the reader selected by the flags: a seeded stream if --seed is set,
+// and otherwise a new prng reader configured by the other flags.
+func (sf *sourceFlags) source(stderr io.Writer) (io.Reader, error) {
+	if sf.seed == "" {
+		opts, err := sf.options(stderr)
+		if err != nil {
+			return nil, err
+		}
+		return prng.NewReader(opts...)
+	}
+
+	var conflict string
+	sf.fs.Visit(func(f *flag.Flag) {
+		if conflict == "" && isSourceFlag(f.Name) && f.Name != "seed" {
+			conflict = f.Name
+		}
+	})
+	if conflict != "" {
+		return nil, usagef("--%s cannot be combined with --seed", conflict)
+	}
+	seed, err := hex.DecodeString(sf.seed)
+	if err != nil || len(seed) != prng.SeedSize {
+		return nil, usagef("--seed must be %d hex digits", 2*prng.SeedSize)
+	}
+	return prng.NewSeededReader(seed)
+}
+
+// isSourceFlag reports whether name is one of the flags registered by newFlagSet.
+func isSourceFlag(name string) bool {
+	switch name {
+	case "profile", "shards", "max-bytes-per-key", "key-rotation", "zero-buffer", "buffer-size",
+		"max-init-retries", "max-rekey-attempts", "rekey-backoff", "max-rekey-backoff",
+		"latency-sample-interval", "health-tests", "self-test", "verbose", "seed":
+		return true
+	}
+	return false
+}
+
+// sizeSuffixes maps size suffixes to binary multiples.
+var sizeSuffixes = []struct {
+	suffix string
+	shift  uint
+}{
+	{"T", 40},
+	{"G", 30},
+	{"M", 20},
+	{"K", 10},
+}
+
+// parseSize parses a byte count such as 4096, 64K, 1M, 1G or 2TiB; suffixes are binary
+// multiples and case-insensitive, and may be followed by "i", "B" or "iB". A plain count
+// may be followed by "B".
+func parseSize(s string) (uint64, error) {
+	t := strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(s)), "B")
+	var shift uint
+	for _, sfx := range sizeSuffixes {
+		// The "i" of KiB, MiB and so on is only valid after a multiple suffix.
+		if rest, ok := strings.CutSuffix(t, sfx.suffix+"I"); ok {
+			t, shift = rest, sfx.shift
+			break
+		}
+		if rest, ok := strings.CutSuffix(t, sfx.suffix); ok {
+			t, shift = rest, sfx.shift
+			break
+		}
+	}
+	n, err := strconv.ParseUint(t, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	if shift > 0 && n > (1<<64-1)>>shift {
+		return 0, fmt.Errorf("size %q is too large", s)
+	}
+	return n << shift, nil
+}
+
+// formatSize formats n with the largest binary suffix that divides it exactly.
+func formatSize(n uint64) string {
+	for _, sfx := range sizeSuffixes {
+		if n != 0 && n%(1<<sfx.shift) == 0 {
+			return strconv.FormatUint(n>>sfx.shift, 10) + sfx.suffix
+		}
+	}
+	return strconv.FormatUint(n, 10)
+}
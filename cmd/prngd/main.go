@@ -0,0 +1,152 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+// Command prngd serves random bytes from a prng reader over the Entropy Gathering
+// Daemon (EGD) protocol on a Unix domain socket, for legacy services and tools that read
+// entropy from an EGD socket.
+//
+// Usage:
+//
+//	prngd [flags]
+//
+// The daemon runs until interrupted (SIGINT or SIGTERM) and removes its socket on exit.
+// Run "prngd -h" for the flags.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/sixafter/prng-chacha"
+	"github.com/sixafter/prng-chacha/egd"
+)
+
+// Exit codes.
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], os.Stderr))
+}
+
+// run serves until ctx is done and returns the process exit code.
+func run(ctx context.Context, args []string, stderr io.Writer) int {
+	fs := flag.NewFlagSet("prngd", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	socket := fs.String("socket", filepath.Join(os.TempDir(), "egd-pool"), "path of the Unix domain socket")
+	mode := fs.String("mode", "0600", "permission of the socket file, in octal")
+	rate := fs.Int("rate", 0, "bytes per second each connection may read (0 disables rate limiting)")
+	burst := fs.Int("burst", 64<<10, "bytes a rate-limited connection may read at once")
+	maxConns := fs.Int("max-conns", 256, "concurrent connections served (0 is unlimited)")
+	idle := fs.Duration("idle-timeout", 5*time.Minute, "close connections idle for this long (0 disables)")
+	profile := fs.String("profile", "", "reader configuration profile: throughput, low-memory or paranoid")
+	healthTests := fs.Bool("health-tests", false, "run SP 800-90B health tests on seeding entropy (the paranoid profile enables them)")
+	selfTest := fs.Bool("self-test", true, "run the power-on self-test before serving")
+	verbose := fs.Bool("verbose", false, "log connection and reader diagnostics")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "prngd: unexpected argument %q\n", fs.Arg(0))
+		return exitUsage
+	}
+
+	perm, err := strconv.ParseUint(*mode, 8, 32)
+	if err != nil || perm > 0o777 {
+		fmt.Fprintf(stderr, "prngd: invalid --mode %q\n", *mode)
+		return exitUsage
+	}
+	opts, err := readerOptions(fs, *profile, *healthTests, *selfTest)
+	if err != nil {
+		fmt.Fprintf(stderr, "prngd: %v\n", err)
+		return exitUsage
+	}
+
+	level := slog.LevelInfo
+	if *verbose {
+		level = slog.LevelDebug
+	}
+	logger := slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: level}))
+	if *verbose {
+		opts = append(opts, prng.WithLogger(logger))
+	}
+
+	r, err := prng.NewReader(opts...)
+	if err != nil {
+		fmt.Fprintf(stderr, "prngd: %v\n", err)
+		return exitError
+	}
+	srv, err := egd.NewServer(
+		egd.WithReader(r),
+		egd.WithRateLimit(*rate, *burst),
+		egd.WithMaxConnections(*maxConns),
+		egd.WithIdleTimeout(*idle),
+		egd.WithSocketMode(os.FileMode(perm)),
+		egd.WithLogger(logger),
+	)
+	if err != nil {
+		fmt.Fprintf(stderr, "prngd: %v\n", err)
+		return exitUsage
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- srv.ListenAndServe(*socket) }()
+	logger.Info("prngd: serving", slog.String("socket", *socket), slog.Int("rate", *rate))
+
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		logger.Info("prngd: shutting down")
+		_ = srv.Close()
+		err = <-done
+	}
+	if err != nil && !errors.Is(err, egd.ErrServerClosed) {
+		fmt.Fprintf(stderr, "prngd: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// readerOptions returns the prng options for the --profile, --health-tests and
+// --self-test flags of fs, with the profile first. --health-tests only overrides the
+// profile when it is set explicitly, so that the paranoid profile keeps its health tests.
+func readerOptions(fs *flag.FlagSet, profile string, healthTests, selfTest bool) ([]prng.Option, error) {
+	var opts []prng.Option
+	switch profile {
+	case "":
+	case "throughput":
+		opts = append(opts, prng.ProfileThroughput())
+	case "low-memory":
+		opts = append(opts, prng.ProfileLowMemory())
+	case "paranoid":
+		opts = append(opts, prng.ProfileParanoid())
+	default:
+		return nil, fmt.Errorf("unknown profile %q", profile)
+	}
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "health-tests" {
+			opts = append(opts, prng.WithHealthTests(healthTests))
+		}
+	})
+	return append(opts, prng.WithSelfTest(selfTest)), nil
+}
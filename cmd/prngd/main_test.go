@@ -0,0 +1,140 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sixafter/prng-chacha"
+	"github.com/sixafter/prng-chacha/egd"
+	"github.com/stretchr/testify/assert"
+)
+
+// syncBuffer is a bytes.Buffer safe for the daemon's logger and the test to share.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// TestRun serves a rate-limited socket, reads from it and shuts down on cancellation.
+func TestRun(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "prngd")
+	is.NoError(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "egd")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var stderr syncBuffer
+	exit := make(chan int, 1)
+	go func() {
+		exit <- run(ctx, []string{"--socket", path, "--mode", "0640", "--rate", "1000", "--burst", "512", "--profile", "paranoid"}, &stderr)
+	}()
+
+	var c *egd.Client
+	is.Eventually(func() bool {
+		c, err = egd.Dial(path)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	defer c.Close()
+
+	bits, err := c.EntropyCount()
+	is.NoError(err)
+	is.Equal(uint32(8*512), bits)
+	n, err := c.Read(make([]byte, 300))
+	is.NoError(err)
+	is.Equal(300, n)
+	fi, err := os.Stat(path)
+	is.NoError(err)
+	is.Equal(os.FileMode(0o640), fi.Mode().Perm())
+
+	cancel()
+	is.Equal(exitOK, <-exit, stderr.String())
+	is.Contains(stderr.String(), "shutting down")
+	_, err = os.Lstat(path)
+	is.True(os.IsNotExist(err))
+}
+
+// TestRun_Usage verifies flag validation.
+func TestRun_Usage(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	for _, args := range [][]string{
+		{"--mode", "999"},
+		{"--mode", "01000"},
+		{"--profile", "fast"},
+		{"--rate", "100", "--burst", "10"},
+		{"--rate", "-1"},
+		{"--bogus"},
+		{"extra"},
+	} {
+		var stderr bytes.Buffer
+		is.Equal(exitUsage, run(context.Background(), args, &stderr), "%v", args)
+	}
+
+	var stderr bytes.Buffer
+	is.Equal(exitOK, run(context.Background(), []string{"-h"}, &stderr))
+	is.Contains(stderr.String(), "-socket")
+
+	// A socket in a missing directory cannot be created.
+	is.Equal(exitError, run(context.Background(), []string{"--socket", "/nonexistent/dir/egd"}, &stderr))
+}
+
+// TestReaderOptions verifies that the paranoid profile keeps its health tests unless
+// --health-tests is set explicitly.
+func TestReaderOptions(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	for _, tc := range []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"--profile", "paranoid"}, true},
+		{[]string{"--profile", "paranoid", "--health-tests=false"}, false},
+		{[]string{}, false},
+		{[]string{"--health-tests"}, true},
+	} {
+		fs := flag.NewFlagSet("prngd", flag.ContinueOnError)
+		profile := fs.String("profile", "", "")
+		healthTests := fs.Bool("health-tests", false, "")
+		is.NoError(fs.Parse(tc.args))
+
+		opts, err := readerOptions(fs, *profile, *healthTests, true)
+		is.NoError(err)
+		cfg := prng.DefaultConfig()
+		for _, opt := range opts {
+			opt(&cfg)
+		}
+		is.Equal(tc.want, cfg.HealthTests, "%v", tc.args)
+		is.True(cfg.SelfTest, "%v", tc.args)
+	}
+
+	_, err := readerOptions(flag.NewFlagSet("prngd", flag.ContinueOnError), "fast", false, true)
+	is.Error(err)
+}
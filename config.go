@@ -12,6 +12,7 @@
 package prng
 
 import (
+	"log/slog"
 	"runtime"
 	"time"
 )
@@ -28,8 +29,15 @@ import (
 //   - MaxRekeyBackoff: Maximum backoff duration for exponential rekey retries.
 //   - RekeyBackoff: Initial backoff for rekey attempts.
 //   - EnableKeyRotation: Whether to enable automatic key rotation (default: false).
+//   - FailClosed: Whether Read fails rather than exceed MaxBytesPerKey under one key.
 //   - UseZeroBuffer: Whether to use a zero-filled buffer for ChaCha20 XORKeyStream.
 //   - DefaultBufferSize: Initial internal buffer size for zero buffer operations.
+//   - Shards: Number of independent pools used to spread concurrent load.
+//   - Observer: Optional receiver of lifecycle notifications.
+//   - Logger: Optional structured logger for diagnostics.
+//   - LatencySampleInterval: Opt-in Read latency sampling (0 disables).
+//   - HealthTests: Opt-in SP 800-90B continuous health tests on seeding entropy.
+//   - SelfTest: Opt-in power-on self-test in NewReader.
 type Config struct {
 	// MaxBytesPerKey is the maximum number of bytes generated per key/nonce before triggering automatic rekeying.
 	//
@@ -43,7 +51,8 @@ type Config struct {
 	// If set to zero, a default of 3 is used.
 	MaxInitRetries int
 
-	// MaxRekeyAttempts specifies the number of attempts to perform asynchronous rekeying.
+	// MaxRekeyAttempts specifies the number of attempts to perform rekeying, asynchronously
+	// or, with FailClosed, in the Read that exhausts a key.
 	//
 	// On failure, exponential backoff is used between attempts. If zero, a default of 5 is used.
 	MaxRekeyAttempts int
@@ -65,6 +74,17 @@ type Config struct {
 	// Defaults to false for performance.
 	EnableKeyRotation bool
 
+	// FailClosed makes key rotation a hard limit rather than best effort. It only has an
+	// effect when EnableKeyRotation is set.
+	//
+	// By default a key that reaches MaxBytesPerKey keeps producing output while a new key is
+	// prepared in the background, and remains in use if every rekey attempt fails. With
+	// FailClosed, no key ever produces more than MaxBytesPerKey bytes: the Read that
+	// exhausts a key rekeys synchronously (retrying up to MaxRekeyAttempts times with
+	// backoff), and if every attempt fails it returns the bytes generated so far with an
+	// error wrapping ErrRekeyFailed. Later Reads retry the rekey. Defaults to false.
+	FailClosed bool
+
 	// UseZeroBuffer determines whether each Read operation uses a zero-filled buffer for ChaCha20's XORKeyStream.
 	//
 	// If true, Read uses an internal buffer of zeroes for output; if false, in-place XOR is used (faster).
@@ -81,6 +101,44 @@ type Config struct {
 	// If zero, defaults to runtime.GOMAXPROCS(0).
 	// Increase this to improve throughput under high concurrency.
 	Shards int
+
+	// Observer receives lifecycle notifications such as key rotations, rekey failures and
+	// PRNG instance creation.
+	//
+	// Callbacks never receive key material and run synchronously, some inside Read, so
+	// they must not block (see Observer). If nil, no notifications are delivered.
+	Observer Observer
+
+	// Logger receives structured diagnostic records for reader initialization, pool instance
+	// creation and rekeying (shard index, attempt, backoff delay, bytes emitted under the
+	// current key, and errors).
+	//
+	// Records never contain key material and are not emitted per Read: the only records
+	// emitted by Read are the one reporting a key rotation, by the Read that installs it,
+	// and, with FailClosed, those of the synchronous rekey that precedes it.
+	// If nil, logging is disabled and no logging work is performed.
+	Logger *slog.Logger
+
+	// LatencySampleInterval enables latency tracking and sets how often Read is timed.
+	//
+	// Zero disables tracking (the default). One times every Read; N times roughly one
+	// in N reads, chosen at random. While enabled, rekey durations, rekey backoff time and
+	// pool hit/miss counts are also recorded. Results are reported in Stats.Latency.
+	LatencySampleInterval int
+
+	// HealthTests enables the NIST SP 800-90B Repetition Count and Adaptive Proportion
+	// tests on every byte of entropy read to seed keys and nonces.
+	//
+	// A stuck or badly degraded source then fails the seeding with a *HealthTestError
+	// instead of producing weak keys: NewReader returns the error, and failed reseeds are
+	// reported to the Observer and retried like any other rekey failure. Defaults to false.
+	HealthTests bool
+
+	// SelfTest makes NewReader run SelfTest before building any pool, returning an error
+	// wrapping ErrSelfTestFailed if a known-answer or output path check fails.
+	//
+	// It only affects construction; changing it with Update has no effect. Defaults to false.
+	SelfTest bool
 }
 
 // Default configuration constants for ChaCha20-PRNG.
@@ -137,6 +195,7 @@ const (
 //   - MaxRekeyBackoff: 2 seconds
 //   - RekeyBackoff: 100 milliseconds
 //   - EnableKeyRotation: false
+//   - FailClosed: false
 //   - UseZeroBuffer: false
 //   - DefaultBufferSize: 64
 //
@@ -225,6 +284,15 @@ func WithEnableKeyRotation(enable bool) Option {
 	}
 }
 
+// WithFailClosed returns an Option that makes Read fail with ErrRekeyFailed rather than
+// exceed MaxBytesPerKey under one key when key rotation cannot complete.
+// See Config.FailClosed.
+func WithFailClosed(enable bool) Option {
+	return func(cfg *Config) {
+		cfg.FailClosed = enable
+	}
+}
+
 // WithZeroBuffer returns an Option that enables or disables use of a zero-filled buffer for XORKeyStream.
 //
 // Enable only if required for legacy compatibility.
@@ -243,6 +311,56 @@ func WithDefaultBufferSize(n int) Option {
 	}
 }
 
+// WithObserver returns an Option that registers an Observer for lifecycle notifications.
+//
+// Pass nil to disable notifications.
+func WithObserver(o Observer) Option {
+	return func(cfg *Config) {
+		cfg.Observer = o
+	}
+}
+
+// WithLogger returns an Option that sets the structured logger used for diagnostics.
+//
+// Debug records cover initialization and successful rotations, warnings cover individual
+// failed attempts, and errors cover exhausted retries. Pass nil to disable logging.
+func WithLogger(l *slog.Logger) Option {
+	return func(cfg *Config) {
+		cfg.Logger = l
+	}
+}
+
+// WithLatencyTracking returns an Option that enables latency tracking, timing roughly one
+// in sampleInterval Read calls (every call if sampleInterval is 1).
+//
+// Histograms use lock-free atomic buckets, so tracking can stay enabled in production;
+// a larger interval further reduces the cost of timing. Pass 0 to disable tracking.
+func WithLatencyTracking(sampleInterval int) Option {
+	return func(cfg *Config) {
+		cfg.LatencySampleInterval = sampleInterval
+	}
+}
+
+// WithHealthTests returns an Option that enables or disables continuous health tests on
+// the entropy used for keys and nonces.
+//
+// Enable in deployments that must detect entropy source failures, such as FIPS-style
+// environments; the tests only run when a cipher is seeded, never on Read.
+func WithHealthTests(enable bool) Option {
+	return func(cfg *Config) {
+		cfg.HealthTests = enable
+	}
+}
+
+// WithSelfTest returns an Option that makes NewReader run the power-on self-test.
+//
+// Enable in FIPS-style deployments that require the generator to verify itself before use.
+func WithSelfTest(enable bool) Option {
+	return func(cfg *Config) {
+		cfg.SelfTest = enable
+	}
+}
+
 // WithShards sets the number of independent sync.Pool shards to use.
 // By default, a single shard is used. Sharding may reduce contention
 // under high concurrency but can increase overhead on most systems.
@@ -258,3 +376,99 @@ func WithShards(n int) Option {
 		cfg.Shards = n
 	}
 }
+
+// Profile constants used by the predefined configuration profiles.
+const (
+	// throughputShardFactor multiplies runtime.GOMAXPROCS(0) to size the shard count
+	// used by ProfileThroughput.
+	throughputShardFactor = 2
+
+	// throughputBufferSize is the zero buffer capacity used by ProfileThroughput.
+	//
+	// The zero buffer is disabled by the profile, so this value only matters if a later
+	// option re-enables it. It is sized to cover typical bulk reads without regrowth.
+	throughputBufferSize = 4096
+
+	// paranoidMaxBytesPerKey is the per-key output budget used by ProfileParanoid (1 MiB).
+	paranoidMaxBytesPerKey = 1 << 20
+
+	// paranoidMaxRekeyAttempts is the number of rekey attempts used by ProfileParanoid.
+	paranoidMaxRekeyAttempts = 10
+
+	// paranoidRekeyBackoff is the initial rekey backoff used by ProfileParanoid.
+	paranoidRekeyBackoff = 10 * time.Millisecond
+
+	// paranoidMaxRekeyBackoff is the maximum rekey backoff used by ProfileParanoid.
+	paranoidMaxRekeyBackoff = 500 * time.Millisecond
+)
+
+// ProfileThroughput returns an Option that tunes a Config for maximum throughput under
+// heavy concurrency.
+//
+// Settings:
+//   - Shards: 2 × runtime.GOMAXPROCS(0), spreading contention over more pools
+//   - UseZeroBuffer: false (in-place XOR, the fastest path)
+//   - DefaultBufferSize: 4096
+//   - EnableKeyRotation: false
+//
+// Profiles overwrite only the fields listed above. Options applied after a profile
+// override its settings, so a profile can be used as a starting point:
+//
+//	r, err := prng.NewReader(
+//	    prng.ProfileThroughput(),
+//	    prng.WithEnableKeyRotation(true),
+//	)
+func ProfileThroughput() Option {
+	return func(cfg *Config) {
+		cfg.Shards = runtime.GOMAXPROCS(0) * throughputShardFactor
+		cfg.UseZeroBuffer = false
+		cfg.DefaultBufferSize = throughputBufferSize
+		cfg.EnableKeyRotation = false
+	}
+}
+
+// ProfileLowMemory returns an Option that tunes a Config for a minimal memory footprint.
+//
+// Settings:
+//   - Shards: 1, keeping a single pool of PRNG instances
+//   - UseZeroBuffer: false (in-place XOR needs no scratch buffer)
+//   - DefaultBufferSize: 0, so no buffer is preallocated per instance
+//
+// Concurrent throughput is lower than with the default configuration because all
+// goroutines share one pool.
+func ProfileLowMemory() Option {
+	return func(cfg *Config) {
+		cfg.Shards = 1
+		cfg.UseZeroBuffer = false
+		cfg.DefaultBufferSize = 0
+	}
+}
+
+// ProfileParanoid returns an Option that favors forward secrecy and fail-closed behavior
+// over raw throughput and availability.
+//
+// Settings:
+//   - EnableKeyRotation: true
+//   - FailClosed: true, so Read returns an error rather than exceed a key's budget
+//   - MaxBytesPerKey: 1 MiB, so keys are replaced frequently
+//   - MaxRekeyAttempts: 10, retrying harder before failing
+//   - RekeyBackoff: 10 milliseconds
+//   - MaxRekeyBackoff: 500 milliseconds
+//   - HealthTests: true, so a degraded entropy source fails seeding
+//   - SelfTest: true, so NewReader fails if the cipher does not match its known answers
+//
+// The previous cipher state is always wiped after a successful rotation. Rotations happen
+// synchronously in the Read that exhausts a key, so that Read may block for up to the
+// total rekey backoff while the entropy source is failing.
+func ProfileParanoid() Option {
+	return func(cfg *Config) {
+		cfg.EnableKeyRotation = true
+		cfg.FailClosed = true
+		cfg.HealthTests = true
+		cfg.SelfTest = true
+		cfg.MaxBytesPerKey = paranoidMaxBytesPerKey
+		cfg.MaxRekeyAttempts = paranoidMaxRekeyAttempts
+		cfg.RekeyBackoff = paranoidRekeyBackoff
+		cfg.MaxRekeyBackoff = paranoidMaxRekeyBackoff
+	}
+}
@@ -11,6 +11,8 @@
 package prng
 
 import (
+	"io"
+	"log/slog"
 	"runtime"
 	"testing"
 	"time"
@@ -136,6 +138,19 @@ func TestConfig_WithEnableKeyRotation(t *testing.T) {
 	is.Equal(uint64(1<<30), cfg.MaxBytesPerKey)
 }
 
+// TestConfig_WithFailClosed validates that WithFailClosed sets and clears FailClosed.
+func TestConfig_WithFailClosed(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	cfg := DefaultConfig()
+	is.False(cfg.FailClosed, "FailClosed should default to false")
+	WithFailClosed(true)(&cfg)
+	is.True(cfg.FailClosed, "WithFailClosed(true) should set FailClosed to true")
+	WithFailClosed(false)(&cfg)
+	is.False(cfg.FailClosed, "WithFailClosed(false) should set FailClosed to false")
+}
+
 // TestConfig_WithDefaultBufferSize ensures that the WithDefaultBufferSize
 // option modifies only the DefaultBufferSize field, and does not affect UseZeroBuffer.
 func TestConfig_WithDefaultBufferSize(t *testing.T) {
@@ -195,6 +210,20 @@ func TestConfig_WithShards_Normalization(t *testing.T) {
 	is.Equal(8, cfg.Shards, "WithShards(8) should preserve explicit positive value")
 }
 
+// TestConfig_WithLogger ensures that WithLogger sets and clears the Logger field.
+func TestConfig_WithLogger(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := DefaultConfig()
+	is.Nil(cfg.Logger, "DefaultConfig should not set a Logger")
+	WithLogger(l)(&cfg)
+	is.Same(l, cfg.Logger, "WithLogger should set Logger")
+	WithLogger(nil)(&cfg)
+	is.Nil(cfg.Logger, "WithLogger(nil) should disable logging")
+}
+
 // TestConfig_AllOptions verifies that all option functions can be composed
 // and applied together, each updating their corresponding field in the Config struct.
 func TestConfig_AllOptions(t *testing.T) {
@@ -225,3 +254,74 @@ func TestConfig_AllOptions(t *testing.T) {
 	is.Equal(321, cfg.DefaultBufferSize)
 	is.Equal(1234*time.Millisecond, cfg.MaxRekeyBackoff)
 }
+
+// TestConfig_ProfileThroughput verifies the Config produced by the ProfileThroughput option.
+func TestConfig_ProfileThroughput(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	want := DefaultConfig()
+	want.Shards = runtime.GOMAXPROCS(0) * 2
+	want.UseZeroBuffer = false
+	want.DefaultBufferSize = 4096
+	want.EnableKeyRotation = false
+
+	cfg := DefaultConfig()
+	ProfileThroughput()(&cfg)
+	is.Equal(want, cfg, "ProfileThroughput should produce the documented Config")
+}
+
+// TestConfig_ProfileLowMemory verifies the Config produced by the ProfileLowMemory option.
+func TestConfig_ProfileLowMemory(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	want := DefaultConfig()
+	want.Shards = 1
+	want.UseZeroBuffer = false
+	want.DefaultBufferSize = 0
+
+	cfg := DefaultConfig()
+	ProfileLowMemory()(&cfg)
+	is.Equal(want, cfg, "ProfileLowMemory should produce the documented Config")
+}
+
+// TestConfig_ProfileParanoid verifies the Config produced by the ProfileParanoid option.
+func TestConfig_ProfileParanoid(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	want := DefaultConfig()
+	want.EnableKeyRotation = true
+	want.FailClosed = true
+	want.HealthTests = true
+	want.SelfTest = true
+	want.MaxBytesPerKey = 1 << 20
+	want.MaxRekeyAttempts = 10
+	want.RekeyBackoff = 10 * time.Millisecond
+	want.MaxRekeyBackoff = 500 * time.Millisecond
+
+	cfg := DefaultConfig()
+	ProfileParanoid()(&cfg)
+	is.Equal(want, cfg, "ProfileParanoid should produce the documented Config")
+}
+
+// TestConfig_ProfileOverride ensures options applied after a profile take precedence
+// and that every profile yields a Config accepted by NewReader.
+func TestConfig_ProfileOverride(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	r, err := NewReader(ProfileParanoid(), WithMaxBytesPerKey(4096), WithShards(2))
+	is.NoError(err)
+	cfg := r.Config()
+	is.Equal(uint64(4096), cfg.MaxBytesPerKey, "later option should override the profile")
+	is.Equal(2, cfg.Shards, "later option should override the profile")
+	is.True(cfg.EnableKeyRotation, "profile settings not overridden should be kept")
+
+	for _, profile := range []Option{ProfileThroughput(), ProfileLowMemory(), ProfileParanoid()} {
+		r, err := NewReader(profile)
+		is.NoError(err, "profile should produce a valid Config")
+		is.NotNil(r)
+	}
+}
@@ -0,0 +1,280 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package dist
+
+import (
+	"math"
+)
+
+const (
+	// poissonInversionMax is the largest mean sampled by inversion; larger means use PTRS,
+	// whose cost does not grow with the mean.
+	poissonInversionMax = 10
+
+	// binomialInversionMax is the largest n·min(p, 1-p) sampled by inversion; larger
+	// values use BTRS.
+	binomialInversionMax = 10
+)
+
+// lfact returns log(k!).
+func lfact(k float64) float64 {
+	v, _ := math.Lgamma(k + 1)
+	return v
+}
+
+// Poisson returns a Poisson-distributed count of events with the given mean, such as the
+// number of arrivals in an interval.
+//
+// It returns ErrMeanInvalid if mean is negative, NaN or infinite.
+func (r *Rand) Poisson(mean float64) (int, error) {
+	if !(mean >= 0) || math.IsInf(mean, 0) {
+		return 0, ErrMeanInvalid
+	}
+	if mean == 0 {
+		return 0, nil
+	}
+	if mean <= poissonInversionMax {
+		return r.poissonInversion(mean)
+	}
+	return r.poissonPTRS(mean)
+}
+
+// poissonInversion walks the cumulative distribution from zero. A run that exhausts the
+// representable tail, which only floating-point residue makes possible, restarts.
+func (r *Rand) poissonInversion(mean float64) (int, error) {
+	for {
+		u, err := r.Float64()
+		if err != nil {
+			return 0, err
+		}
+		k := 0
+		p := math.Exp(-mean)
+		for u > p && p > 0 {
+			u -= p
+			k++
+			p *= mean / float64(k)
+		}
+		if p > 0 {
+			return k, nil
+		}
+	}
+}
+
+// poissonPTRS implements the transformed rejection method with squeeze of W. Hörmann,
+// "The transformed rejection method for generating Poisson random variables", Insurance:
+// Mathematics and Economics 12(1), 1993.
+func (r *Rand) poissonPTRS(mean float64) (int, error) {
+	slam := math.Sqrt(mean)
+	loglam := math.Log(mean)
+	b := 0.931 + 2.53*slam
+	a := -0.059 + 0.02483*b
+	invalpha := 1.1239 + 1.1328/(b-3.4)
+	vr := 0.9277 - 3.6224/(b-2)
+
+	for {
+		u, err := r.Float64()
+		if err != nil {
+			return 0, err
+		}
+		v, err := r.src.OpenFloat64()
+		if err != nil {
+			return 0, err
+		}
+		u -= 0.5
+		us := 0.5 - math.Abs(u)
+		k := math.Floor((2*a/us+b)*u + mean + 0.43)
+		if us >= 0.07 && v <= vr {
+			return int(k), nil
+		}
+		if k < 0 || (us < 0.013 && v > us) {
+			continue
+		}
+		if math.Log(v)+math.Log(invalpha)-math.Log(a/(us*us)+b) <= -mean+k*loglam-lfact(k) {
+			return int(k), nil
+		}
+	}
+}
+
+// Binomial returns the number of successes in n independent trials that each succeed
+// with probability p.
+//
+// It returns ErrTrialsInvalid if n is negative and ErrProbabilityInvalid unless
+// 0 <= p <= 1.
+func (r *Rand) Binomial(n int, p float64) (int, error) {
+	if n < 0 {
+		return 0, ErrTrialsInvalid
+	}
+	if !(p >= 0 && p <= 1) {
+		return 0, ErrProbabilityInvalid
+	}
+	if n == 0 || p == 0 {
+		return 0, nil
+	}
+	if p == 1 {
+		return n, nil
+	}
+
+	// Sample the number of the rarer outcome and flip it back if needed.
+	flip := p > 0.5
+	if flip {
+		p = 1 - p
+	}
+	var (
+		k   int
+		err error
+	)
+	if float64(n)*p <= binomialInversionMax {
+		k, err = r.binomialInversion(n, p)
+	} else {
+		k, err = r.binomialBTRS(n, p)
+	}
+	if flip {
+		k = n - k
+	}
+	return k, err
+}
+
+// binomialInversion walks the cumulative distribution from zero, for p <= 0.5 and a
+// small mean. Rare runs past a generous bound, caused by floating-point residue, restart.
+func (r *Rand) binomialInversion(n int, p float64) (int, error) {
+	q := 1 - p
+	qn := math.Exp(float64(n) * math.Log1p(-p))
+	np := float64(n) * p
+	bound := math.Min(float64(n), np+10*math.Sqrt(np*q+1))
+
+	for {
+		u, err := r.Float64()
+		if err != nil {
+			return 0, err
+		}
+		k := 0
+		px := qn
+		for u > px {
+			k++
+			if float64(k) > bound {
+				break
+			}
+			u -= px
+			px = px * float64(n-k+1) * p / (float64(k) * q)
+		}
+		if float64(k) <= bound {
+			return k, nil
+		}
+	}
+}
+
+// binomialBTRS implements the transformed rejection method with squeeze of W. Hörmann,
+// "The generation of binomial random variates", Journal of Statistical Computation and
+// Simulation 46(1-2), 1993, for p <= 0.5 and n·p > 10.
+func (r *Rand) binomialBTRS(n int, p float64) (int, error) {
+	nf := float64(n)
+	q := 1 - p
+	spq := math.Sqrt(nf * p * q)
+	b := 1.15 + 2.53*spq
+	a := -0.0873 + 0.0248*b + 0.01*p
+	c := nf*p + 0.5
+	vr := 0.92 - 4.2/b
+	alpha := (2.83 + 5.1/b) * spq
+	lpq := math.Log(p / q)
+	m := math.Floor((nf + 1) * p)
+	h := lfact(m) + lfact(nf-m)
+
+	for {
+		u, err := r.Float64()
+		if err != nil {
+			return 0, err
+		}
+		v, err := r.src.OpenFloat64()
+		if err != nil {
+			return 0, err
+		}
+		u -= 0.5
+		us := 0.5 - math.Abs(u)
+		k := math.Floor((2*a/us+b)*u + c)
+		if k < 0 || k > nf {
+			continue
+		}
+		if us >= 0.07 && v <= vr {
+			return int(k), nil
+		}
+		v = math.Log(v * alpha / (a/(us*us) + b))
+		if v <= h-lfact(k)-lfact(nf-k)+(k-m)*lpq {
+			return int(k), nil
+		}
+	}
+}
+
+// Zipf samples a Zipf distribution: values k in [0, imax] with probability proportional
+// to (v + k)^(-s). It models skewed popularity, such as cache keys or tenants in a load
+// test. A Zipf shares its Rand and, like it, is not safe for concurrent use.
+type Zipf struct {
+	r            *Rand
+	imax         float64
+	v            float64
+	q            float64
+	s            float64
+	oneMinusQ    float64
+	oneMinusQInv float64
+	hxm          float64
+	hx0MinusHxm  float64
+}
+
+// NewZipf returns a Zipf sampler drawing from r with exponent s > 1, offset v >= 1 and
+// maximum value imax >= 1, sampled with the rejection-inversion method of W. Hörmann and
+// G. Derflinger, "Rejection-inversion to generate variates from monotone discrete
+// distributions", ACM TOMACS 6(3), 1996.
+//
+// It returns ErrNilRand if r is nil and ErrZipfInvalid for parameters out of range.
+func NewZipf(r *Rand, s, v float64, imax uint64) (*Zipf, error) {
+	if r == nil {
+		return nil, ErrNilRand
+	}
+	if !(s > 1) || !(v >= 1) || imax < 1 || math.IsInf(s, 0) || math.IsInf(v, 0) {
+		return nil, ErrZipfInvalid
+	}
+
+	z := &Zipf{
+		r:    r,
+		imax: float64(imax),
+		v:    v,
+		q:    s,
+	}
+	z.oneMinusQ = 1 - z.q
+	z.oneMinusQInv = 1 / z.oneMinusQ
+	z.hxm = z.h(z.imax + 0.5)
+	z.hx0MinusHxm = z.h(0.5) - math.Exp(math.Log(z.v)*(-z.q)) - z.hxm
+	z.s = 1 - z.hinv(z.h(1.5)-math.Exp(-z.q*math.Log(z.v+1)))
+	return z, nil
+}
+
+// h is the integral of the hat function.
+func (z *Zipf) h(x float64) float64 {
+	return math.Exp(z.oneMinusQ*math.Log(z.v+x)) * z.oneMinusQInv
+}
+
+// hinv is the inverse of h.
+func (z *Zipf) hinv(x float64) float64 {
+	return math.Exp(z.oneMinusQInv*math.Log(z.oneMinusQ*x)) - z.v
+}
+
+// Uint64 returns a Zipf-distributed value in [0, imax].
+func (z *Zipf) Uint64() (uint64, error) {
+	for {
+		u, err := z.r.Float64()
+		if err != nil {
+			return 0, err
+		}
+		ur := z.hxm + u*z.hx0MinusHxm
+		x := z.hinv(ur)
+		k := math.Floor(x + 0.5)
+		if k-x <= z.s {
+			return uint64(k), nil
+		}
+		if ur >= z.h(k+0.5)-math.Exp(-math.Log(k+z.v)*z.q) {
+			return uint64(k), nil
+		}
+	}
+}
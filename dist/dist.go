@@ -0,0 +1,148 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package dist samples non-uniform distributions (normal, exponential, Poisson,
+// binomial, geometric and Zipf) from the prng package.
+//
+// A Rand reads its randomness through a small buffer from any io.Reader: prng.Reader by
+// default, a prng.Interface from prng.NewReader, or a prng.SeededReader when runs must be
+// reproducible across machines and teams. With a SeededReader, the same seed and the
+// same sequence of calls always produce the same samples.
+//
+// Normal and exponential variates use the ziggurat method of Marsaglia and Tsang;
+// Poisson and binomial variates use inversion for small means and Hörmann's transformed
+// rejection (PTRS and BTRS) for large ones; Zipf variates use rejection-inversion.
+//
+// Example:
+//
+//	seed := sha256.Sum256([]byte("load-test-2026-10"))
+//	src, _ := prng.NewSeededReader(seed[:])
+//	r, err := dist.New(dist.WithReader(src))
+//	if err != nil {
+//	    // handle error
+//	}
+//	size, err := r.Normal(4096, 512)  // request size in bytes
+//	wait, err := r.Exponential(200)   // seconds between requests at 200 per second
+//	arrivals, err := r.Poisson(200)   // requests in one second
+package dist
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/sixafter/prng-chacha"
+	"github.com/sixafter/prng-chacha/internal/randutil"
+)
+
+var (
+	ErrStdDevInvalid      = fmt.Errorf("dist: standard deviation must be finite and non-negative")
+	ErrRateInvalid        = fmt.Errorf("dist: rate must be finite and greater than zero")
+	ErrMeanInvalid        = fmt.Errorf("dist: mean must be finite and non-negative")
+	ErrTrialsInvalid      = fmt.Errorf("dist: number of trials cannot be negative")
+	ErrProbabilityInvalid = fmt.Errorf("dist: probability is out of range")
+	ErrZipfInvalid        = fmt.Errorf("dist: Zipf parameters require s > 1, v >= 1 and imax >= 1")
+	ErrNilReader          = fmt.Errorf("dist: reader must not be nil")
+	ErrNilRand            = fmt.Errorf("dist: Rand must not be nil")
+)
+
+// Config defines the randomness source of a Rand.
+type Config struct {
+	// Reader is the source of randomness. Defaults to prng.Reader.
+	Reader io.Reader
+}
+
+// Option defines a functional option for customizing a Rand's Config.
+type Option func(*Config)
+
+// WithReader returns an Option that sets the source of randomness, typically a
+// prng.Interface returned by prng.NewReader or a prng.SeededReader.
+func WithReader(r io.Reader) Option {
+	return func(cfg *Config) {
+		cfg.Reader = r
+	}
+}
+
+// Rand samples distributions from a buffered random source.
+//
+// A Rand is not safe for concurrent use: like math/rand.Rand, it keeps unconsumed random
+// bytes in a buffer, and with a seeded source its samples are only reproducible if calls
+// happen in a fixed order. Use one Rand per goroutine.
+//
+// Every method returns an error if its parameters are out of range or the underlying
+// reader fails.
+type Rand struct {
+	src *randutil.Reader
+}
+
+// New returns a Rand configured by opts.
+//
+// It returns ErrNilReader if the configured reader is nil.
+func New(opts ...Option) (*Rand, error) {
+	cfg := Config{
+		Reader: prng.Reader,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.Reader == nil {
+		return nil, ErrNilReader
+	}
+	return &Rand{src: randutil.NewReader(cfg.Reader)}, nil
+}
+
+// Uint64 returns a uniformly distributed random uint64.
+func (r *Rand) Uint64() (uint64, error) {
+	return r.src.Uint64()
+}
+
+// Float64 returns a uniformly distributed float64 in [0, 1) with 53 bits of precision.
+func (r *Rand) Float64() (float64, error) {
+	u, err := r.src.Uint64()
+	return float64(u>>11) * 0x1p-53, err
+}
+
+// Normal returns a normally distributed float64 with the given mean and standard deviation.
+func (r *Rand) Normal(mean, stddev float64) (float64, error) {
+	if !(stddev >= 0) || math.IsInf(stddev, 0) {
+		return 0, ErrStdDevInvalid
+	}
+	z, err := r.NormFloat64()
+	return mean + stddev*z, err
+}
+
+// Exponential returns an exponentially distributed float64 with the given rate (lambda),
+// so the mean is 1/rate. It models the time between events of a Poisson process.
+func (r *Rand) Exponential(rate float64) (float64, error) {
+	if !(rate > 0) || math.IsInf(rate, 0) {
+		return 0, ErrRateInvalid
+	}
+	e, err := r.ExpFloat64()
+	return e / rate, err
+}
+
+// Geometric returns the number of independent trials, each succeeding with probability p,
+// up to and including the first success. The result is at least 1 and has mean 1/p;
+// results beyond math.MaxInt, only possible for minuscule p, are capped at math.MaxInt.
+//
+// It returns ErrProbabilityInvalid unless 0 < p <= 1.
+func (r *Rand) Geometric(p float64) (int, error) {
+	if !(p > 0 && p <= 1) {
+		return 0, ErrProbabilityInvalid
+	}
+	if p == 1 {
+		return 1, nil
+	}
+	u, err := r.src.OpenFloat64()
+	if err != nil {
+		return 0, err
+	}
+	k := math.Floor(math.Log(u)/math.Log1p(-p)) + 1
+	if k >= math.MaxInt {
+		return math.MaxInt, nil
+	}
+	return int(k), nil
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package dist
+
+import (
+	"testing"
+)
+
+// BenchmarkDist_Normal measures NormFloat64 over prng.Reader.
+func BenchmarkDist_Normal(b *testing.B) {
+	r, err := New()
+	if err != nil {
+		b.Fatalf("New failed: %v", err)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		_, _ = r.NormFloat64()
+	}
+}
+
+// BenchmarkDist_Exponential measures ExpFloat64 over prng.Reader.
+func BenchmarkDist_Exponential(b *testing.B) {
+	r, err := New()
+	if err != nil {
+		b.Fatalf("New failed: %v", err)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		_, _ = r.ExpFloat64()
+	}
+}
+
+// BenchmarkDist_Normal_Seeded measures NormFloat64 over a SeededReader.
+func BenchmarkDist_Normal_Seeded(b *testing.B) {
+	r := seeded(b, "bench")
+	b.ReportAllocs()
+	for b.Loop() {
+		_, _ = r.NormFloat64()
+	}
+}
+
+// BenchmarkDist_Poisson measures the inversion and PTRS paths.
+func BenchmarkDist_Poisson(b *testing.B) {
+	for _, bc := range []struct {
+		name string
+		mean float64
+	}{{"Mean_4", 4}, {"Mean_100", 100}, {"Mean_1e6", 1e6}} {
+		b.Run(bc.name, func(b *testing.B) {
+			r := seeded(b, "bench")
+			b.ReportAllocs()
+			for b.Loop() {
+				_, _ = r.Poisson(bc.mean)
+			}
+		})
+	}
+}
+
+// BenchmarkDist_Binomial measures the inversion and BTRS paths.
+func BenchmarkDist_Binomial(b *testing.B) {
+	for _, bc := range []struct {
+		name string
+		n    int
+		p    float64
+	}{{"N_20", 20, 0.3}, {"N_1000", 1000, 0.4}, {"N_1e6", 1e6, 0.5}} {
+		b.Run(bc.name, func(b *testing.B) {
+			r := seeded(b, "bench")
+			b.ReportAllocs()
+			for b.Loop() {
+				_, _ = r.Binomial(bc.n, bc.p)
+			}
+		})
+	}
+}
+
+// BenchmarkDist_Zipf measures Zipf sampling over a large key space.
+func BenchmarkDist_Zipf(b *testing.B) {
+	z, err := NewZipf(seeded(b, "bench"), 1.1, 1, 1<<20)
+	if err != nil {
+		b.Fatalf("NewZipf failed: %v", err)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		_, _ = z.Uint64()
+	}
+}
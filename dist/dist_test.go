@@ -0,0 +1,400 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package dist
+
+import (
+	"crypto/sha256"
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/sixafter/prng-chacha"
+	"github.com/stretchr/testify/assert"
+)
+
+// The goodness-of-fit tests draw from seeded readers so that they are deterministic; the
+// thresholds are set at a significance level of 0.001.
+
+const samples = 50000
+
+// seeded returns a Rand over a SeededReader derived from name.
+func seeded(t testing.TB, name string) *Rand {
+	t.Helper()
+	seed := sha256.Sum256([]byte("dist/" + name))
+	src, err := prng.NewSeededReader(seed[:])
+	if err != nil {
+		t.Fatalf("NewSeededReader failed: %v", err)
+	}
+	r, err := New(WithReader(src))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return r
+}
+
+// ksStatistic returns the Kolmogorov-Smirnov distance between the empirical distribution
+// of xs and cdf. xs is sorted in place.
+func ksStatistic(xs []float64, cdf func(float64) float64) float64 {
+	slices.Sort(xs)
+	n := float64(len(xs))
+	var d float64
+	for i, x := range xs {
+		f := cdf(x)
+		d = math.Max(d, math.Max(f-float64(i)/n, float64(i+1)/n-f))
+	}
+	return d
+}
+
+// ksCritical is the Kolmogorov-Smirnov critical value for n samples at 0.001.
+func ksCritical(n int) float64 {
+	return 1.9495 / math.Sqrt(float64(n))
+}
+
+// chiSquared compares counts of integer outcomes with pmf. Outcomes are binned from the
+// lowest value with appreciable probability; bins with fewer than 5 expected observations
+// are merged into their neighbours. It returns the statistic and the degrees of freedom.
+func chiSquared(counts map[int]int, n int, lo, hi int, pmf func(int) float64) (float64, int) {
+	var (
+		chi2       float64
+		bins       int
+		obs, exp   float64
+		flush      = func() { d := obs - exp; chi2 += d * d / exp; bins++; obs, exp = 0, 0 }
+		totalProb  float64
+		totalCount int
+	)
+	for k := lo; k <= hi; k++ {
+		p := pmf(k)
+		totalProb += p
+		totalCount += counts[k]
+		obs += float64(counts[k])
+		exp += p * float64(n)
+		if exp >= 5 {
+			flush()
+		}
+	}
+	// Everything outside [lo, hi] joins the last bin.
+	obs += float64(n - totalCount)
+	exp += (1 - totalProb) * float64(n)
+	if exp > 0 {
+		flush()
+	}
+	return chi2, bins - 1
+}
+
+// chiCritical approximates the chi-squared critical value at 0.001 using the
+// Wilson-Hilferty transformation.
+func chiCritical(df int) float64 {
+	const z = 3.0902
+	k := float64(df)
+	a := 2 / (9 * k)
+	return k * math.Pow(1-a+z*math.Sqrt(a), 3)
+}
+
+// Test_Normal_GoodnessOfFit compares NormFloat64 and Normal with the normal distribution.
+func Test_Normal_GoodnessOfFit(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	r := seeded(t, "normal")
+	xs := make([]float64, samples)
+	var sum, sumSq float64
+	for i := range xs {
+		x, err := r.Normal(10, 3)
+		is.NoError(err)
+		xs[i] = x
+		sum += x
+		sumSq += x * x
+	}
+	mean := sum / samples
+	is.InDelta(10, mean, 0.05)
+	is.InDelta(3, math.Sqrt(sumSq/samples-mean*mean), 0.05)
+
+	d := ksStatistic(xs, func(x float64) float64 { return 0.5 * math.Erfc(-(x-10)/(3*math.Sqrt2)) })
+	is.Less(d, ksCritical(samples))
+
+	// The tail beyond the ziggurat base is sampled separately; check its frequency.
+	tail := 0
+	for i := 0; i < 4*samples; i++ {
+		z, err := r.NormFloat64()
+		is.NoError(err)
+		if math.Abs(z) > normR {
+			tail++
+		}
+	}
+	want := 4 * samples * math.Erfc(normR/math.Sqrt2)
+	is.InDelta(want, float64(tail), 4*math.Sqrt(want))
+}
+
+// Test_Exponential_GoodnessOfFit compares ExpFloat64 and Exponential with the exponential
+// distribution.
+func Test_Exponential_GoodnessOfFit(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	r := seeded(t, "exponential")
+	xs := make([]float64, samples)
+	for i := range xs {
+		x, err := r.Exponential(4)
+		is.NoError(err)
+		is.GreaterOrEqual(x, 0.0)
+		xs[i] = x
+	}
+	d := ksStatistic(xs, func(x float64) float64 { return -math.Expm1(-4 * x) })
+	is.Less(d, ksCritical(samples))
+}
+
+// Test_Poisson_GoodnessOfFit covers the inversion and PTRS paths.
+func Test_Poisson_GoodnessOfFit(t *testing.T) {
+	t.Parallel()
+
+	for _, mean := range []float64{0.5, 4, 10, 10.5, 50, 1000} {
+		t.Run(strconv.FormatFloat(mean, 'g', -1, 64), func(t *testing.T) {
+			t.Parallel()
+			is := assert.New(t)
+
+			r := seeded(t, "poisson/"+strconv.FormatFloat(mean, 'g', -1, 64))
+			counts := make(map[int]int)
+			for i := 0; i < samples; i++ {
+				k, err := r.Poisson(mean)
+				is.NoError(err)
+				is.GreaterOrEqual(k, 0)
+				counts[k]++
+			}
+			pmf := func(k int) float64 {
+				return math.Exp(float64(k)*math.Log(mean) - mean - lfact(float64(k)))
+			}
+			spread := int(10*math.Sqrt(mean)) + 10
+			lo, hi := max(0, int(mean)-spread), int(mean)+spread
+			chi2, df := chiSquared(counts, samples, lo, hi, pmf)
+			is.Less(chi2, chiCritical(df), "df=%d", df)
+		})
+	}
+}
+
+// Test_Binomial_GoodnessOfFit covers inversion, BTRS and the p > 0.5 reflection.
+func Test_Binomial_GoodnessOfFit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		n int
+		p float64
+	}{
+		{20, 0.3},
+		{50, 0.9},
+		{1000, 0.4},
+		{1000, 0.8},
+		{100000, 0.001},
+	}
+	for _, tc := range tests {
+		name := strconv.Itoa(tc.n) + "_" + strconv.FormatFloat(tc.p, 'g', -1, 64)
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			is := assert.New(t)
+
+			r := seeded(t, "binomial/"+name)
+			counts := make(map[int]int)
+			for i := 0; i < samples; i++ {
+				k, err := r.Binomial(tc.n, tc.p)
+				is.NoError(err)
+				is.GreaterOrEqual(k, 0)
+				is.LessOrEqual(k, tc.n)
+				counts[k]++
+			}
+			n := float64(tc.n)
+			pmf := func(k int) float64 {
+				kf := float64(k)
+				return math.Exp(lfact(n) - lfact(kf) - lfact(n-kf) + kf*math.Log(tc.p) + (n-kf)*math.Log1p(-tc.p))
+			}
+			chi2, df := chiSquared(counts, samples, 0, tc.n, pmf)
+			is.Less(chi2, chiCritical(df), "df=%d", df)
+		})
+	}
+}
+
+// Test_Geometric_GoodnessOfFit compares Geometric with the geometric distribution.
+func Test_Geometric_GoodnessOfFit(t *testing.T) {
+	t.Parallel()
+
+	for _, p := range []float64{0.5, 0.05} {
+		t.Run(strconv.FormatFloat(p, 'g', -1, 64), func(t *testing.T) {
+			t.Parallel()
+			is := assert.New(t)
+
+			r := seeded(t, "geometric/"+strconv.FormatFloat(p, 'g', -1, 64))
+			counts := make(map[int]int)
+			for i := 0; i < samples; i++ {
+				k, err := r.Geometric(p)
+				is.NoError(err)
+				is.GreaterOrEqual(k, 1)
+				counts[k]++
+			}
+			pmf := func(k int) float64 {
+				if k < 1 {
+					return 0
+				}
+				return math.Pow(1-p, float64(k-1)) * p
+			}
+			chi2, df := chiSquared(counts, samples, 1, int(20/p), pmf)
+			is.Less(chi2, chiCritical(df), "df=%d", df)
+		})
+	}
+
+	r := seeded(t, "geometric/1")
+	k, err := r.Geometric(1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, k)
+}
+
+// Test_Zipf_GoodnessOfFit compares Zipf with its normalized probability mass function.
+func Test_Zipf_GoodnessOfFit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		s, v float64
+		imax uint64
+	}{
+		{1.5, 1, 100},
+		{2.5, 3, 20},
+		{1.1, 1, 1000},
+	}
+	for _, tc := range tests {
+		name := strconv.FormatFloat(tc.s, 'g', -1, 64) + "_" + strconv.FormatFloat(tc.v, 'g', -1, 64) + "_" + strconv.FormatUint(tc.imax, 10)
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			is := assert.New(t)
+
+			z, err := NewZipf(seeded(t, "zipf/"+name), tc.s, tc.v, tc.imax)
+			is.NoError(err)
+
+			counts := make(map[int]int)
+			for i := 0; i < samples; i++ {
+				k, err := z.Uint64()
+				is.NoError(err)
+				is.LessOrEqual(k, tc.imax)
+				counts[int(k)]++
+			}
+			var norm float64
+			for k := uint64(0); k <= tc.imax; k++ {
+				norm += math.Pow(tc.v+float64(k), -tc.s)
+			}
+			pmf := func(k int) float64 { return math.Pow(tc.v+float64(k), -tc.s) / norm }
+			chi2, df := chiSquared(counts, samples, 0, int(tc.imax), pmf)
+			is.Less(chi2, chiCritical(df), "df=%d", df)
+		})
+	}
+}
+
+// Test_Seeded_Reproducible verifies that equal seeds give equal samples.
+func Test_Seeded_Reproducible(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	a, b := seeded(t, "repro"), seeded(t, "repro")
+	for i := 0; i < 1000; i++ {
+		x, err := a.Normal(0, 1)
+		is.NoError(err)
+		y, err := b.Normal(0, 1)
+		is.NoError(err)
+		is.Equal(x, y)
+
+		m, err := a.Poisson(30)
+		is.NoError(err)
+		n, err := b.Poisson(30)
+		is.NoError(err)
+		is.Equal(m, n)
+	}
+}
+
+// Test_DefaultReader verifies that the default Rand draws from prng.Reader.
+func Test_DefaultReader(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	r, err := New()
+	is.NoError(err)
+	var sum float64
+	for i := 0; i < 10000; i++ {
+		f, err := r.Float64()
+		is.NoError(err)
+		is.GreaterOrEqual(f, 0.0)
+		is.Less(f, 1.0)
+		sum += f
+	}
+	is.InDelta(0.5, sum/10000, 0.02)
+}
+
+var errRead = errors.New("read failed")
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) { return 0, errRead }
+
+// Test_Errors verifies parameter validation and reader error propagation.
+func Test_Errors(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	_, err := New(WithReader(nil))
+	is.ErrorIs(err, ErrNilReader)
+
+	r := seeded(t, "errors")
+	_, err = r.Normal(0, -1)
+	is.ErrorIs(err, ErrStdDevInvalid)
+	_, err = r.Normal(0, math.NaN())
+	is.ErrorIs(err, ErrStdDevInvalid)
+	_, err = r.Exponential(0)
+	is.ErrorIs(err, ErrRateInvalid)
+	_, err = r.Exponential(math.Inf(1))
+	is.ErrorIs(err, ErrRateInvalid)
+	_, err = r.Poisson(-1)
+	is.ErrorIs(err, ErrMeanInvalid)
+	_, err = r.Poisson(math.NaN())
+	is.ErrorIs(err, ErrMeanInvalid)
+	_, err = r.Binomial(-1, 0.5)
+	is.ErrorIs(err, ErrTrialsInvalid)
+	_, err = r.Binomial(10, 1.5)
+	is.ErrorIs(err, ErrProbabilityInvalid)
+	_, err = r.Geometric(0)
+	is.ErrorIs(err, ErrProbabilityInvalid)
+	_, err = NewZipf(r, 1, 1, 10)
+	is.ErrorIs(err, ErrZipfInvalid)
+	_, err = NewZipf(r, 2, 0.5, 10)
+	is.ErrorIs(err, ErrZipfInvalid)
+	_, err = NewZipf(r, 2, 1, 0)
+	is.ErrorIs(err, ErrZipfInvalid)
+	_, err = NewZipf(nil, 2, 1, 10)
+	is.ErrorIs(err, ErrNilRand)
+
+	// Degenerate parameters need no randomness.
+	k, err := r.Poisson(0)
+	is.NoError(err)
+	is.Zero(k)
+	k, err = r.Binomial(7, 1)
+	is.NoError(err)
+	is.Equal(7, k)
+	k, err = r.Binomial(7, 0)
+	is.NoError(err)
+	is.Zero(k)
+
+	f, err := New(WithReader(errReader{}))
+	is.NoError(err)
+	_, err = f.NormFloat64()
+	is.ErrorIs(err, errRead)
+	_, err = f.ExpFloat64()
+	is.ErrorIs(err, errRead)
+	_, err = f.Poisson(100)
+	is.ErrorIs(err, errRead)
+	_, err = f.Binomial(1000, 0.5)
+	is.ErrorIs(err, errRead)
+	_, err = f.Geometric(0.5)
+	is.ErrorIs(err, errRead)
+	z, err := NewZipf(f, 2, 1, 10)
+	is.NoError(err)
+	_, err = z.Uint64()
+	is.ErrorIs(err, errRead)
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package dist
+
+import (
+	"math"
+)
+
+// Ziggurat tables after G. Marsaglia and W. W. Tsang, "The Ziggurat Method for
+// Generating Random Variables", Journal of Statistical Software 5(8), 2000.
+//
+// The density is covered by layers of equal area. A sample picks a layer and a
+// position within it; positions inside the rectangle under the curve, which is the
+// vast majority, are accepted with one comparison. The tables are computed at package
+// initialization from the published constants.
+const (
+	// normR is the start of the tail of the 128-layer normal ziggurat.
+	normR = 3.442619855899
+	// normV is the area of each normal layer.
+	normV = 9.91256303526217e-3
+
+	// expR is the start of the tail of the 256-layer exponential ziggurat.
+	expR = 7.697117470131487
+	// expV is the area of each exponential layer.
+	expV = 3.949659822581572e-3
+)
+
+var (
+	// kn, wn and fn are the normal acceptance thresholds, layer widths scaled to a
+	// 32-bit signed integer, and densities at the layer edges.
+	kn [128]uint32
+	wn [128]float64
+	fn [128]float64
+
+	// ke, we and fe are the exponential equivalents, scaled to a 32-bit unsigned integer.
+	ke [256]uint32
+	we [256]float64
+	fe [256]float64
+)
+
+func init() {
+	const m1 = 1 << 31
+	dn, tn := normR, normR
+	q := normV / math.Exp(-0.5*dn*dn)
+	kn[0] = uint32(dn / q * m1)
+	kn[1] = 0
+	wn[0] = q / m1
+	wn[127] = dn / m1
+	fn[0] = 1
+	fn[127] = math.Exp(-0.5 * dn * dn)
+	for i := 126; i >= 1; i-- {
+		dn = math.Sqrt(-2 * math.Log(normV/dn+math.Exp(-0.5*dn*dn)))
+		kn[i+1] = uint32(dn / tn * m1)
+		tn = dn
+		fn[i] = math.Exp(-0.5 * dn * dn)
+		wn[i] = dn / m1
+	}
+
+	const m2 = 1 << 32
+	de, te := expR, expR
+	q = expV / math.Exp(-de)
+	ke[0] = uint32(de / q * m2)
+	ke[1] = 0
+	we[0] = q / m2
+	we[255] = de / m2
+	fe[0] = 1
+	fe[255] = math.Exp(-de)
+	for i := 254; i >= 1; i-- {
+		de = -math.Log(expV/de + math.Exp(-de))
+		ke[i+1] = uint32(de / te * m2)
+		te = de
+		fe[i] = math.Exp(-de)
+		we[i] = de / m2
+	}
+}
+
+// NormFloat64 returns a normally distributed float64 with mean 0 and standard deviation 1.
+func (r *Rand) NormFloat64() (float64, error) {
+	for {
+		u, err := r.src.Uint64()
+		if err != nil {
+			return 0, err
+		}
+		// The layer comes from the low bits and the signed position from the high bits,
+		// so the two are independent.
+		i := u & 127
+		j := int32(u >> 32)
+		x := float64(j) * wn[i]
+		if absInt32(j) < kn[i] {
+			return x, nil
+		}
+
+		if i == 0 {
+			// Sample the tail beyond normR.
+			for {
+				u1, err := r.src.OpenFloat64()
+				if err != nil {
+					return 0, err
+				}
+				u2, err := r.src.OpenFloat64()
+				if err != nil {
+					return 0, err
+				}
+				x = -math.Log(u1) / normR
+				y := -math.Log(u2)
+				if y+y >= x*x {
+					break
+				}
+			}
+			if j > 0 {
+				return normR + x, nil
+			}
+			return -normR - x, nil
+		}
+
+		// Between the rectangle and the curve: accept if under the density.
+		f, err := r.Float64()
+		if err != nil {
+			return 0, err
+		}
+		if fn[i]+f*(fn[i-1]-fn[i]) < math.Exp(-0.5*x*x) {
+			return x, nil
+		}
+	}
+}
+
+// ExpFloat64 returns an exponentially distributed float64 with rate 1, so mean 1.
+func (r *Rand) ExpFloat64() (float64, error) {
+	for {
+		u, err := r.src.Uint64()
+		if err != nil {
+			return 0, err
+		}
+		i := u & 255
+		j := uint32(u >> 32)
+		x := float64(j) * we[i]
+		if j < ke[i] {
+			return x, nil
+		}
+
+		if i == 0 {
+			// The tail beyond expR is itself exponential, shifted by expR.
+			f, err := r.src.OpenFloat64()
+			if err != nil {
+				return 0, err
+			}
+			return expR - math.Log(f), nil
+		}
+
+		f, err := r.Float64()
+		if err != nil {
+			return 0, err
+		}
+		if fe[i]+f*(fe[i-1]-fe[i]) < math.Exp(-x) {
+			return x, nil
+		}
+	}
+}
+
+// absInt32 returns the absolute value of i as a uint32, which is correct for math.MinInt32.
+func absInt32(i int32) uint32 {
+	if i < 0 {
+		return uint32(-i)
+	}
+	return uint32(i)
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package egd
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+)
+
+// Client speaks the EGD protocol to a server. It is safe for concurrent use; requests
+// are serialized on the connection.
+type Client struct {
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// Dial connects to the EGD server on the Unix domain socket at path.
+func Dial(path string) (*Client, error) {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(conn), nil
+}
+
+// NewClient returns a Client that speaks EGD on conn. Deadlines set on conn apply to
+// the client's requests.
+func NewClient(conn net.Conn) *Client {
+	return &Client{conn: conn}
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// EntropyCount returns the entropy the server reports available, in bits.
+func (c *Client) EntropyCount() (uint32, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var resp [4]byte
+	if err := c.roundTrip([]byte{cmdEntropyCount}, resp[:]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint32(resp[:]), nil
+}
+
+// ReadNonBlocking requests len(p) bytes without waiting and returns the number of bytes
+// the server supplied, which may be fewer, or zero, under a rate limit.
+//
+// It returns ErrRequestSizeInvalid unless 1 <= len(p) <= MaxRequestSize, and
+// ErrMalformedResponse if the server claims more bytes than requested.
+func (c *Client) ReadNonBlocking(p []byte) (int, error) {
+	if len(p) == 0 || len(p) > MaxRequestSize {
+		return 0, ErrRequestSizeInvalid
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var count [1]byte
+	if err := c.roundTrip([]byte{cmdReadNonBlock, byte(len(p))}, count[:]); err != nil {
+		return 0, err
+	}
+	n := int(count[0])
+	if n > len(p) {
+		return 0, ErrMalformedResponse
+	}
+	if _, err := io.ReadFull(c.conn, p[:n]); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// Read fills p with blocking reads of up to MaxRequestSize bytes each, waiting for the
+// server's rate limit as needed. It implements io.Reader and returns len(p) unless an
+// error occurs.
+func (c *Client) Read(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var n int
+	for n < len(p) {
+		chunk := p[n:min(len(p), n+MaxRequestSize)]
+		if err := c.roundTrip([]byte{cmdReadBlock, byte(len(chunk))}, chunk); err != nil {
+			return n, err
+		}
+		n += len(chunk)
+	}
+	return n, nil
+}
+
+// PID returns the process ID of the server.
+func (c *Client) PID() (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var size [1]byte
+	if err := c.roundTrip([]byte{cmdPID}, size[:]); err != nil {
+		return 0, err
+	}
+	buf := make([]byte, size[0])
+	if _, err := io.ReadFull(c.conn, buf); err != nil {
+		return 0, err
+	}
+	pid, err := strconv.Atoi(string(buf))
+	if err != nil {
+		return 0, ErrMalformedResponse
+	}
+	return pid, nil
+}
+
+// roundTrip writes req and reads exactly len(resp) bytes of response. An unexpected
+// end of stream means the server rejected the request and closed the connection.
+func (c *Client) roundTrip(req, resp []byte) error {
+	if _, err := c.conn.Write(req); err != nil {
+		return err
+	}
+	_, err := io.ReadFull(c.conn, resp)
+	return err
+}
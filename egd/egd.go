@@ -0,0 +1,186 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package egd serves random bytes from the prng package over the Entropy Gathering
+// Daemon (EGD) protocol, and provides a client for it.
+//
+// EGD is a byte-oriented request/response protocol over a Unix domain socket, spoken by
+// OpenSSL's RAND_egd and other legacy tools. The server implements:
+//
+//	0x00             entropy count: responds with the available entropy in bits as a
+//	                 4-byte big-endian integer
+//	0x01 n           non-blocking read: responds with a count byte k <= n followed by k
+//	                 random bytes
+//	0x02 n           blocking read: responds with exactly n random bytes, waiting for the
+//	                 connection's rate limit if necessary
+//	0x04             process ID: responds with a length byte followed by the server's
+//	                 process ID in decimal
+//
+// The write-entropy command (0x03) and unknown commands close the connection: the
+// server's reader is a CSPRNG seeded from the operating system and does not accept
+// caller-supplied entropy.
+//
+// A ChaCha20 stream does not deplete, so without a rate limit the server reports the
+// maximum entropy count and never blocks. WithRateLimit gives each connection a token
+// bucket; the entropy count then reports the tokens available, non-blocking reads
+// return at most that many bytes, and blocking reads wait for them.
+//
+// Example:
+//
+//	r, err := prng.NewReader()
+//	if err != nil {
+//	    // handle error
+//	}
+//	srv, err := egd.NewServer(egd.WithReader(r), egd.WithRateLimit(1<<20, 64<<10))
+//	if err != nil {
+//	    // handle error
+//	}
+//	go srv.ListenAndServe("/var/run/egd-pool")
+//	defer srv.Close()
+package egd
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/sixafter/prng-chacha"
+)
+
+var (
+	ErrNilReader          = fmt.Errorf("egd: reader must not be nil")
+	ErrRateLimitInvalid   = fmt.Errorf("egd: rate limit must not be negative")
+	ErrBurstInvalid       = fmt.Errorf("egd: burst must be at least %d bytes when rate limited", MaxRequestSize)
+	ErrServerClosed       = fmt.Errorf("egd: server closed")
+	ErrAddressInUse       = fmt.Errorf("egd: socket is in use by another server")
+	ErrRequestSizeInvalid = fmt.Errorf("egd: request size must be between 1 and %d bytes", MaxRequestSize)
+	ErrMalformedResponse  = fmt.Errorf("egd: malformed response")
+)
+
+// MaxRequestSize is the largest number of bytes a single EGD read request can ask for.
+const MaxRequestSize = 255
+
+// Protocol commands.
+const (
+	cmdEntropyCount = 0x00
+	cmdReadNonBlock = 0x01
+	cmdReadBlock    = 0x02
+	cmdWriteEntropy = 0x03
+	cmdPID          = 0x04
+)
+
+// Defaults.
+const (
+	defaultMaxConnections = 256
+	defaultIdleTimeout    = 5 * time.Minute
+	defaultSocketMode     = 0o600
+)
+
+// Config defines the randomness source and limits of a Server.
+type Config struct {
+	// Reader is the source of random bytes. Defaults to prng.Reader; the prngd command
+	// passes a reader from prng.NewReader configured by its flags.
+	Reader io.Reader
+
+	// RateLimit is the sustained number of bytes per second each connection may read.
+	// Zero, the default, disables rate limiting.
+	RateLimit int
+
+	// Burst is the size, in bytes, of each connection's token bucket: the most it can
+	// read at once after being idle. It must be at least MaxRequestSize when RateLimit
+	// is set, so that every blocking read can eventually be satisfied.
+	Burst int
+
+	// MaxConnections is the number of concurrent connections served; further
+	// connections are closed on accept. Defaults to 256. Zero or negative means
+	// unlimited.
+	MaxConnections int
+
+	// IdleTimeout closes a connection that sends no request, or does not read its
+	// response, for this long. Defaults to 5 minutes. Zero disables the timeout.
+	IdleTimeout time.Duration
+
+	// SocketMode is the permission of the socket file created by ListenAndServe.
+	// Defaults to 0600.
+	SocketMode os.FileMode
+
+	// Logger receives connection and error records. Nil disables logging.
+	Logger *slog.Logger
+}
+
+// Option defines a functional option for customizing a Server's Config.
+type Option func(*Config)
+
+// WithReader returns an Option that sets the source of random bytes, typically a
+// prng.Interface returned by prng.NewReader.
+func WithReader(r io.Reader) Option {
+	return func(cfg *Config) {
+		cfg.Reader = r
+	}
+}
+
+// WithRateLimit returns an Option that limits each connection to bytesPerSecond, with
+// bursts of up to burst bytes.
+func WithRateLimit(bytesPerSecond, burst int) Option {
+	return func(cfg *Config) {
+		cfg.RateLimit = bytesPerSecond
+		cfg.Burst = burst
+	}
+}
+
+// WithMaxConnections returns an Option that sets the number of concurrent connections
+// served.
+func WithMaxConnections(n int) Option {
+	return func(cfg *Config) {
+		cfg.MaxConnections = n
+	}
+}
+
+// WithIdleTimeout returns an Option that sets how long a connection may stay idle.
+func WithIdleTimeout(d time.Duration) Option {
+	return func(cfg *Config) {
+		cfg.IdleTimeout = d
+	}
+}
+
+// WithSocketMode returns an Option that sets the permission of the socket file created
+// by ListenAndServe.
+func WithSocketMode(mode os.FileMode) Option {
+	return func(cfg *Config) {
+		cfg.SocketMode = mode
+	}
+}
+
+// WithLogger returns an Option that sets the structured logger.
+func WithLogger(l *slog.Logger) Option {
+	return func(cfg *Config) {
+		cfg.Logger = l
+	}
+}
+
+// newConfig applies opts to the default Config and validates the result.
+func newConfig(opts []Option) (Config, error) {
+	cfg := Config{
+		Reader:         prng.Reader,
+		MaxConnections: defaultMaxConnections,
+		IdleTimeout:    defaultIdleTimeout,
+		SocketMode:     defaultSocketMode,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	switch {
+	case cfg.Reader == nil:
+		return cfg, ErrNilReader
+	case cfg.RateLimit < 0:
+		return cfg, ErrRateLimitInvalid
+	case cfg.RateLimit > 0 && cfg.Burst < MaxRequestSize:
+		return cfg, ErrBurstInvalid
+	}
+	return cfg, nil
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package egd
+
+import (
+	"strconv"
+	"testing"
+)
+
+// BenchmarkClient_Read measures blocking reads over a Unix socket, which issue one
+// round trip per 255 bytes.
+func BenchmarkClient_Read(b *testing.B) {
+	for _, size := range []int{32, MaxRequestSize, 4096} {
+		b.Run("Bytes_"+strconv.Itoa(size), func(b *testing.B) {
+			_, path := serve(b, WithIdleTimeout(0))
+			c, err := Dial(path)
+			if err != nil {
+				b.Fatalf("Dial failed: %v", err)
+			}
+			defer c.Close()
+			buf := make([]byte, size)
+			b.SetBytes(int64(size))
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := c.Read(buf); err != nil {
+					b.Fatalf("Read failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkClient_EntropyCount measures the smallest request and response.
+func BenchmarkClient_EntropyCount(b *testing.B) {
+	_, path := serve(b, WithIdleTimeout(0))
+	c, err := Dial(path)
+	if err != nil {
+		b.Fatalf("Dial failed: %v", err)
+	}
+	defer c.Close()
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := c.EntropyCount(); err != nil {
+			b.Fatalf("EntropyCount failed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,440 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package egd
+
+import (
+	"errors"
+	"io"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/sixafter/prng-chacha"
+	"github.com/stretchr/testify/assert"
+)
+
+// socketPath returns a socket path in a fresh temporary directory. t.TempDir paths can
+// exceed the 104-byte limit on Unix socket addresses on some systems.
+func socketPath(t testing.TB) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "egd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	return filepath.Join(dir, "s")
+}
+
+// serve starts a Server configured by opts on a new socket and returns it with its path.
+// The server is closed when the test ends.
+func serve(t testing.TB, opts ...Option) (*Server, string) {
+	t.Helper()
+	srv, err := NewServer(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := socketPath(t)
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(l) }()
+	t.Cleanup(func() {
+		_ = srv.Close()
+		if err := <-done; !errors.Is(err, ErrServerClosed) {
+			t.Errorf("Serve returned %v", err)
+		}
+	})
+	return srv, path
+}
+
+// dial connects a Client to path, closed when the test ends.
+func dial(t *testing.T, path string) *Client {
+	t.Helper()
+	c, err := Dial(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+// seededStream returns the first n bytes of the seeded stream for seed.
+func seededStream(t *testing.T, seed []byte, n int) []byte {
+	t.Helper()
+	r, err := prng.NewSeededReader(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// closedByServer reports whether err is the server closing the connection: EOF, or a
+// reset if the server closed with request bytes still unread.
+func closedByServer(err error) bool {
+	return errors.Is(err, io.EOF) || errors.Is(err, syscall.ECONNRESET)
+}
+
+var errRead = errors.New("read failed")
+
+// errReader always fails.
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, errRead
+}
+
+// TestServer_Commands verifies every command against an unlimited server, using a
+// seeded reader so the bytes served can be checked.
+func TestServer_Commands(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	seed := make([]byte, prng.SeedSize)
+	seed[0] = 7
+	src, err := prng.NewSeededReader(seed)
+	is.NoError(err)
+	_, path := serve(t, WithReader(src))
+	c := dial(t, path)
+
+	bits, err := c.EntropyCount()
+	is.NoError(err)
+	is.Equal(uint32(math.MaxUint32), bits)
+
+	got := make([]byte, 1000)
+	buf := make([]byte, MaxRequestSize)
+	n, err := c.ReadNonBlocking(buf[:200])
+	is.NoError(err)
+	is.Equal(200, n)
+	copy(got, buf[:n])
+	n, err = c.Read(got[200:])
+	is.NoError(err)
+	is.Equal(800, n)
+	is.Equal(seededStream(t, seed, 1000), got, "bytes must come from the configured reader in order")
+
+	pid, err := c.PID()
+	is.NoError(err)
+	is.Equal(os.Getpid(), pid)
+
+	// The default reader is prng.Reader.
+	_, path = serve(t)
+	n, err = dial(t, path).Read(make([]byte, 4096))
+	is.NoError(err)
+	is.Equal(4096, n)
+}
+
+// TestServer_RateLimit verifies that the entropy count, non-blocking reads and blocking
+// reads follow each connection's token bucket.
+func TestServer_RateLimit(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	const rate, burst = 2000, 300
+	_, path := serve(t, WithRateLimit(rate, burst))
+	c := dial(t, path)
+
+	bits, err := c.EntropyCount()
+	is.NoError(err)
+	is.Equal(uint32(8*burst), bits)
+
+	buf := make([]byte, MaxRequestSize)
+	n, err := c.ReadNonBlocking(buf)
+	is.NoError(err)
+	is.Equal(MaxRequestSize, n)
+	n, err = c.ReadNonBlocking(buf)
+	is.NoError(err)
+	is.GreaterOrEqual(n, burst-MaxRequestSize)
+	is.Less(n, MaxRequestSize, "the bucket must be nearly empty")
+
+	// Refilling 255 bytes at 2000 bytes per second takes about 128ms.
+	start := time.Now()
+	n, err = c.Read(buf)
+	is.NoError(err)
+	is.Equal(MaxRequestSize, n)
+	is.GreaterOrEqual(time.Since(start), 100*time.Millisecond)
+
+	// Another connection has its own bucket.
+	bits, err = dial(t, path).EntropyCount()
+	is.NoError(err)
+	is.Equal(uint32(8*burst), bits)
+}
+
+// TestServer_ClosesConnection verifies that unsupported commands, reader failures and
+// idle clients close the connection.
+func TestServer_ClosesConnection(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	_, path := serve(t)
+	for _, req := range [][]byte{{cmdWriteEntropy, 0, 8, 1}, {0x7f}} {
+		conn, err := net.Dial("unix", path)
+		is.NoError(err)
+		_, err = conn.Write(req)
+		is.NoError(err)
+		_, err = conn.Read(make([]byte, 1))
+		is.True(closedByServer(err), "command %#x: %v", req[0], err)
+		_ = conn.Close()
+	}
+
+	_, path = serve(t, WithReader(errReader{}))
+	_, err := dial(t, path).Read(make([]byte, 16))
+	is.True(closedByServer(err), err)
+
+	_, path = serve(t, WithIdleTimeout(20*time.Millisecond))
+	c := dial(t, path)
+	time.Sleep(100 * time.Millisecond)
+	_, err = c.EntropyCount()
+	is.Error(err)
+}
+
+// TestServer_MaxConnections verifies that connections beyond the limit are closed and
+// that a slot frees up when a client disconnects.
+func TestServer_MaxConnections(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	_, path := serve(t, WithMaxConnections(1))
+	first := dial(t, path)
+	_, err := first.EntropyCount()
+	is.NoError(err)
+
+	_, err = dial(t, path).EntropyCount()
+	is.True(closedByServer(err), err)
+
+	is.NoError(first.Close())
+	is.Eventually(func() bool {
+		c, err := Dial(path)
+		if err != nil {
+			return false
+		}
+		defer c.Close()
+		_, err = c.EntropyCount()
+		return err == nil
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+// TestServer_Close verifies that Close ends Serve, disconnects clients, including one
+// waiting on the rate limit, and is idempotent.
+func TestServer_Close(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	srv, err := NewServer(WithRateLimit(1, MaxRequestSize))
+	is.NoError(err)
+	path := socketPath(t)
+	l, err := net.Listen("unix", path)
+	is.NoError(err)
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(l) }()
+
+	c := dial(t, path)
+	buf := make([]byte, MaxRequestSize)
+	_, err = c.Read(buf)
+	is.NoError(err)
+
+	// The bucket is empty and refills at 1 byte per second.
+	readErr := make(chan error, 1)
+	go func() {
+		_, err := c.Read(buf)
+		readErr <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	is.NoError(srv.Close())
+	is.ErrorIs(<-done, ErrServerClosed)
+	is.Error(<-readErr)
+	is.NoError(srv.Close())
+
+	l, err = net.Listen("unix", socketPath(t))
+	is.NoError(err)
+	is.ErrorIs(srv.Serve(l), ErrServerClosed)
+}
+
+// TestServer_ListenAndServe verifies the socket mode, stale socket removal and
+// detection of a running server.
+func TestServer_ListenAndServe(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	path := socketPath(t)
+
+	// Leave a stale socket behind.
+	l, err := net.Listen("unix", path)
+	is.NoError(err)
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	is.NoError(l.Close())
+	_, err = os.Lstat(path)
+	is.NoError(err)
+
+	srv, err := NewServer(WithSocketMode(0o660))
+	is.NoError(err)
+	done := make(chan error, 1)
+	go func() { done <- srv.ListenAndServe(path) }()
+	is.Eventually(func() bool {
+		c, err := Dial(path)
+		if err != nil {
+			return false
+		}
+		_ = c.Close()
+		return true
+	}, 2*time.Second, 10*time.Millisecond)
+
+	fi, err := os.Stat(path)
+	is.NoError(err)
+	is.Equal(os.FileMode(0o660), fi.Mode().Perm())
+
+	other, err := NewServer()
+	is.NoError(err)
+	is.ErrorIs(other.ListenAndServe(path), ErrAddressInUse)
+
+	is.NoError(srv.Close())
+	is.ErrorIs(<-done, ErrServerClosed)
+	_, err = os.Lstat(path)
+	is.True(os.IsNotExist(err), "the socket file must be removed")
+	entries, err := os.ReadDir(filepath.Dir(path))
+	is.NoError(err)
+	is.Empty(entries, "the private directory used to bind the socket must be removed")
+}
+
+// TestServer_ListenAndServeExistingFile verifies that ListenAndServe fails without
+// replacing a file that is not a socket.
+func TestServer_ListenAndServeExistingFile(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	path := socketPath(t)
+	is.NoError(os.WriteFile(path, []byte("keep"), 0o600))
+
+	srv, err := NewServer()
+	is.NoError(err)
+	is.ErrorIs(srv.ListenAndServe(path), os.ErrExist)
+
+	got, err := os.ReadFile(path)
+	is.NoError(err)
+	is.Equal("keep", string(got))
+	entries, err := os.ReadDir(filepath.Dir(path))
+	is.NoError(err)
+	is.Len(entries, 1, "the private directory used to bind the socket must be removed")
+}
+
+// TestServer_Concurrent serves many clients at once; run with -race.
+func TestServer_Concurrent(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	_, path := serve(t)
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c, err := Dial(path)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer c.Close()
+			buf := make([]byte, 10000)
+			if _, err := c.Read(buf); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// A Client is safe for concurrent use.
+	c := dial(t, path)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Read(make([]byte, 1000)); err != nil {
+				t.Error(err)
+			}
+			if _, err := c.EntropyCount(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	is.False(t.Failed())
+}
+
+// TestNewServer_Invalid verifies configuration validation.
+func TestNewServer_Invalid(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	_, err := NewServer(WithReader(nil))
+	is.ErrorIs(err, ErrNilReader)
+	_, err = NewServer(WithRateLimit(-1, 0))
+	is.ErrorIs(err, ErrRateLimitInvalid)
+	_, err = NewServer(WithRateLimit(100, MaxRequestSize-1))
+	is.ErrorIs(err, ErrBurstInvalid)
+	_, err = NewServer(WithRateLimit(0, 0))
+	is.NoError(err)
+}
+
+// TestClient_Invalid verifies request validation and malformed responses.
+func TestClient_Invalid(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	_, path := serve(t)
+	c := dial(t, path)
+	_, err := c.ReadNonBlocking(nil)
+	is.ErrorIs(err, ErrRequestSizeInvalid)
+	_, err = c.ReadNonBlocking(make([]byte, MaxRequestSize+1))
+	is.ErrorIs(err, ErrRequestSizeInvalid)
+	n, err := c.Read(nil)
+	is.NoError(err)
+	is.Zero(n)
+
+	// A server that claims more bytes than requested.
+	client, server := net.Pipe()
+	defer client.Close()
+	go func() {
+		defer server.Close()
+		req := make([]byte, 2)
+		if _, err := io.ReadFull(server, req); err == nil {
+			_, _ = server.Write([]byte{req[1] + 1})
+		}
+	}()
+	_, err = NewClient(client).ReadNonBlocking(make([]byte, 4))
+	is.ErrorIs(err, ErrMalformedResponse)
+
+	_, err = Dial(filepath.Join(filepath.Dir(path), "missing"))
+	is.Error(err)
+}
+
+// TestBucket verifies token accounting without real time.
+func TestBucket(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	is.Nil(newBucket(0, 0, time.Now()))
+
+	t0 := time.Unix(0, 0)
+	b := newBucket(100, 300, t0)
+	is.Equal(300, b.available(t0))
+	b.take(300)
+	is.Equal(0, b.available(t0))
+	is.Equal(time.Second, b.wait(100, t0))
+	is.Equal(50, b.available(t0.Add(500*time.Millisecond)))
+	is.Equal(500*time.Millisecond, b.wait(100, t0.Add(500*time.Millisecond)))
+	is.Zero(b.wait(100, t0.Add(time.Second)))
+	is.Equal(300, b.available(t0.Add(time.Hour)), "the bucket must not exceed its burst")
+}
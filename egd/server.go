@@ -0,0 +1,417 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package egd
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"log/slog"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Server serves the EGD protocol. It is safe for concurrent use; one Server may serve
+// several listeners.
+type Server struct {
+	cfg Config
+
+	mu        sync.Mutex
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+	closed    bool
+	done      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewServer returns a Server configured by opts.
+//
+// It returns ErrNilReader if the configured reader is nil, ErrRateLimitInvalid if the
+// rate limit is negative and ErrBurstInvalid if a rate limit is set with a burst below
+// MaxRequestSize.
+func NewServer(opts ...Option) (*Server, error) {
+	cfg, err := newConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Server{
+		cfg:       cfg,
+		listeners: make(map[net.Listener]struct{}),
+		conns:     make(map[net.Conn]struct{}),
+		done:      make(chan struct{}),
+	}, nil
+}
+
+// ListenAndServe listens on the Unix domain socket at path, with the configured
+// SocketMode, and serves connections until Close is called. If path already exists and
+// is a socket that no server accepts on, it is treated as stale, removed and the listen
+// retried once; ErrAddressInUse is returned if another server is still accepting on it,
+// and any other existing file is left in place. The socket file is removed when the
+// listener closes.
+//
+// The socket is bound in a new directory next to path that only the owner can enter,
+// given its mode there, and then linked to path, so it is never reachable with the
+// looser permissions the process umask would allow.
+//
+// ListenAndServe always returns a non-nil error; after Close it returns ErrServerClosed.
+func (s *Server) ListenAndServe(path string) error {
+	l, err := listenUnix(path, s.cfg.SocketMode)
+	if errors.Is(err, os.ErrExist) {
+		if stale, serr := staleSocket(path); serr != nil {
+			err = serr
+		} else if stale {
+			l, err = listenUnix(path, s.cfg.SocketMode)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+// staleSocket removes the file at path if it is a socket that no server accepts on and
+// reports whether it did. It returns ErrAddressInUse if a server accepts on it, and
+// false for any file that is not a socket.
+func staleSocket(path string) (bool, error) {
+	fi, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		// Removed since the failed listen; try again.
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if fi.Mode()&os.ModeSocket == 0 {
+		return false, nil
+	}
+	if c, err := net.Dial("unix", path); err == nil {
+		_ = c.Close()
+		return false, ErrAddressInUse
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, err
+	}
+	return true, nil
+}
+
+// listenUnix listens on a Unix domain socket created at path with the given mode. The
+// socket is bound and chmod'ed inside a private 0700 directory, then hard linked to path,
+// which fails rather than replace an existing file.
+func listenUnix(path string, mode os.FileMode) (net.Listener, error) {
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".egd-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	tmp := filepath.Join(dir, "s")
+	l, err := net.Listen("unix", tmp)
+	if err != nil {
+		return nil, err
+	}
+	// The listener's own name disappears with dir; path is removed by unixListener.
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	if err := os.Chmod(tmp, mode); err != nil {
+		_ = l.Close()
+		return nil, err
+	}
+	if err := os.Link(tmp, path); err != nil {
+		_ = l.Close()
+		return nil, err
+	}
+	return &unixListener{Listener: l, path: path}, nil
+}
+
+// unixListener removes its socket file when closed.
+type unixListener struct {
+	net.Listener
+	path string
+	once sync.Once
+}
+
+// Close closes the listener and removes the socket file on the first call.
+func (l *unixListener) Close() error {
+	err := l.Listener.Close()
+	l.once.Do(func() { _ = os.Remove(l.path) })
+	return err
+}
+
+// Serve accepts connections on l and serves each on its own goroutine until Close is
+// called. Serve closes l when it returns.
+//
+// Serve always returns a non-nil error; after Close it returns ErrServerClosed.
+func (s *Server) Serve(l net.Listener) error {
+	if !s.track(l) {
+		_ = l.Close()
+		return ErrServerClosed
+	}
+	defer s.untrack(l)
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if s.isClosed() {
+				return ErrServerClosed
+			}
+			var ne net.Error
+			if errors.As(err, &ne) && ne.Timeout() {
+				continue
+			}
+			return err
+		}
+		if !s.add(conn) {
+			_ = conn.Close()
+			continue
+		}
+		go s.serveConn(conn)
+	}
+}
+
+// Close stops all listeners, closes all connections, including those waiting on the
+// rate limit, and waits for their goroutines to finish. Subsequent calls return nil.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.done)
+	var err error
+	for l := range s.listeners {
+		if cerr := l.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	for c := range s.conns {
+		_ = c.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+// track registers l, reporting false if the server is closed.
+func (s *Server) track(l net.Listener) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	s.listeners[l] = struct{}{}
+	return true
+}
+
+// untrack closes and unregisters l.
+func (s *Server) untrack(l net.Listener) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.listeners, l)
+	_ = l.Close()
+}
+
+// isClosed reports whether Close has been called.
+func (s *Server) isClosed() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// add registers conn, reporting false if the server is closed or at MaxConnections.
+func (s *Server) add(conn net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	if s.cfg.MaxConnections > 0 && len(s.conns) >= s.cfg.MaxConnections {
+		s.log(slog.LevelWarn, "egd: connection limit reached", slog.Int("max_connections", s.cfg.MaxConnections))
+		return false
+	}
+	s.conns[conn] = struct{}{}
+	s.wg.Add(1)
+	return true
+}
+
+// remove closes and unregisters conn.
+func (s *Server) remove(conn net.Conn) {
+	s.mu.Lock()
+	delete(s.conns, conn)
+	s.mu.Unlock()
+	_ = conn.Close()
+	s.wg.Done()
+}
+
+// log writes a record if a logger is configured.
+func (s *Server) log(level slog.Level, msg string, attrs ...slog.Attr) {
+	if s.cfg.Logger != nil {
+		s.cfg.Logger.LogAttrs(context.Background(), level, msg, attrs...)
+	}
+}
+
+// serveConn handles requests on conn until the client disconnects, sends an
+// unsupported command, idles out or the server closes.
+func (s *Server) serveConn(conn net.Conn) {
+	defer s.remove(conn)
+
+	var (
+		limit = newBucket(s.cfg.RateLimit, s.cfg.Burst, time.Now())
+		req   [2]byte
+		out   [1 + MaxRequestSize]byte
+	)
+	defer clear(out[:])
+
+	for {
+		s.deadline(conn)
+		if _, err := io.ReadFull(conn, req[:1]); err != nil {
+			s.connError(err)
+			return
+		}
+
+		var resp []byte
+		switch req[0] {
+		case cmdEntropyCount:
+			bits := uint64(math.MaxUint32)
+			if limit != nil {
+				bits = min(8*uint64(limit.available(time.Now())), bits)
+			}
+			resp = binary.BigEndian.AppendUint32(out[:0], uint32(bits))
+
+		case cmdReadNonBlock, cmdReadBlock:
+			if _, err := io.ReadFull(conn, req[1:2]); err != nil {
+				s.connError(err)
+				return
+			}
+			n := int(req[1])
+			if req[0] == cmdReadNonBlock {
+				if limit != nil {
+					n = min(n, limit.available(time.Now()))
+				}
+				out[0] = byte(n)
+				resp = out[:1+n]
+			} else {
+				if limit != nil && !s.wait(limit, n) {
+					return
+				}
+				resp = out[:n]
+			}
+			if _, err := io.ReadFull(s.cfg.Reader, resp[len(resp)-n:]); err != nil {
+				s.log(slog.LevelError, "egd: reader failed", slog.Any("error", err))
+				return
+			}
+			if limit != nil {
+				limit.take(n)
+			}
+
+		case cmdPID:
+			pid := strconv.Itoa(os.Getpid())
+			resp = append(append(out[:0], byte(len(pid))), pid...)
+
+		case cmdWriteEntropy:
+			s.log(slog.LevelWarn, "egd: write entropy is not supported")
+			return
+
+		default:
+			s.log(slog.LevelWarn, "egd: unsupported command", slog.Int("command", int(req[0])))
+			return
+		}
+
+		// A blocking read may have waited on the rate limit; restart the timeout for the write.
+		s.deadline(conn)
+		if _, err := conn.Write(resp); err != nil {
+			s.connError(err)
+			return
+		}
+	}
+}
+
+// deadline applies the idle timeout to the next request and its response.
+func (s *Server) deadline(conn net.Conn) {
+	if s.cfg.IdleTimeout > 0 {
+		_ = conn.SetDeadline(time.Now().Add(s.cfg.IdleTimeout))
+	}
+}
+
+// wait blocks until limit holds n tokens, reporting false if the server closed first.
+func (s *Server) wait(limit *bucket, n int) bool {
+	for {
+		d := limit.wait(n, time.Now())
+		if d <= 0 {
+			return true
+		}
+		t := time.NewTimer(d)
+		select {
+		case <-t.C:
+		case <-s.done:
+			t.Stop()
+			return false
+		}
+	}
+}
+
+// connError logs a connection error unless it is the client disconnecting or the
+// server closing.
+func (s *Server) connError(err error) {
+	if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) || s.isClosed() {
+		return
+	}
+	s.log(slog.LevelDebug, "egd: connection closed", slog.Any("error", err))
+}
+
+// bucket is a per-connection token bucket holding up to burst bytes and refilled at
+// rate bytes per second. It is used by one goroutine only.
+type bucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newBucket returns a full bucket, or nil if rate is zero (unlimited).
+func newBucket(rate, burst int, now time.Time) *bucket {
+	if rate == 0 {
+		return nil
+	}
+	return &bucket{rate: float64(rate), burst: float64(burst), tokens: float64(burst), last: now}
+}
+
+// refill adds the tokens accrued since the last refill.
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = min(b.burst, b.tokens+elapsed*b.rate)
+	}
+	b.last = now
+}
+
+// available returns the whole tokens in the bucket.
+func (b *bucket) available(now time.Time) int {
+	b.refill(now)
+	return int(b.tokens)
+}
+
+// wait returns how long until the bucket holds n tokens, or zero if it already does.
+func (b *bucket) wait(n int, now time.Time) time.Duration {
+	b.refill(now)
+	if missing := float64(n) - b.tokens; missing > 0 {
+		return time.Duration(math.Ceil(missing / b.rate * float64(time.Second)))
+	}
+	return 0
+}
+
+// take removes n tokens.
+func (b *bucket) take(n int) {
+	b.tokens -= float64(n)
+}
@@ -0,0 +1,369 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package fill writes random data from the prng package over files, disk images and
+// other io.WriterAt destinations at disk speed, for example to overwrite temporary
+// files before deletion.
+//
+// Random bytes are generated into a large reusable buffer by several goroutines at
+// once, each issuing its own Read so that concurrent reads are spread across the
+// reader's pool shards, while the previous buffer is being written. Each pass
+// overwrites the destination from the start; Sync flushes every pass to stable storage
+// before the next begins.
+//
+// Overwriting a file is not a guarantee that its previous contents are unrecoverable:
+// journaling and copy-on-write file systems, SSD wear leveling and snapshots may keep
+// old copies of the data.
+//
+// Example:
+//
+//	err := fill.File(ctx, "/tmp/secrets.db",
+//	    fill.WithPasses(3),
+//	    fill.WithSync(true),
+//	    fill.WithProgress(func(p fill.Progress) {
+//	        log.Printf("pass %d/%d: %d of %d bytes", p.Pass, p.Passes, p.PassWritten, p.Size)
+//	    }),
+//	)
+package fill
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+
+	"github.com/sixafter/prng-chacha"
+)
+
+var (
+	ErrNilReader         = fmt.Errorf("fill: reader must not be nil")
+	ErrPassesInvalid     = fmt.Errorf("fill: passes must be greater than zero")
+	ErrBufferSizeInvalid = fmt.Errorf("fill: buffer size must be greater than zero")
+	ErrWorkersInvalid    = fmt.Errorf("fill: workers must not be negative")
+	ErrSizeInvalid       = fmt.Errorf("fill: size must not be negative")
+)
+
+const (
+	// DefaultBufferSize is the default size of each of the two generation buffers.
+	DefaultBufferSize = 4 << 20
+
+	// minSegment is the smallest share of a buffer given to a worker; smaller segments
+	// cost more in goroutine handoffs than they gain.
+	minSegment = 64 << 10
+)
+
+// Progress reports how far a fill has advanced.
+type Progress struct {
+	// Pass is the current pass, starting at 1, and Passes the total number of passes.
+	Pass   int
+	Passes int
+
+	// PassWritten is the number of bytes written in the current pass, out of Size.
+	PassWritten int64
+	Size        int64
+
+	// Done reports that the current pass is complete, and synced if Sync is set.
+	Done bool
+}
+
+// Config defines the source, passes and buffering of a fill.
+type Config struct {
+	// Reader is the source of random bytes. Defaults to prng.Reader. It must be safe
+	// for concurrent use unless Workers is 1.
+	Reader io.Reader
+
+	// Passes is the number of times the destination is overwritten. Defaults to 1.
+	Passes int
+
+	// BufferSize is the size in bytes of each of the two buffers alternately generated
+	// into and written from. Defaults to DefaultBufferSize.
+	BufferSize int
+
+	// Workers is the number of goroutines generating each buffer. Defaults to the
+	// reader's Shards if it is a prng.Interface, and to runtime.GOMAXPROCS(0)
+	// otherwise. With more than one worker, the order in which the stream is laid out
+	// is unspecified; use one worker to reproduce a seeded stream.
+	Workers int
+
+	// Sync flushes the destination to stable storage after each pass, when it has a
+	// Sync method, as *os.File does.
+	Sync bool
+
+	// Size is the number of bytes File writes. Zero, the default, overwrites the
+	// file's current length; a larger size extends or creates the file. WriterAt takes
+	// its size as an argument instead.
+	Size int64
+
+	// Progress, if set, is called from the writing goroutine after every buffer written
+	// and at the end of every pass.
+	Progress func(Progress)
+}
+
+// Option defines a functional option for customizing a fill's Config.
+type Option func(*Config)
+
+// WithReader returns an Option that sets the source of random bytes.
+func WithReader(r io.Reader) Option {
+	return func(cfg *Config) {
+		cfg.Reader = r
+	}
+}
+
+// WithPasses returns an Option that sets the number of overwrite passes.
+func WithPasses(n int) Option {
+	return func(cfg *Config) {
+		cfg.Passes = n
+	}
+}
+
+// WithBufferSize returns an Option that sets the size of each generation buffer.
+func WithBufferSize(n int) Option {
+	return func(cfg *Config) {
+		cfg.BufferSize = n
+	}
+}
+
+// WithWorkers returns an Option that sets the number of generating goroutines.
+func WithWorkers(n int) Option {
+	return func(cfg *Config) {
+		cfg.Workers = n
+	}
+}
+
+// WithSync returns an Option that flushes the destination after each pass.
+func WithSync(enabled bool) Option {
+	return func(cfg *Config) {
+		cfg.Sync = enabled
+	}
+}
+
+// WithSize returns an Option that sets the number of bytes File writes.
+func WithSize(n int64) Option {
+	return func(cfg *Config) {
+		cfg.Size = n
+	}
+}
+
+// WithProgress returns an Option that sets the progress callback.
+func WithProgress(fn func(Progress)) Option {
+	return func(cfg *Config) {
+		cfg.Progress = fn
+	}
+}
+
+// newConfig applies opts to the default Config and validates the result.
+func newConfig(opts []Option) (Config, error) {
+	cfg := Config{
+		Reader:     prng.Reader,
+		Passes:     1,
+		BufferSize: DefaultBufferSize,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	switch {
+	case cfg.Reader == nil:
+		return cfg, ErrNilReader
+	case cfg.Passes <= 0:
+		return cfg, ErrPassesInvalid
+	case cfg.BufferSize <= 0:
+		return cfg, ErrBufferSizeInvalid
+	case cfg.Workers < 0:
+		return cfg, ErrWorkersInvalid
+	case cfg.Size < 0:
+		return cfg, ErrSizeInvalid
+	}
+	if cfg.Workers == 0 {
+		cfg.Workers = runtime.GOMAXPROCS(0)
+		if r, ok := cfg.Reader.(prng.Interface); ok {
+			cfg.Workers = r.Config().Shards
+		}
+	}
+	return cfg, nil
+}
+
+// File overwrites the file at path with random data, as configured by opts. Without
+// WithSize it overwrites the file's current length, which for a block device is the
+// device size; with WithSize it writes that many bytes, creating the file with mode
+// 0600 if necessary. The file is never truncated.
+//
+// It returns a configuration error, an error opening, writing or syncing the file, the
+// reader's error, or ctx.Err() if ctx is canceled.
+func File(ctx context.Context, path string, opts ...Option) error {
+	cfg, err := newConfig(opts)
+	if err != nil {
+		return err
+	}
+
+	flag := os.O_WRONLY
+	if cfg.Size > 0 {
+		flag |= os.O_CREATE
+	}
+	f, err := os.OpenFile(path, flag, 0o600)
+	if err != nil {
+		return err
+	}
+
+	size := cfg.Size
+	if size == 0 {
+		// Seeking to the end also measures block devices, whose Stat size is zero.
+		if size, err = f.Seek(0, io.SeekEnd); err != nil {
+			_ = f.Close()
+			return err
+		}
+	}
+	if err := fill(ctx, f, size, cfg); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// WriterAt overwrites the first size bytes of w with random data, as configured by
+// opts, and calls w.Sync after each pass if WithSync is set and w has a Sync() error
+// method.
+//
+// It returns a configuration error, w's error, the reader's error, or ctx.Err() if ctx
+// is canceled.
+func WriterAt(ctx context.Context, w io.WriterAt, size int64, opts ...Option) error {
+	cfg, err := newConfig(opts)
+	if err != nil {
+		return err
+	}
+	if size < 0 {
+		return ErrSizeInvalid
+	}
+	return fill(ctx, w, size, cfg)
+}
+
+// syncer is implemented by destinations that can flush to stable storage.
+type syncer interface {
+	Sync() error
+}
+
+// fill runs cfg.Passes passes over the first size bytes of w.
+func fill(ctx context.Context, w io.WriterAt, size int64, cfg Config) error {
+	bufs := [2][]byte{make([]byte, cfg.BufferSize), make([]byte, cfg.BufferSize)}
+	defer func() {
+		clear(bufs[0])
+		clear(bufs[1])
+	}()
+
+	for pass := 1; pass <= cfg.Passes; pass++ {
+		p := Progress{Pass: pass, Passes: cfg.Passes, Size: size}
+		if err := writePass(ctx, w, cfg, bufs, &p); err != nil {
+			return err
+		}
+		if s, ok := w.(syncer); ok && cfg.Sync {
+			if err := s.Sync(); err != nil {
+				return err
+			}
+		}
+		if cfg.Progress != nil {
+			p.Done = true
+			cfg.Progress(p)
+		}
+	}
+	return nil
+}
+
+// filled is a buffer generated for writing at off.
+type filled struct {
+	buf []byte
+	off int64
+	err error
+}
+
+// writePass writes one pass of p.Size bytes, generating the next buffer while the
+// current one is written.
+func writePass(ctx context.Context, w io.WriterAt, cfg Config, bufs [2][]byte, p *Progress) error {
+	ctx, cancel := context.WithCancel(ctx)
+	ready := make(chan filled)
+	// On an early return, stop the generator and wait for it to let go of the buffers.
+	defer func() {
+		cancel()
+		for range ready {
+		}
+	}()
+
+	free := make(chan []byte, len(bufs))
+	for _, b := range bufs {
+		free <- b
+	}
+
+	go func() {
+		defer close(ready)
+		for off := int64(0); off < p.Size; {
+			var buf []byte
+			select {
+			case buf = <-free:
+			case <-ctx.Done():
+				return
+			}
+			buf = buf[:min(int64(len(buf)), p.Size-off)]
+			err := generate(cfg.Reader, buf, cfg.Workers)
+			select {
+			case ready <- filled{buf: buf, off: off, err: err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+			off += int64(len(buf))
+		}
+	}()
+
+	for f := range ready {
+		if f.err != nil {
+			return f.err
+		}
+		if _, err := w.WriteAt(f.buf, f.off); err != nil {
+			return err
+		}
+		p.PassWritten += int64(len(f.buf))
+		if cfg.Progress != nil {
+			cfg.Progress(*p)
+		}
+		free <- f.buf[:cap(f.buf)]
+	}
+	return ctx.Err()
+}
+
+// generate fills buf from r, splitting it into one segment per worker read
+// concurrently.
+func generate(r io.Reader, buf []byte, workers int) error {
+	workers = min(workers, max(1, len(buf)/minSegment))
+	if workers == 1 {
+		_, err := io.ReadFull(r, buf)
+		return err
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	seg := (len(buf) + workers - 1) / workers
+	for start := 0; start < len(buf); start += seg {
+		part := buf[start:min(start+seg, len(buf))]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := io.ReadFull(r, part); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return firstErr
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package fill
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// discardAt is an io.WriterAt that drops its input, isolating generation speed.
+type discardAt struct{}
+
+func (discardAt) WriteAt(p []byte, _ int64) (int, error) {
+	return len(p), nil
+}
+
+// BenchmarkWriterAt measures generation throughput for increasing worker counts.
+func BenchmarkWriterAt(b *testing.B) {
+	const size = 64 << 20
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run("Workers_"+strconv.Itoa(workers), func(b *testing.B) {
+			b.SetBytes(size)
+			b.ReportAllocs()
+			for b.Loop() {
+				if err := WriterAt(context.Background(), discardAt{}, size, WithWorkers(workers)); err != nil {
+					b.Fatalf("WriterAt failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkFile measures overwriting a temp file, including the file system.
+func BenchmarkFile(b *testing.B) {
+	const size = 16 << 20
+	path := filepath.Join(b.TempDir(), "bench")
+	if err := os.WriteFile(path, make([]byte, size), 0o600); err != nil {
+		b.Fatalf("WriteFile failed: %v", err)
+	}
+	b.SetBytes(size)
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := File(context.Background(), path); err != nil {
+			b.Fatalf("File failed: %v", err)
+		}
+	}
+}
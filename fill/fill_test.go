@@ -0,0 +1,258 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package fill
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/sixafter/prng-chacha"
+	"github.com/stretchr/testify/assert"
+)
+
+// memFile is an in-memory io.WriterAt that counts Sync calls and can fail on demand.
+type memFile struct {
+	mu       sync.Mutex
+	data     []byte
+	syncs    int
+	writeErr error
+	syncErr  error
+}
+
+func (m *memFile) WriteAt(p []byte, off int64) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.writeErr != nil {
+		return 0, m.writeErr
+	}
+	if end := int(off) + len(p); end > len(m.data) {
+		m.data = append(m.data, make([]byte, end-len(m.data))...)
+	}
+	return copy(m.data[off:], p), nil
+}
+
+func (m *memFile) Sync() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.syncs++
+	return m.syncErr
+}
+
+var errTest = errors.New("test failure")
+
+// errReader always fails.
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, errTest
+}
+
+// hasZeroRun reports whether b contains a run of 64 zero bytes, which random data
+// essentially never does.
+func hasZeroRun(b []byte) bool {
+	return bytes.Contains(b, make([]byte, 64))
+}
+
+// TestFile_Overwrite verifies that every byte of an existing file is overwritten on each
+// pass without changing its length, and the progress reported.
+func TestFile_Overwrite(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "secret")
+	const size = 1<<20 + 123
+	is.NoError(os.WriteFile(path, make([]byte, size), 0o600))
+
+	var progress []Progress
+	err := File(context.Background(), path,
+		WithPasses(3),
+		WithBufferSize(128<<10),
+		WithWorkers(4),
+		WithSync(true),
+		WithProgress(func(p Progress) { progress = append(progress, p) }),
+	)
+	is.NoError(err)
+
+	got, err := os.ReadFile(path)
+	is.NoError(err)
+	is.Len(got, size)
+	is.False(hasZeroRun(got))
+
+	// Nine buffers per pass, then the pass summary.
+	is.Len(progress, 3*10)
+	var done []Progress
+	for i, p := range progress {
+		is.Equal(3, p.Passes)
+		is.Equal(int64(size), p.Size)
+		if p.Done {
+			done = append(done, p)
+			continue
+		}
+		if i > 0 && !progress[i-1].Done {
+			is.Greater(p.PassWritten, progress[i-1].PassWritten)
+		}
+	}
+	is.Equal([]Progress{
+		{Pass: 1, Passes: 3, PassWritten: size, Size: size, Done: true},
+		{Pass: 2, Passes: 3, PassWritten: size, Size: size, Done: true},
+		{Pass: 3, Passes: 3, PassWritten: size, Size: size, Done: true},
+	}, done)
+}
+
+// TestFile_Size verifies creating a file of a given size and overwriting only a prefix
+// of a longer file.
+func TestFile_Size(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "new.img")
+	is.NoError(File(context.Background(), path, WithSize(300000)))
+	got, err := os.ReadFile(path)
+	is.NoError(err)
+	is.Len(got, 300000)
+	is.False(hasZeroRun(got))
+	fi, err := os.Stat(path)
+	is.NoError(err)
+	is.Equal(os.FileMode(0o600), fi.Mode().Perm())
+
+	path = filepath.Join(dir, "long")
+	orig := bytes.Repeat([]byte{0xAA}, 10000)
+	is.NoError(os.WriteFile(path, orig, 0o600))
+	is.NoError(File(context.Background(), path, WithSize(4000)))
+	got, err = os.ReadFile(path)
+	is.NoError(err)
+	is.Len(got, 10000, "the file must not be truncated")
+	is.NotEqual(orig[:4000], got[:4000])
+	is.Equal(orig[4000:], got[4000:])
+
+	// An empty file is left empty.
+	path = filepath.Join(dir, "empty")
+	is.NoError(os.WriteFile(path, nil, 0o600))
+	is.NoError(File(context.Background(), path))
+	fi, err = os.Stat(path)
+	is.NoError(err)
+	is.Zero(fi.Size())
+
+	// Without a size, the file must exist.
+	err = File(context.Background(), filepath.Join(dir, "missing"))
+	is.ErrorIs(err, os.ErrNotExist)
+}
+
+// TestWriterAt_Seeded verifies that one worker lays out a seeded stream in order across
+// buffers, and that every pass continues the stream.
+func TestWriterAt_Seeded(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	seed := make([]byte, prng.SeedSize)
+	src, err := prng.NewSeededReader(seed)
+	is.NoError(err)
+	var m memFile
+	is.NoError(WriterAt(context.Background(), &m, 2500,
+		WithReader(src), WithWorkers(1), WithBufferSize(1000), WithPasses(2), WithSync(true)))
+
+	want, err := prng.NewSeededReader(seed)
+	is.NoError(err)
+	stream := make([]byte, 5000)
+	_, err = io.ReadFull(want, stream)
+	is.NoError(err)
+	is.Equal(stream[2500:], m.data, "the last pass must hold the second 2500 bytes")
+	is.Equal(2, m.syncs)
+}
+
+// TestWriterAt_Parallel verifies that several workers together produce every byte and
+// spread their reads across shards.
+func TestWriterAt_Parallel(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	r, err := prng.NewReader(prng.WithShards(4))
+	is.NoError(err)
+	var m memFile
+	is.NoError(WriterAt(context.Background(), &m, 3<<20, WithReader(r), WithBufferSize(1<<20)))
+	is.Len(m.data, 3<<20)
+	is.False(hasZeroRun(m.data))
+	is.Equal(uint64(3<<20), r.(prng.StatsReporter).Stats().BytesGenerated)
+
+	var busy int
+	for _, s := range r.(prng.StatsReporter).Stats().Shards {
+		if s.BytesGenerated > 0 {
+			busy++
+		}
+	}
+	is.Greater(busy, 1, "reads must be spread across shards")
+	is.Zero(m.syncs, "Sync is only called with WithSync")
+}
+
+// TestWriterAt_Errors verifies that reader, writer, sync and context errors stop the
+// fill, and configuration validation.
+func TestWriterAt_Errors(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+	ctx := context.Background()
+
+	is.ErrorIs(WriterAt(ctx, &memFile{}, 1<<20, WithReader(errReader{}), WithBufferSize(64<<10)), errTest)
+	is.ErrorIs(WriterAt(ctx, &memFile{}, 1<<20, WithReader(errReader{}), WithWorkers(4)), errTest)
+	is.ErrorIs(WriterAt(ctx, &memFile{writeErr: errTest}, 1<<20, WithBufferSize(64<<10)), errTest)
+	is.ErrorIs(WriterAt(ctx, &memFile{syncErr: errTest}, 100, WithSync(true)), errTest)
+
+	// Cancel after the first buffer is written.
+	cctx, cancel := context.WithCancel(ctx)
+	var m memFile
+	var calls int
+	err := WriterAt(cctx, &m, 1<<20, WithBufferSize(64<<10), WithPasses(2), WithProgress(func(Progress) {
+		calls++
+		cancel()
+	}))
+	is.ErrorIs(err, context.Canceled)
+	is.LessOrEqual(calls, 2)
+	is.Less(len(m.data), 1<<20)
+
+	for _, tc := range []struct {
+		opts []Option
+		err  error
+	}{
+		{[]Option{WithReader(nil)}, ErrNilReader},
+		{[]Option{WithPasses(0)}, ErrPassesInvalid},
+		{[]Option{WithBufferSize(0)}, ErrBufferSizeInvalid},
+		{[]Option{WithWorkers(-1)}, ErrWorkersInvalid},
+		{[]Option{WithSize(-1)}, ErrSizeInvalid},
+	} {
+		is.ErrorIs(WriterAt(ctx, &memFile{}, 10, tc.opts...), tc.err)
+		is.ErrorIs(File(ctx, filepath.Join(t.TempDir(), "x"), tc.opts...), tc.err)
+	}
+	is.ErrorIs(WriterAt(ctx, &memFile{}, -1), ErrSizeInvalid)
+}
+
+// TestGenerate verifies that splitting a buffer across workers covers it exactly.
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	for _, n := range []int{1, minSegment - 1, 2 * minSegment, 5*minSegment + 7} {
+		for _, workers := range []int{1, 3, 8} {
+			buf := make([]byte, n)
+			is.NoError(generate(prng.Reader, buf, workers))
+			if n >= 64 {
+				is.False(hasZeroRun(buf), "n=%d workers=%d", n, workers)
+			}
+		}
+	}
+
+	cfg, err := newConfig(nil)
+	is.NoError(err)
+	is.Equal(prng.Reader.(prng.Interface).Config().Shards, cfg.Workers)
+	cfg, err = newConfig([]Option{WithReader(bytes.NewReader(nil))})
+	is.NoError(err)
+	is.Positive(cfg.Workers)
+}
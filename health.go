@@ -0,0 +1,151 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package prng
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ErrHealthTestFailed is matched, via errors.Is, by every HealthTestError.
+var ErrHealthTestFailed = fmt.Errorf("prng: entropy source health test failed")
+
+// Names of the SP 800-90B continuous health tests, reported in HealthTestError.Test.
+const (
+	HealthTestRepetitionCount    = "repetition count"
+	HealthTestAdaptiveProportion = "adaptive proportion"
+)
+
+// Health test parameters, from NIST SP 800-90B section 4.4 with byte-sized samples, a
+// claimed min-entropy of H = 8 bits per sample (crypto/rand output is full entropy) and
+// a false positive probability of α = 2^-40 per sample.
+const (
+	// rctCutoff is the Repetition Count Test cutoff, 1 + ⌈-log2(α)/H⌉ = 1 + ⌈40/8⌉: a
+	// value repeated this many times in a row fails.
+	rctCutoff = 6
+
+	// aptWindow is the Adaptive Proportion Test window size for non-binary samples.
+	aptWindow = 512
+
+	// aptCutoff is the Adaptive Proportion Test cutoff, 1 + CRITBINOM(512, 2^-8, 1-α): a
+	// window in which its first value occurs this many times fails.
+	aptCutoff = 19
+)
+
+// HealthTestError reports that entropy read to seed a cipher failed a continuous health
+// test, indicating a stuck or badly degraded entropy source.
+//
+// It is returned, wrapped, by NewReader when no instance can be seeded, and passed to
+// Observer.OnInitFailed and Observer.OnRekeyFailed when a reseed fails. Use errors.As to
+// inspect it, or errors.Is with ErrHealthTestFailed to detect it.
+type HealthTestError struct {
+	// Test is HealthTestRepetitionCount or HealthTestAdaptiveProportion.
+	Test string
+
+	// Value is the sample value that occurred too often.
+	Value byte
+
+	// Count is the number of occurrences that reached Cutoff.
+	Count int
+
+	// Cutoff is the test's failure threshold.
+	Cutoff int
+}
+
+// Error implements the error interface.
+func (e *HealthTestError) Error() string {
+	return fmt.Sprintf("prng: %s health test failed: value 0x%02x occurred %d times (cutoff %d)",
+		e.Test, e.Value, e.Count, e.Cutoff)
+}
+
+// Unwrap returns ErrHealthTestFailed.
+func (e *HealthTestError) Unwrap() error {
+	return ErrHealthTestFailed
+}
+
+// healthTests holds the state of the continuous health tests over every byte of entropy
+// a reader consumes. State carries over from one reseed to the next, since a single
+// reseed reads far fewer bytes than an Adaptive Proportion Test window.
+//
+// The zero value is ready to use and safe for concurrent use.
+type healthTests struct {
+	mu sync.Mutex
+
+	// rctValue and rctCount are the current run's value and length; rctCount is zero
+	// before the first sample.
+	rctValue byte
+	rctCount int
+
+	// aptValue is the first value of the current window, aptCount its occurrences so far
+	// and aptSeen the number of samples in the window; aptSeen is zero between windows.
+	aptValue byte
+	aptCount int
+	aptSeen  int
+}
+
+// check runs both tests over samples. On failure it returns a *HealthTestError and
+// resets both tests, so that a retried reseed is judged on fresh samples only.
+func (h *healthTests) check(samples []byte) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, b := range samples {
+		// Repetition Count Test (SP 800-90B section 4.4.1).
+		if h.rctCount > 0 && b == h.rctValue {
+			h.rctCount++
+			if h.rctCount >= rctCutoff {
+				err := &HealthTestError{Test: HealthTestRepetitionCount, Value: b, Count: h.rctCount, Cutoff: rctCutoff}
+				h.reset()
+				return err
+			}
+		} else {
+			h.rctValue, h.rctCount = b, 1
+		}
+
+		// Adaptive Proportion Test (SP 800-90B section 4.4.2).
+		if h.aptSeen == 0 {
+			h.aptValue, h.aptCount = b, 1
+		} else if b == h.aptValue {
+			h.aptCount++
+			if h.aptCount >= aptCutoff {
+				err := &HealthTestError{Test: HealthTestAdaptiveProportion, Value: b, Count: h.aptCount, Cutoff: aptCutoff}
+				h.reset()
+				return err
+			}
+		}
+		h.aptSeen++
+		if h.aptSeen == aptWindow {
+			h.aptSeen = 0
+		}
+	}
+	return nil
+}
+
+// reset discards the state of both tests. The caller must hold h.mu.
+func (h *healthTests) reset() {
+	h.rctValue, h.rctCount = 0, 0
+	h.aptValue, h.aptCount, h.aptSeen = 0, 0, 0
+}
+
+// healthReader passes entropy from src through the health tests. Bytes from a Read that
+// fails a test are wiped and not returned.
+type healthReader struct {
+	src   io.Reader
+	tests *healthTests
+}
+
+// Read implements io.Reader.
+func (h *healthReader) Read(b []byte) (int, error) {
+	n, err := h.src.Read(b)
+	if n > 0 {
+		if herr := h.tests.check(b[:n]); herr != nil {
+			clear(b[:n])
+			return 0, herr
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,212 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package prng
+
+import (
+	"crypto/rand"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stuckSource is an entropy source stuck at a single byte value.
+type stuckSource byte
+
+func (s stuckSource) Read(b []byte) (int, error) {
+	for i := range b {
+		b[i] = byte(s)
+	}
+	return len(b), nil
+}
+
+// alternatingSource alternates between two byte values: it never repeats a value, so it
+// passes the Repetition Count Test, but carries only one bit of entropy per byte.
+type alternatingSource struct {
+	n atomic.Uint64
+}
+
+func (s *alternatingSource) Read(b []byte) (int, error) {
+	for i := range b {
+		b[i] = byte(1 + s.n.Add(1)%2)
+	}
+	return len(b), nil
+}
+
+// Test_Health_RepetitionCount verifies that a stuck-at source fails instance creation
+// with a typed error once a value repeats rctCutoff times.
+func Test_Health_RepetitionCount(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	obs := &recordingObserver{}
+	cfg := DefaultConfig()
+	cfg.Shards = 1
+	cfg.HealthTests = true
+	cfg.Observer = obs
+	r := newReader(&cfg)
+	r.entropy = stuckSource(0xaa)
+
+	p, err := r.newInstance(0)
+	is.Nil(p)
+	is.ErrorIs(err, ErrHealthTestFailed)
+	var herr *HealthTestError
+	if is.ErrorAs(err, &herr) {
+		is.Equal(HealthTestRepetitionCount, herr.Test)
+		is.Equal(byte(0xaa), herr.Value)
+		is.Equal(rctCutoff, herr.Count)
+		is.Equal(rctCutoff, herr.Cutoff)
+	}
+
+	obs.mu.Lock()
+	defer obs.mu.Unlock()
+	is.Equal([]int{0}, obs.initFailed)
+	is.ErrorIs(obs.initFailures[0], ErrHealthTestFailed)
+}
+
+// Test_Health_AdaptiveProportion verifies that a low-entropy source that never repeats
+// a value fails the Adaptive Proportion Test, on every initialization attempt.
+func Test_Health_AdaptiveProportion(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	cfg := DefaultConfig()
+	cfg.Shards = 1
+	cfg.HealthTests = true
+	r := newReader(&cfg)
+	r.entropy = &alternatingSource{}
+
+	p, err := r.newInstance(0)
+	is.Nil(p)
+	var herr *HealthTestError
+	if is.ErrorAs(err, &herr) {
+		is.Equal(HealthTestAdaptiveProportion, herr.Test)
+		is.Equal(aptCutoff, herr.Count)
+		is.Equal(aptCutoff, herr.Cutoff)
+	}
+}
+
+// Test_Health_RekeyFailed verifies that entropy failing a health test during a rekey
+// leaves the existing cipher in place and reports each failed attempt to the Observer.
+func Test_Health_RekeyFailed(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	obs := &recordingObserver{}
+	cfg := DefaultConfig()
+	cfg.Shards = 1
+	cfg.EnableKeyRotation = true
+	cfg.HealthTests = true
+	cfg.MaxRekeyAttempts = 2
+	cfg.RekeyBackoff = time.Millisecond
+	cfg.MaxRekeyBackoff = 2 * time.Millisecond
+	cfg.Observer = obs
+	r := newReader(&cfg)
+
+	p, err := newPRNG(r, 0)
+	is.NoError(err)
+
+	// Break the entropy source only after the instance has been seeded.
+	r.entropy = stuckSource(0)
+	atomic.StoreUint32(&p.rekeying, 1)
+	p.asyncRekey()
+
+	is.Nil(p.pending.Load(), "no cipher should be seeded from failing entropy")
+	is.Equal(uint64(2), r.Stats().RekeyFailures)
+
+	obs.mu.Lock()
+	defer obs.mu.Unlock()
+	is.Empty(obs.rotated)
+	is.Len(obs.rekeyFailed, 2)
+	for _, f := range obs.rekeyFailed {
+		is.ErrorIs(f.err, ErrHealthTestFailed)
+	}
+}
+
+// Test_Health_Disabled verifies that health tests are opt-in: without them a stuck
+// source is accepted.
+func Test_Health_Disabled(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	cfg := DefaultConfig()
+	cfg.Shards = 1
+	r := newReader(&cfg)
+	r.entropy = stuckSource(0)
+
+	p, err := r.newInstance(0)
+	is.NoError(err)
+	is.NotNil(p)
+}
+
+// Test_Health_State verifies that test state carries across reads, that a failure
+// resets it, and that bytes from a failing read are wiped.
+func Test_Health_State(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	var h healthTests
+	is.NoError(h.check([]byte{7, 7, 7}))
+	is.ErrorIs(h.check([]byte{7, 7, 7}), ErrHealthTestFailed, "runs continue across reads")
+	is.NoError(h.check([]byte{7, 7, 7}), "state is reset after a failure")
+
+	// The window's first value may occur aptCutoff-1 times without failing, and the
+	// count restarts with the next window.
+	h = healthTests{}
+	window := make([]byte, aptWindow)
+	for i := 1; i < len(window); i++ {
+		window[i] = byte(1 + i%250)
+	}
+	for i := 0; i < aptCutoff-2; i++ {
+		window[1+i*20] = 0
+	}
+	is.NoError(h.check(window))
+	is.NoError(h.check(window))
+
+	hr := &healthReader{src: stuckSource(9), tests: &healthTests{}}
+	buf := make([]byte, 16)
+	n, err := hr.Read(buf)
+	is.Zero(n)
+	is.ErrorIs(err, ErrHealthTestFailed)
+	is.Equal(make([]byte, 16), buf)
+}
+
+// Test_Health_SystemEntropy verifies that crypto/rand output passes the health tests and
+// that a reader with health tests enabled works normally, including after Update.
+func Test_Health_SystemEntropy(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	var h healthTests
+	buf := make([]byte, 1<<16)
+	for i := 0; i < 16; i++ {
+		_, _ = rand.Read(buf)
+		is.NoError(h.check(buf))
+	}
+
+	rdr, err := NewReader(WithHealthTests(true))
+	is.NoError(err)
+	is.True(rdr.Config().HealthTests)
+	_, err = rdr.Read(buf)
+	is.NoError(err)
+
+	rdr, err = NewReader()
+	is.NoError(err)
+	is.NoError(rdr.(Updater).Update(WithHealthTests(true)))
+	is.True(rdr.Config().HealthTests)
+}
+
+// Test_HealthTestError verifies the error message and errors.Is matching.
+func Test_HealthTestError(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	var err error = &HealthTestError{Test: HealthTestRepetitionCount, Value: 0xff, Count: 6, Cutoff: 6}
+	is.Equal("prng: repetition count health test failed: value 0xff occurred 6 times (cutoff 6)", err.Error())
+	is.True(errors.Is(err, ErrHealthTestFailed))
+}
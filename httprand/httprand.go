@@ -0,0 +1,471 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package httprand serves random bytes, integers, UUIDs and tokens from a prng reader
+// over HTTP, as a local stand-in for services such as random.org in test harnesses.
+//
+// A Handler serves GET and HEAD requests on the following paths, relative to where it
+// is mounted (use http.StripPrefix to mount it under a prefix):
+//
+//	/bytes?n=32&encoding=hex           n random bytes; encoding is binary, hex or base64
+//	/int?min=1&max=6&n=10              n integers drawn uniformly from [min, max]
+//	/uuid?version=7&n=5                n version 4 or version 7 UUIDs
+//	/token?length=21&alphabet=abc&n=5  n tokens; entropy=bits sizes them instead of length
+//
+// Every parameter is optional except max. Unknown or repeated parameters, malformed
+// values and requests above the configured limits are rejected with 400 Bad Request.
+//
+// Responses are negotiated from the Accept header. Lists are served as text/plain, one
+// value per line, or as application/json ({"data": [...]}). Bytes are served as
+// text/plain or application/json in hex or base64, or as application/octet-stream, which
+// is also selected by encoding=binary. Without an Accept header, text/plain is served.
+// A request no representation satisfies is answered with 406 Not Acceptable. Responses
+// carry Cache-Control: no-store.
+//
+// Example:
+//
+//	r, _ := prng.NewReader()
+//	h, err := httprand.New(r, httprand.WithMaxBytes(64<<10))
+//	if err != nil {
+//	    // handle error
+//	}
+//	http.Handle("/random/", http.StripPrefix("/random", h))
+package httprand
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/sixafter/prng-chacha"
+	"github.com/sixafter/prng-chacha/internal/randutil"
+	"github.com/sixafter/prng-chacha/token"
+	"github.com/sixafter/prng-chacha/uuid"
+)
+
+var (
+	ErrNilSource    = fmt.Errorf("httprand: source must not be nil")
+	ErrLimitInvalid = fmt.Errorf("httprand: limits must be greater than zero")
+)
+
+// Default limits.
+const (
+	DefaultMaxBytes       = 1 << 20
+	DefaultMaxCount       = 1000
+	DefaultMaxTokenLength = 1024
+)
+
+// Media types.
+const (
+	mediaText   = "text/plain"
+	mediaJSON   = "application/json"
+	mediaBinary = "application/octet-stream"
+)
+
+// Byte encodings.
+const (
+	encodingBinary = "binary"
+	encodingHex    = "hex"
+	encodingBase64 = "base64"
+)
+
+// Config defines the per-request limits of a Handler.
+type Config struct {
+	// MaxBytes is the largest n accepted by /bytes. Defaults to DefaultMaxBytes.
+	MaxBytes int
+
+	// MaxCount is the largest n accepted by /int, /uuid and /token. Defaults to
+	// DefaultMaxCount.
+	MaxCount int
+
+	// MaxTokenLength is the longest token, in characters, served by /token, whether
+	// requested by length or by entropy. Defaults to DefaultMaxTokenLength.
+	MaxTokenLength int
+}
+
+// Option defines a functional option for customizing a Handler's Config.
+type Option func(*Config)
+
+// WithMaxBytes returns an Option that sets the largest byte count served per request.
+func WithMaxBytes(n int) Option {
+	return func(cfg *Config) {
+		cfg.MaxBytes = n
+	}
+}
+
+// WithMaxCount returns an Option that sets the largest number of values served per
+// request.
+func WithMaxCount(n int) Option {
+	return func(cfg *Config) {
+		cfg.MaxCount = n
+	}
+}
+
+// WithMaxTokenLength returns an Option that sets the longest token served.
+func WithMaxTokenLength(n int) Option {
+	return func(cfg *Config) {
+		cfg.MaxTokenLength = n
+	}
+}
+
+// Handler serves random values from a prng.Interface. It implements http.Handler and
+// is safe for concurrent use.
+type Handler struct {
+	src   prng.Interface
+	cfg   Config
+	uuids *uuid.Generator
+}
+
+// New returns a Handler serving values from src, configured by opts.
+//
+// It returns ErrNilSource if src is nil and ErrLimitInvalid if a limit is not positive.
+func New(src prng.Interface, opts ...Option) (*Handler, error) {
+	cfg := Config{
+		MaxBytes:       DefaultMaxBytes,
+		MaxCount:       DefaultMaxCount,
+		MaxTokenLength: DefaultMaxTokenLength,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if src == nil {
+		return nil, ErrNilSource
+	}
+	if cfg.MaxBytes <= 0 || cfg.MaxCount <= 0 || cfg.MaxTokenLength <= 0 {
+		return nil, ErrLimitInvalid
+	}
+	uuids, err := uuid.NewGenerator(uuid.WithReader(src))
+	if err != nil {
+		return nil, err
+	}
+	return &Handler{src: src, cfg: cfg, uuids: uuids}, nil
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	var serve func(http.ResponseWriter, *http.Request, url.Values) error
+	var params []string
+	switch r.URL.Path {
+	case "/bytes":
+		serve, params = h.serveBytes, []string{"n", "encoding"}
+	case "/int":
+		serve, params = h.serveInt, []string{"n", "min", "max"}
+	case "/uuid":
+		serve, params = h.serveUUID, []string{"n", "version"}
+	case "/token":
+		serve, params = h.serveToken, []string{"n", "length", "entropy", "alphabet"}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	q, err := parseQuery(r.URL.RawQuery, params)
+	if err == nil {
+		err = serve(w, r, q)
+	}
+	if err != nil {
+		status := http.StatusInternalServerError
+		var se *statusError
+		if errors.As(err, &se) {
+			status = se.status
+		}
+		http.Error(w, err.Error(), status)
+	}
+}
+
+// statusError is an error reported to the client with an HTTP status other than 500.
+type statusError struct {
+	status int
+	msg    string
+}
+
+// Error implements the error interface.
+func (e *statusError) Error() string {
+	return e.msg
+}
+
+// badRequest returns a 400 statusError with a formatted message.
+func badRequest(format string, a ...any) error {
+	return &statusError{status: http.StatusBadRequest, msg: fmt.Sprintf(format, a...)}
+}
+
+// errNotAcceptable is returned when no representation satisfies the Accept header.
+var errNotAcceptable = &statusError{status: http.StatusNotAcceptable, msg: http.StatusText(http.StatusNotAcceptable)}
+
+// parseQuery parses raw, rejecting malformed queries and parameters that are not in
+// allowed or appear more than once.
+func parseQuery(raw string, allowed []string) (url.Values, error) {
+	q, err := url.ParseQuery(raw)
+	if err != nil {
+		return nil, badRequest("malformed query: %v", err)
+	}
+	for name, values := range q {
+		if !slices.Contains(allowed, name) {
+			return nil, badRequest("unknown parameter %q", name)
+		}
+		if len(values) > 1 {
+			return nil, badRequest("parameter %q is repeated", name)
+		}
+	}
+	return q, nil
+}
+
+// intParam returns the named parameter as an integer in [lo, hi], or def if it is
+// absent.
+func intParam(q url.Values, name string, def, lo, hi int64) (int64, error) {
+	if !q.Has(name) {
+		return def, nil
+	}
+	v, err := strconv.ParseInt(q.Get(name), 10, 64)
+	if err != nil {
+		return 0, badRequest("%s must be an integer", name)
+	}
+	if v < lo || v > hi {
+		return 0, badRequest("%s must be between %d and %d", name, lo, hi)
+	}
+	return v, nil
+}
+
+// count returns the n parameter, which defaults to 1 and is bounded by MaxCount.
+func (h *Handler) count(q url.Values) (int, error) {
+	n, err := intParam(q, "n", 1, 1, int64(h.cfg.MaxCount))
+	return int(n), err
+}
+
+// begin negotiates the representation from offers, sets the response headers and
+// reports whether the body should be written (false for HEAD requests).
+func begin(w http.ResponseWriter, r *http.Request, offers ...string) (string, bool, error) {
+	media := negotiate(r.Header.Get("Accept"), offers)
+	if media == "" {
+		return "", false, errNotAcceptable
+	}
+	hdr := w.Header()
+	hdr.Set("Cache-Control", "no-store")
+	hdr.Set("X-Content-Type-Options", "nosniff")
+	hdr.Add("Vary", "Accept")
+	if media == mediaBinary {
+		hdr.Set("Content-Type", media)
+	} else {
+		hdr.Set("Content-Type", media+"; charset=utf-8")
+	}
+	return media, r.Method != http.MethodHead, nil
+}
+
+// writeList writes values as lines of text or as a JSON array, using text to format
+// each value for text/plain.
+func writeList[T any](w io.Writer, media string, values []T, text func([]byte, T) []byte) error {
+	if media == mediaJSON {
+		return json.NewEncoder(w).Encode(struct {
+			Data []T `json:"data"`
+		}{values})
+	}
+	var buf []byte
+	for _, v := range values {
+		buf = append(text(buf, v), '\n')
+	}
+	_, err := w.Write(buf)
+	return err
+}
+
+// serveBytes serves /bytes.
+func (h *Handler) serveBytes(w http.ResponseWriter, r *http.Request, q url.Values) error {
+	n, err := intParam(q, "n", 32, 1, int64(h.cfg.MaxBytes))
+	if err != nil {
+		return err
+	}
+
+	encoding := q.Get("encoding")
+	var media string
+	var write bool
+	switch encoding {
+	case encodingBinary:
+		media, write, err = begin(w, r, mediaBinary)
+	case encodingHex, encodingBase64:
+		media, write, err = begin(w, r, mediaText, mediaJSON)
+	case "":
+		media, write, err = begin(w, r, mediaText, mediaJSON, mediaBinary)
+		encoding = encodingHex
+	default:
+		return badRequest("encoding must be binary, hex or base64")
+	}
+	if err != nil || !write {
+		return err
+	}
+
+	b := make([]byte, n)
+	defer clear(b)
+	if _, err := io.ReadFull(h.src, b); err != nil {
+		return err
+	}
+	if media == mediaBinary {
+		_, err = w.Write(b)
+		return err
+	}
+
+	var s string
+	if encoding == encodingBase64 {
+		s = base64.StdEncoding.EncodeToString(b)
+	} else {
+		s = hex.EncodeToString(b)
+	}
+	if media == mediaJSON {
+		return json.NewEncoder(w).Encode(struct {
+			Data     string `json:"data"`
+			Encoding string `json:"encoding"`
+		}{s, encoding})
+	}
+	_, err = io.WriteString(w, s+"\n")
+	return err
+}
+
+// serveInt serves /int.
+func (h *Handler) serveInt(w http.ResponseWriter, r *http.Request, q url.Values) error {
+	n, err := h.count(q)
+	if err != nil {
+		return err
+	}
+	if !q.Has("max") {
+		return badRequest("max is required")
+	}
+	lo, err := intParam(q, "min", 0, -1<<63, 1<<63-1)
+	if err != nil {
+		return err
+	}
+	hi, err := intParam(q, "max", 0, -1<<63, 1<<63-1)
+	if err != nil {
+		return err
+	}
+	if lo > hi {
+		return badRequest("min must not be greater than max")
+	}
+	media, write, err := begin(w, r, mediaText, mediaJSON)
+	if err != nil || !write {
+		return err
+	}
+
+	rd := randutil.NewReader(h.src)
+	defer rd.Wipe()
+	// span wraps to zero when the range covers every int64.
+	span := uint64(hi-lo) + 1
+	values := make([]int64, n)
+	for i := range values {
+		var v uint64
+		if span == 0 {
+			v, err = rd.Uint64()
+		} else {
+			v, err = rd.Uint64n(span)
+		}
+		if err != nil {
+			return err
+		}
+		values[i] = lo + int64(v)
+	}
+	return writeList(w, media, values, appendInt)
+}
+
+// serveUUID serves /uuid.
+func (h *Handler) serveUUID(w http.ResponseWriter, r *http.Request, q url.Values) error {
+	n, err := h.count(q)
+	if err != nil {
+		return err
+	}
+	version, err := intParam(q, "version", 4, 4, 7)
+	if err != nil || (version != 4 && version != 7) {
+		return badRequest("version must be 4 or 7")
+	}
+	media, write, err := begin(w, r, mediaText, mediaJSON)
+	if err != nil || !write {
+		return err
+	}
+
+	values := make([]string, n)
+	for i := range values {
+		var u uuid.UUID
+		if version == 7 {
+			u, err = h.uuids.NewV7()
+		} else {
+			u, err = h.uuids.NewV4()
+		}
+		if err != nil {
+			return err
+		}
+		values[i] = u.String()
+	}
+	return writeList(w, media, values, appendString)
+}
+
+// serveToken serves /token.
+func (h *Handler) serveToken(w http.ResponseWriter, r *http.Request, q url.Values) error {
+	n, err := h.count(q)
+	if err != nil {
+		return err
+	}
+	if q.Has("length") && q.Has("entropy") {
+		return badRequest("length and entropy are mutually exclusive")
+	}
+
+	opts := []token.Option{token.WithReader(h.src)}
+	if q.Has("alphabet") {
+		opts = append(opts, token.WithAlphabet(q.Get("alphabet")))
+	}
+	if q.Has("length") {
+		length, err := intParam(q, "length", 0, 1, int64(h.cfg.MaxTokenLength))
+		if err != nil {
+			return err
+		}
+		opts = append(opts, token.WithLength(int(length)))
+	}
+	if q.Has("entropy") {
+		bits, err := intParam(q, "entropy", 0, 1, int64(h.cfg.MaxTokenLength)*32)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, token.WithEntropy(int(bits)))
+	}
+	g, err := token.New(opts...)
+	if err != nil {
+		return badRequest("%s", strings.TrimPrefix(err.Error(), "token: "))
+	}
+	if g.Length() > h.cfg.MaxTokenLength {
+		return badRequest("tokens must not be longer than %d characters", h.cfg.MaxTokenLength)
+	}
+	media, write, err := begin(w, r, mediaText, mediaJSON)
+	if err != nil || !write {
+		return err
+	}
+
+	values := make([]string, n)
+	for i := range values {
+		if values[i], err = g.Generate(); err != nil {
+			return err
+		}
+	}
+	return writeList(w, media, values, appendString)
+}
+
+// appendInt appends the decimal form of v to b.
+func appendInt(b []byte, v int64) []byte {
+	return strconv.AppendInt(b, v, 10)
+}
+
+// appendString appends s to b.
+func appendString(b []byte, s string) []byte {
+	return append(b, s...)
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package httprand
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sixafter/prng-chacha"
+)
+
+// BenchmarkHandler measures each endpoint through ServeHTTP, excluding the network.
+func BenchmarkHandler(b *testing.B) {
+	r, err := prng.NewReader()
+	if err != nil {
+		b.Fatalf("NewReader failed: %v", err)
+	}
+	h, err := New(r)
+	if err != nil {
+		b.Fatalf("New failed: %v", err)
+	}
+	for _, bc := range []struct {
+		name, target, accept string
+	}{
+		{"Bytes_Hex_32", "/bytes?n=32", ""},
+		{"Bytes_Binary_64K", "/bytes?n=65536&encoding=binary", ""},
+		{"Int_100", "/int?max=100&n=100", ""},
+		{"UUID_JSON_100", "/uuid?n=100", "application/json"},
+		{"Token_100", "/token?n=100", ""},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			req := httptest.NewRequest(http.MethodGet, bc.target, nil)
+			if bc.accept != "" {
+				req.Header.Set("Accept", bc.accept)
+			}
+			b.ReportAllocs()
+			for b.Loop() {
+				rec := httptest.NewRecorder()
+				h.ServeHTTP(rec, req)
+				if rec.Code != http.StatusOK {
+					b.Fatalf("status %d: %s", rec.Code, rec.Body)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,398 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package httprand
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sixafter/prng-chacha"
+	"github.com/sixafter/prng-chacha/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// seededSource serves a seeded stream through prng.Interface, so responses can be
+// checked byte for byte.
+type seededSource struct {
+	prng.Interface
+	r     io.Reader
+	reads atomic.Int64
+}
+
+func (s *seededSource) Read(b []byte) (int, error) {
+	s.reads.Add(1)
+	return s.r.Read(b)
+}
+
+// newSeededSource returns a seededSource for seed, and a second reader producing the
+// same stream for comparison.
+func newSeededSource(t *testing.T, seed byte) (*seededSource, io.Reader) {
+	t.Helper()
+	key := make([]byte, prng.SeedSize)
+	key[0] = seed
+	r, err := prng.NewSeededReader(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := prng.NewSeededReader(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rdr, err := prng.NewReader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &seededSource{Interface: rdr, r: r}, want
+}
+
+var errRead = errors.New("read failed")
+
+// errSource always fails to read.
+type errSource struct {
+	prng.Interface
+}
+
+func (errSource) Read([]byte) (int, error) {
+	return 0, errRead
+}
+
+// get serves a GET request for target with the given Accept header.
+func get(h http.Handler, target, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+// newHandler returns a Handler over a new reader.
+func newHandler(t *testing.T, opts ...Option) *Handler {
+	t.Helper()
+	r, err := prng.NewReader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := New(r, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+// lines splits a text/plain list response.
+func lines(body string) []string {
+	return strings.Split(strings.TrimSuffix(body, "\n"), "\n")
+}
+
+// TestBytes verifies every encoding and representation against the seeded stream.
+func TestBytes(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	src, want := newSeededSource(t, 1)
+	h, err := New(src)
+	is.NoError(err)
+	next := func(n int) []byte {
+		b := make([]byte, n)
+		_, err := io.ReadFull(want, b)
+		is.NoError(err)
+		return b
+	}
+
+	rec := get(h, "/bytes", "")
+	is.Equal(http.StatusOK, rec.Code)
+	is.Equal("text/plain; charset=utf-8", rec.Header().Get("Content-Type"))
+	is.Equal("no-store", rec.Header().Get("Cache-Control"))
+	is.Equal(hex.EncodeToString(next(32))+"\n", rec.Body.String())
+
+	rec = get(h, "/bytes?n=100&encoding=base64", "")
+	is.Equal(base64.StdEncoding.EncodeToString(next(100))+"\n", rec.Body.String())
+
+	rec = get(h, "/bytes?n=10&encoding=binary", "")
+	is.Equal("application/octet-stream", rec.Header().Get("Content-Type"))
+	is.Equal(next(10), rec.Body.Bytes())
+
+	rec = get(h, "/bytes?n=10", "application/octet-stream")
+	is.Equal("application/octet-stream", rec.Header().Get("Content-Type"))
+	is.Equal(next(10), rec.Body.Bytes())
+
+	rec = get(h, "/bytes?n=16&encoding=base64", "application/json")
+	is.Equal("application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+	var body struct {
+		Data     string `json:"data"`
+		Encoding string `json:"encoding"`
+	}
+	is.NoError(json.Unmarshal(rec.Body.Bytes(), &body))
+	is.Equal("base64", body.Encoding)
+	is.Equal(base64.StdEncoding.EncodeToString(next(16)), body.Data)
+
+	rec = get(h, "/bytes?n=16&encoding=binary", "text/plain")
+	is.Equal(http.StatusNotAcceptable, rec.Code)
+}
+
+// TestInt verifies range, uniformity and the full int64 range.
+func TestInt(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	h := newHandler(t)
+	counts := make(map[string]int)
+	const draws = 6000
+	for i := 0; i < draws/1000; i++ {
+		rec := get(h, "/int?min=1&max=6&n=1000", "")
+		is.Equal(http.StatusOK, rec.Code)
+		for _, l := range lines(rec.Body.String()) {
+			counts[l]++
+		}
+	}
+	is.Len(counts, 6)
+	var chi2 float64
+	for face := 1; face <= 6; face++ {
+		d := float64(counts[strconv.Itoa(face)]) - draws/6
+		chi2 += d * d / (draws / 6)
+	}
+	// 5 degrees of freedom; critical value at p = 0.0001 is about 25.7.
+	is.Less(chi2, 25.7)
+
+	rec := get(h, "/int?min=-5&max=-5&n=3", "application/json")
+	var body struct {
+		Data []int64 `json:"data"`
+	}
+	is.NoError(json.Unmarshal(rec.Body.Bytes(), &body))
+	is.Equal([]int64{-5, -5, -5}, body.Data)
+
+	rec = get(h, "/int?min=-9223372036854775808&max=9223372036854775807&n=100", "")
+	is.Equal(http.StatusOK, rec.Code)
+	var negative int
+	for _, l := range lines(rec.Body.String()) {
+		v, err := strconv.ParseInt(l, 10, 64)
+		is.NoError(err)
+		if v < 0 {
+			negative++
+		}
+	}
+	is.Greater(negative, 10)
+	is.Less(negative, 90)
+}
+
+// TestUUID verifies versions and representations.
+func TestUUID(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	h := newHandler(t)
+	rec := get(h, "/uuid?n=5", "")
+	is.Equal(http.StatusOK, rec.Code)
+	for _, l := range lines(rec.Body.String()) {
+		u, err := uuid.Parse(l)
+		is.NoError(err)
+		is.Equal(4, u.Version())
+	}
+
+	rec = get(h, "/uuid?version=7&n=3", "application/json")
+	var body struct {
+		Data []string `json:"data"`
+	}
+	is.NoError(json.Unmarshal(rec.Body.Bytes(), &body))
+	is.Len(body.Data, 3)
+	for _, s := range body.Data {
+		u, err := uuid.Parse(s)
+		is.NoError(err)
+		is.Equal(7, u.Version())
+	}
+}
+
+// TestToken verifies alphabets, lengths and entropy sizing.
+func TestToken(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	h := newHandler(t, WithMaxTokenLength(64))
+	rec := get(h, "/token?alphabet=xyz&length=40&n=4", "")
+	is.Equal(http.StatusOK, rec.Code)
+	got := lines(rec.Body.String())
+	is.Len(got, 4)
+	for _, l := range got {
+		is.Len(l, 40)
+		is.Empty(strings.Trim(l, "xyz"))
+	}
+
+	// 128 bits over 16 characters takes 32 characters.
+	rec = get(h, "/token?alphabet=0123456789abcdef&entropy=128", "")
+	is.Len(strings.TrimSpace(rec.Body.String()), 32)
+
+	rec = get(h, "/token", "")
+	is.Equal(http.StatusOK, rec.Code)
+	is.NotEmpty(strings.TrimSpace(rec.Body.String()))
+
+	// 512 bits over 2 characters would take 512 characters.
+	rec = get(h, "/token?alphabet=01&entropy=512", "")
+	is.Equal(http.StatusBadRequest, rec.Code)
+	is.Contains(rec.Body.String(), "longer than 64")
+
+	rec = get(h, "/token?alphabet=aa", "")
+	is.Equal(http.StatusBadRequest, rec.Code)
+	is.Contains(rec.Body.String(), "duplicate")
+}
+
+// TestValidation verifies rejected requests and their status codes.
+func TestValidation(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	h := newHandler(t, WithMaxBytes(64), WithMaxCount(10))
+	for target, status := range map[string]int{
+		"/bytes?n=0":                   http.StatusBadRequest,
+		"/bytes?n=65":                  http.StatusBadRequest,
+		"/bytes?n=x":                   http.StatusBadRequest,
+		"/bytes?encoding=base32":       http.StatusBadRequest,
+		"/bytes?n=1&n=2":               http.StatusBadRequest,
+		"/bytes?size=4":                http.StatusBadRequest,
+		"/bytes?%zz":                   http.StatusBadRequest,
+		"/int?min=1":                   http.StatusBadRequest,
+		"/int?min=2&max=1":             http.StatusBadRequest,
+		"/int?max=9223372036854775808": http.StatusBadRequest,
+		"/int?max=1&n=11":              http.StatusBadRequest,
+		"/uuid?version=5":              http.StatusBadRequest,
+		"/uuid?version=x":              http.StatusBadRequest,
+		"/token?length=4&entropy=64":   http.StatusBadRequest,
+		"/token?length=0":              http.StatusBadRequest,
+		"/token?length=2000":           http.StatusBadRequest,
+		"/token?alphabet=a":            http.StatusBadRequest,
+		"/token?alphabet=%ff%fe":       http.StatusBadRequest,
+		"/":                            http.StatusNotFound,
+		"/bytes/":                      http.StatusNotFound,
+		"/bytes?n=64":                  http.StatusOK,
+		"/int?max=1&n=10":              http.StatusOK,
+	} {
+		rec := get(h, target, "")
+		is.Equal(status, rec.Code, "%s: %s", target, rec.Body.String())
+	}
+
+	for _, target := range []string{"/int?max=1", "/uuid", "/token"} {
+		is.Equal(http.StatusNotAcceptable, get(h, target, "application/octet-stream").Code, target)
+		is.Equal(http.StatusNotAcceptable, get(h, target, "text/*;q=0, application/json;q=0").Code, target)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/bytes", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	is.Equal(http.StatusMethodNotAllowed, rec.Code)
+	is.Equal("GET, HEAD", rec.Header().Get("Allow"))
+}
+
+// TestHead verifies that HEAD returns the negotiated headers without reading from the
+// source.
+func TestHead(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	src, _ := newSeededSource(t, 2)
+	h, err := New(src)
+	is.NoError(err)
+	for _, target := range []string{"/bytes?n=1024", "/int?max=10&n=100", "/token?n=100"} {
+		req := httptest.NewRequest(http.MethodHead, target, nil)
+		req.Header.Set("Accept", "application/json")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		is.Equal(http.StatusOK, rec.Code)
+		is.Equal("application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+		is.Zero(rec.Body.Len())
+	}
+	is.Zero(src.reads.Load())
+}
+
+// TestSourceError verifies that a failing source is a 500.
+func TestSourceError(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	h, err := New(errSource{})
+	is.NoError(err)
+	for _, target := range []string{"/bytes", "/int?max=10", "/token"} {
+		rec := get(h, target, "")
+		is.Equal(http.StatusInternalServerError, rec.Code, target)
+		is.Contains(rec.Body.String(), errRead.Error())
+	}
+}
+
+// TestServer mounts the handler under a prefix on a real server.
+func TestServer(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	mux := http.NewServeMux()
+	mux.Handle("/random/", http.StripPrefix("/random", newHandler(t)))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/random/bytes?n=8", nil)
+	is.NoError(err)
+	req.Header.Set("Accept", "application/octet-stream, */*;q=0.1")
+	resp, err := srv.Client().Do(req)
+	is.NoError(err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	is.NoError(err)
+	is.Equal(http.StatusOK, resp.StatusCode)
+	is.Equal("application/octet-stream", resp.Header.Get("Content-Type"))
+	is.Len(body, 8)
+	is.Equal("Accept", resp.Header.Get("Vary"))
+}
+
+// TestNegotiate verifies media range matching, specificity and quality values.
+func TestNegotiate(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	offers := []string{mediaText, mediaJSON, mediaBinary}
+	for accept, want := range map[string]string{
+		"":                                      mediaText,
+		"*/*":                                   mediaText,
+		"*":                                     mediaText,
+		"application/json":                      mediaJSON,
+		"application/*":                         mediaJSON,
+		"application/octet-stream":              mediaBinary,
+		"text/html, application/json;q=0.5":     mediaJSON,
+		"*/*;q=0.1, application/octet-stream":   mediaBinary,
+		"application/*;q=0.9, text/plain;q=0.8": mediaJSON,
+		"text/plain;q=0, */*":                   mediaJSON,
+		"TEXT/PLAIN":                            mediaText,
+		"text/plain; charset=utf-8":             mediaText,
+		"image/png":                             "",
+		"text/plain;q=0":                        "",
+		"text/plain;q=2, application/json":      mediaJSON,
+		"bogus, application/json":               mediaJSON,
+	} {
+		is.Equal(want, negotiate(accept, offers), "Accept: %s", accept)
+	}
+}
+
+// TestNew_Invalid verifies configuration validation.
+func TestNew_Invalid(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	_, err := New(nil)
+	is.ErrorIs(err, ErrNilSource)
+	r, err := prng.NewReader()
+	is.NoError(err)
+	for _, opt := range []Option{WithMaxBytes(0), WithMaxCount(-1), WithMaxTokenLength(0)} {
+		_, err = New(r, opt)
+		is.ErrorIs(err, ErrLimitInvalid)
+	}
+}
@@ -0,0 +1,14 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+//go:build !race
+
+// Package race reports whether the race detector is enabled, for tests whose exact
+// allocation counts do not hold under it: sync.Pool randomly drops items when the race
+// detector is on.
+package race
+
+// Enabled reports whether the race detector is enabled.
+const Enabled = false
@@ -0,0 +1,11 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+//go:build race
+
+package race
+
+// Enabled reports whether the race detector is enabled.
+const Enabled = true
@@ -0,0 +1,67 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package randutil
+
+import (
+	"io"
+	"runtime"
+	"sync"
+)
+
+// Batches hands out fixed-size chunks of random bytes from pooled batches, so that each
+// read of the source serves many callers. It is safe for concurrent use.
+//
+// The unread bytes of a batch are the output of future calls. Consumed bytes are cleared
+// as they are handed out, and a batch dropped by the pool is cleared when it is garbage
+// collected rather than left in memory until the allocation is reused.
+type Batches struct {
+	src   io.Reader
+	chunk int
+	pool  sync.Pool
+}
+
+// batch is a buffer of random bytes consumed one chunk at a time.
+type batch struct {
+	buf []byte
+	pos int
+}
+
+// NewBatches returns Batches that read n chunks of chunk bytes from src at a time.
+func NewBatches(src io.Reader, chunk, n int) *Batches {
+	b := &Batches{src: src, chunk: chunk}
+	size := chunk * n
+	b.pool.New = func() any {
+		bt := &batch{buf: make([]byte, size), pos: size}
+		runtime.AddCleanup(bt, clearBatch, bt.buf)
+		return bt
+	}
+	return b
+}
+
+// clearBatch clears the buffer of a batch dropped by the pool.
+func clearBatch(buf []byte) {
+	clear(buf)
+}
+
+// Read fills dst, which must be one chunk long, from a pooled batch, refilling the batch
+// from the source when it is exhausted. It returns an error only if the source fails.
+func (b *Batches) Read(dst []byte) error {
+	bt := b.pool.Get().(*batch)
+	defer b.pool.Put(bt)
+
+	if bt.pos == len(bt.buf) {
+		if _, err := io.ReadFull(b.src, bt.buf); err != nil {
+			clear(bt.buf)
+			return err
+		}
+		bt.pos = 0
+	}
+	chunk := bt.buf[bt.pos : bt.pos+b.chunk]
+	copy(dst, chunk)
+	clear(chunk)
+	bt.pos += b.chunk
+	return nil
+}
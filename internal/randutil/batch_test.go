@@ -0,0 +1,77 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package randutil
+
+import (
+	"bytes"
+	"io"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sixafter/prng-chacha/internal/race"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBatches_Read verifies that chunks are served in source order with one source read
+// per batch, that consumed bytes are cleared from the batch, and that source errors are
+// returned.
+func TestBatches_Read(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	src := make([]byte, 3*4)
+	for i := range src {
+		src[i] = byte(i + 1)
+	}
+	// With one chunk per batch every Read refills, so the order holds even if the pool
+	// drops a batch.
+	cr := &countingReader{r: bytes.NewReader(src)}
+	b := NewBatches(cr, 4, 1)
+	dst := make([]byte, 4)
+	for i := 0; i < 3; i++ {
+		is.NoError(b.Read(dst))
+		is.Equal(src[4*i:4*i+4], dst)
+	}
+	is.Equal(3, cr.calls)
+	is.ErrorIs(b.Read(dst), io.EOF)
+
+	is.ErrorIs(NewBatches(iotestErrReader{}, 4, 2).Read(dst), errRead)
+
+	// sync.Pool drops items at random under the race detector, so the pooled batch
+	// can only be inspected without it.
+	if race.Enabled {
+		return
+	}
+	b = NewBatches(bytes.NewReader(src), 4, 2)
+	is.NoError(b.Read(dst))
+	bt := b.pool.Get().(*batch)
+	is.Equal(make([]byte, 4), bt.buf[:4], "consumed bytes should be cleared")
+	is.Equal(src[4:8], bt.buf[4:], "unread bytes should stay in the batch")
+}
+
+// TestBatches_ClearedOnCollect verifies that the unread bytes of a batch dropped by the
+// pool are cleared once the batch is garbage collected.
+func TestBatches_ClearedOnCollect(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	b := NewBatches(bytes.NewReader(nil), 16, 4)
+	buf := func() []byte {
+		bt := b.pool.New().(*batch)
+		for i := range bt.buf {
+			bt.buf[i] = 0xa5
+		}
+		bt.pos = 0
+		return bt.buf
+	}()
+
+	is.Eventually(func() bool {
+		runtime.GC()
+		return !slices.ContainsFunc(buf, func(c byte) bool { return c != 0 })
+	}, 5*time.Second, 10*time.Millisecond, "a dropped batch should be cleared")
+}
@@ -0,0 +1,204 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package randutil provides unbiased integer sampling over a buffered random byte
+// stream for the generator subpackages.
+package randutil
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"math/bits"
+	"runtime"
+)
+
+var (
+	ErrBoundInvalid = fmt.Errorf("randutil: bound must be greater than zero")
+)
+
+// bufSize is the number of random bytes fetched from the source at once.
+const bufSize = 256
+
+// Reader buffers an underlying random source so that many small draws cost few Reads.
+// A Reader is not safe for concurrent use; callers create one per operation, or pool
+// them, and call Wipe when done. The zero value has an empty buffer and must be given a
+// source with Reset before use.
+//
+// Consumed bytes are cleared as they are read. Unread bytes stay in the buffer until
+// Wipe, or, for a Reader created by NewReader, until the Reader is garbage collected, so
+// that pooled Readers dropped by a sync.Pool do not leave them behind.
+type Reader struct {
+	src io.Reader
+	buf *[bufSize]byte
+
+	// avail is the number of unread bytes, which occupy the end of buf.
+	avail int
+}
+
+// NewReader returns a Reader drawing from src whose buffer is cleared when the Reader is
+// garbage collected.
+func NewReader(src io.Reader) *Reader {
+	r := &Reader{src: src, buf: new([bufSize]byte)}
+	runtime.AddCleanup(r, clearBuffer, r.buf)
+	return r
+}
+
+// clearBuffer clears the buffer of a collected Reader.
+func clearBuffer(buf *[bufSize]byte) {
+	clear(buf[:])
+}
+
+// Reset discards buffered bytes and switches the Reader to src.
+func (r *Reader) Reset(src io.Reader) {
+	r.Wipe()
+	r.src = src
+}
+
+// Wipe clears any buffered random bytes so they do not linger in memory.
+func (r *Reader) Wipe() {
+	if r.buf != nil {
+		clear(r.buf[:])
+	}
+	r.avail = 0
+}
+
+// Read fills p with random bytes, serving small requests from the buffer and passing
+// requests larger than the buffer straight to the source. Consumed buffer bytes are cleared.
+func (r *Reader) Read(p []byte) (int, error) {
+	if len(p) > bufSize {
+		return io.ReadFull(r.src, p)
+	}
+	n := 0
+	for n < len(p) {
+		if r.avail == 0 {
+			if err := r.fill(); err != nil {
+				return n, err
+			}
+		}
+		pos := bufSize - r.avail
+		c := copy(p[n:], r.buf[pos:])
+		clear(r.buf[pos : pos+c])
+		r.avail -= c
+		n += c
+	}
+	return n, nil
+}
+
+// fill discards any unread bytes and refills the buffer from the source.
+func (r *Reader) fill() error {
+	if r.buf == nil {
+		r.buf = new([bufSize]byte)
+	}
+	if _, err := io.ReadFull(r.src, r.buf[:]); err != nil {
+		r.Wipe()
+		return err
+	}
+	r.avail = bufSize
+	return nil
+}
+
+// Uint64 returns a uniformly distributed random uint64. It decodes directly from the
+// buffer, discarding fewer than 8 leftover bytes when a refill is needed.
+func (r *Reader) Uint64() (uint64, error) {
+	if r.avail < 8 {
+		if err := r.fill(); err != nil {
+			return 0, err
+		}
+	}
+	pos := bufSize - r.avail
+	v := binary.LittleEndian.Uint64(r.buf[pos:])
+	clear(r.buf[pos : pos+8])
+	r.avail -= 8
+	return v, nil
+}
+
+// OpenFloat64 returns a uniformly distributed float64 in the open interval (0, 1), so
+// the result is always safe to pass to math.Log.
+func (r *Reader) OpenFloat64() (float64, error) {
+	u, err := r.Uint64()
+	return (float64(u>>11) + 0.5) * 0x1p-53, err
+}
+
+// Uint64n returns a uniformly distributed random value in [0, n), using Lemire's
+// multiply-and-reject method. It returns ErrBoundInvalid if n is zero.
+func (r *Reader) Uint64n(n uint64) (uint64, error) {
+	if n == 0 {
+		return 0, ErrBoundInvalid
+	}
+	if n&(n-1) == 0 {
+		v, err := r.Uint64()
+		return v & (n - 1), err
+	}
+	// threshold is 2^64 mod n; products whose low half falls below it are rejected.
+	threshold := -n % n
+	for {
+		v, err := r.Uint64()
+		if err != nil {
+			return 0, err
+		}
+		hi, lo := bits.Mul64(v, n)
+		if lo >= threshold {
+			return hi, nil
+		}
+	}
+}
+
+// Intn returns a uniformly distributed random value in [0, n). It returns
+// ErrBoundInvalid if n is not positive.
+func (r *Reader) Intn(n int) (int, error) {
+	if n <= 0 {
+		return 0, ErrBoundInvalid
+	}
+	v, err := r.Uint64n(uint64(n))
+	return int(v), err
+}
+
+// Shuffle pseudo-randomizes the order of n elements with an unbiased Fisher-Yates
+// shuffle, calling swap to exchange elements i and j.
+func (r *Reader) Shuffle(n int, swap func(i, j int)) error {
+	for i := n - 1; i > 0; i-- {
+		j, err := r.Intn(i + 1)
+		if err != nil {
+			return err
+		}
+		swap(i, j)
+	}
+	return nil
+}
+
+// Int returns a uniformly distributed random value in [0, max). It returns
+// ErrBoundInvalid if max is not positive.
+func (r *Reader) Int(max *big.Int) (*big.Int, error) {
+	return Int(r, max)
+}
+
+// Int returns a uniformly distributed random value in [0, max) read from src, drawing
+// whole bytes and rejecting values at or above max. Each attempt costs one read of src.
+// It returns ErrBoundInvalid if max is not positive.
+func Int(src io.Reader, max *big.Int) (*big.Int, error) {
+	if max.Sign() <= 0 {
+		return nil, ErrBoundInvalid
+	}
+	bitLen := max.BitLen()
+	b := make([]byte, (bitLen+7)/8)
+	defer clear(b)
+	// Mask the excess high bits of the first byte so each candidate is below 2^bitLen,
+	// which keeps the expected number of attempts under two.
+	topMask := byte(0xff >> ((8 - uint(bitLen)%8) % 8))
+
+	n := new(big.Int)
+	for {
+		if _, err := io.ReadFull(src, b); err != nil {
+			return nil, err
+		}
+		b[0] &= topMask
+		n.SetBytes(b)
+		if n.Cmp(max) < 0 {
+			return n, nil
+		}
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package randutil
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countingReader wraps a reader and counts Read calls.
+type countingReader struct {
+	r     io.Reader
+	mu    sync.Mutex
+	calls int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	c.mu.Lock()
+	c.calls++
+	c.mu.Unlock()
+	return c.r.Read(p)
+}
+
+// TestReader_Buffering verifies that small reads are served from the buffer and that
+// the stream is consumed in order.
+func TestReader_Buffering(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	src := make([]byte, 4*bufSize)
+	_, err := rand.Read(src)
+	is.NoError(err)
+
+	cr := &countingReader{r: bytes.NewReader(src)}
+	r := NewReader(cr)
+
+	var got []byte
+	for len(got) < 3*bufSize {
+		p := make([]byte, 7)
+		n, err := r.Read(p)
+		is.NoError(err)
+		is.Equal(7, n)
+		got = append(got, p...)
+	}
+	is.Equal(src[:len(got)], got)
+	is.Equal(4, cr.calls)
+
+	// A request larger than the buffer bypasses it.
+	big := make([]byte, bufSize+1)
+	_, err = NewReader(bytes.NewReader(src)).Read(big)
+	is.NoError(err)
+	is.Equal(src[:bufSize+1], big)
+}
+
+// TestReader_Errors verifies error propagation and invalid bounds.
+func TestReader_Errors(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	r := NewReader(bytes.NewReader([]byte{1, 2, 3}))
+	_, err := r.Uint64()
+	is.ErrorIs(err, io.ErrUnexpectedEOF)
+
+	r = NewReader(rand.Reader)
+	_, err = r.Uint64n(0)
+	is.ErrorIs(err, ErrBoundInvalid)
+	_, err = r.Intn(-1)
+	is.ErrorIs(err, ErrBoundInvalid)
+	_, err = r.Int(big.NewInt(0))
+	is.ErrorIs(err, ErrBoundInvalid)
+
+	failing := NewReader(iotestErrReader{})
+	_, err = failing.Int(big.NewInt(1000))
+	is.ErrorIs(err, errRead)
+}
+
+var errRead = errors.New("read failed")
+
+type iotestErrReader struct{}
+
+func (iotestErrReader) Read([]byte) (int, error) { return 0, errRead }
+
+// TestReader_Uint64n checks bounds and uniformity with a chi-squared test.
+func TestReader_Uint64n(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	r := NewReader(rand.Reader)
+	const n, draws = 10, 100000
+	var counts [n]int
+	for i := 0; i < draws; i++ {
+		v, err := r.Uint64n(n)
+		is.NoError(err)
+		is.Less(v, uint64(n))
+		counts[v]++
+	}
+	expected := float64(draws) / n
+	var chi2 float64
+	for _, c := range counts {
+		d := float64(c) - expected
+		chi2 += d * d / expected
+	}
+	// Critical value for 9 degrees of freedom at p = 0.0001 is about 33.7.
+	is.Less(chi2, 33.7)
+
+	v, err := r.Uint64n(1)
+	is.NoError(err)
+	is.Zero(v)
+}
+
+// TestReader_Int verifies bounds and coverage of big integer sampling.
+func TestReader_Int(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	r := NewReader(rand.Reader)
+	max := big.NewInt(300) // 9 bits: exercises the top-byte mask
+	seen := make(map[int64]bool)
+	for i := 0; i < 20000; i++ {
+		v, err := r.Int(max)
+		is.NoError(err)
+		is.Equal(-1, v.Cmp(max))
+		is.GreaterOrEqual(v.Sign(), 0)
+		seen[v.Int64()] = true
+	}
+	is.Len(seen, 300)
+
+	huge := new(big.Int).Lsh(big.NewInt(1), 300)
+	v, err := r.Int(huge)
+	is.NoError(err)
+	is.Equal(-1, v.Cmp(huge))
+}
+
+// TestReader_Shuffle verifies that every permutation of three elements occurs.
+func TestReader_Shuffle(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	r := NewReader(rand.Reader)
+	seen := make(map[[3]int]int)
+	for i := 0; i < 6000; i++ {
+		p := [3]int{0, 1, 2}
+		is.NoError(r.Shuffle(len(p), func(i, j int) { p[i], p[j] = p[j], p[i] }))
+		seen[p]++
+	}
+	is.Len(seen, 6)
+	for p, c := range seen {
+		is.InDelta(1000, c, 200, "permutation %v", p)
+	}
+}
+
+// TestReader_Uint64Refill verifies that Uint64 discards a short remainder and decodes
+// the next value from a fresh buffer.
+func TestReader_Uint64Refill(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	src := make([]byte, 2*bufSize)
+	for i := range src {
+		src[i] = byte(i)
+	}
+	r := NewReader(bytes.NewReader(src))
+	_, err := r.Read(make([]byte, bufSize-3))
+	is.NoError(err)
+
+	v, err := r.Uint64()
+	is.NoError(err)
+	is.Equal(binary.LittleEndian.Uint64(src[bufSize:]), v)
+}
+
+// TestReader_ClearedOnCollect verifies that the unread bytes of a Reader created by
+// NewReader are cleared once the Reader is garbage collected.
+func TestReader_ClearedOnCollect(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	buf := func() *[bufSize]byte {
+		r := NewReader(bytes.NewReader(bytes.Repeat([]byte{0xa5}, bufSize)))
+		_, err := r.Read(make([]byte, 1))
+		is.NoError(err)
+		return r.buf
+	}()
+
+	is.Eventually(func() bool {
+		runtime.GC()
+		return *buf == [bufSize]byte{}
+	}, 5*time.Second, 10*time.Millisecond, "a dropped Reader should be cleared")
+}
@@ -0,0 +1,242 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package keys generates cryptographic key material from the prng package.
+//
+// Passing prng.Reader to the standard library's key generators does not always do what
+// it appears to: since Go 1.26, crypto/ecdh and crypto/ecdsa GenerateKey ignore the
+// reader they are given and use the system generator unless GODEBUG=cryptocustomrand=1
+// is set, while crypto/ed25519.GenerateKey does use a non-nil reader. This package
+// sidesteps the difference by drawing the seed or scalar itself and handing it to the
+// deterministic constructors (ed25519.NewKeyFromSeed, ecdh.Curve.NewPrivateKey and
+// ecdsa.ParseRawPrivateKey), so the key material always comes from the configured reader.
+//
+// Seeds and scalars are wiped after use. Byte-slice keys returned to the caller should
+// be wiped with Zeroize when no longer needed.
+//
+// Example:
+//
+//	key, err := keys.AES(256)
+//	if err != nil {
+//	    // handle error
+//	}
+//	defer keys.Zeroize(key)
+//	block, err := aes.NewCipher(key)
+package keys
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"fmt"
+	"io"
+	"runtime"
+
+	"github.com/sixafter/prng-chacha"
+)
+
+var (
+	ErrKeySizeInvalid    = fmt.Errorf("keys: key size must be between 1 and %d bytes", MaxSymmetricSize)
+	ErrAESKeySizeInvalid = fmt.Errorf("keys: AES key size must be 128, 192 or 256 bits")
+	ErrHashUnavailable   = fmt.Errorf("keys: hash function is not available")
+	ErrCurveUnsupported  = fmt.Errorf("keys: curve is not supported")
+	ErrScalarRejected    = fmt.Errorf("keys: reader repeatedly produced invalid private scalars")
+	ErrNilReader         = fmt.Errorf("keys: reader must not be nil")
+)
+
+const (
+	// MaxSymmetricSize is the largest symmetric key, in bytes, that Symmetric generates.
+	MaxSymmetricSize = 1024
+
+	// maxScalarAttempts bounds rejection sampling of elliptic curve scalars. For the
+	// supported curves a random candidate is rejected with probability below 2^-32, so
+	// reaching the bound means the reader is broken.
+	maxScalarAttempts = 16
+)
+
+// Config defines the randomness source used to generate keys.
+type Config struct {
+	// Reader is the source of randomness. Defaults to prng.Reader.
+	Reader io.Reader
+}
+
+// Option defines a functional option for customizing a Config.
+type Option func(*Config)
+
+// WithReader returns an Option that sets the source of randomness, typically a
+// prng.Interface returned by prng.NewReader.
+func WithReader(r io.Reader) Option {
+	return func(cfg *Config) {
+		cfg.Reader = r
+	}
+}
+
+// newConfig applies opts to the default Config and validates the result.
+func newConfig(opts []Option) (Config, error) {
+	cfg := Config{
+		Reader: prng.Reader,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.Reader == nil {
+		return cfg, ErrNilReader
+	}
+	return cfg, nil
+}
+
+// Zeroize overwrites b with zeros. Use it on keys returned by this package, and on any
+// copies, once they are no longer needed.
+//
+// Zeroize cannot reach key material held inside opaque types such as *ecdh.PrivateKey
+// or *ecdsa.PrivateKey, or copies made by the runtime or other libraries; it reduces,
+// but does not eliminate, the time secrets remain in memory.
+func Zeroize(b []byte) {
+	clear(b)
+	// Keep b reachable until the clear is complete so it cannot be optimized away.
+	runtime.KeepAlive(b)
+}
+
+// Symmetric returns size random bytes for use as a symmetric key.
+//
+// It returns ErrKeySizeInvalid unless 1 <= size <= MaxSymmetricSize, ErrNilReader if the
+// configured reader is nil, and the reader's error if it fails.
+func Symmetric(size int, opts ...Option) ([]byte, error) {
+	cfg, err := newConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	if size <= 0 || size > MaxSymmetricSize {
+		return nil, ErrKeySizeInvalid
+	}
+
+	key := make([]byte, size)
+	if _, err := io.ReadFull(cfg.Reader, key); err != nil {
+		Zeroize(key)
+		return nil, err
+	}
+	return key, nil
+}
+
+// AES returns a key for crypto/aes.NewCipher of the given size in bits: 128, 192 or 256.
+//
+// It returns ErrAESKeySizeInvalid for any other size and otherwise fails like Symmetric.
+func AES(bits int, opts ...Option) ([]byte, error) {
+	switch bits {
+	case 128, 192, 256:
+		return Symmetric(bits/8, opts...)
+	default:
+		return nil, ErrAESKeySizeInvalid
+	}
+}
+
+// HMAC returns a key for crypto/hmac.New with hash h, as long as the hash's output, the
+// minimum recommended by RFC 2104. Longer keys add no strength.
+//
+// It returns ErrHashUnavailable if h is not linked into the binary and otherwise fails
+// like Symmetric.
+func HMAC(h crypto.Hash, opts ...Option) ([]byte, error) {
+	if !h.Available() {
+		return nil, ErrHashUnavailable
+	}
+	return Symmetric(h.Size(), opts...)
+}
+
+// Ed25519 returns an Ed25519 private key derived with ed25519.NewKeyFromSeed from a
+// seed read from the configured reader. The seed is wiped; the returned key, which
+// embeds it, can be wiped with Zeroize.
+func Ed25519(opts ...Option) (ed25519.PrivateKey, error) {
+	cfg, err := newConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var seed [ed25519.SeedSize]byte
+	defer Zeroize(seed[:])
+	if _, err := io.ReadFull(cfg.Reader, seed[:]); err != nil {
+		return nil, err
+	}
+	return ed25519.NewKeyFromSeed(seed[:]), nil
+}
+
+// X25519 returns an X25519 private key made from 32 bytes read from the configured
+// reader. Every 32-byte string is a valid X25519 private key.
+func X25519(opts ...Option) (*ecdh.PrivateKey, error) {
+	return ECDH(ecdh.X25519(), opts...)
+}
+
+// ECDH returns a private key for curve, which must be ecdh.X25519, ecdh.P256, ecdh.P384
+// or ecdh.P521, made with curve.NewPrivateKey from bytes read from the configured reader.
+// For the NIST curves, candidates that are zero or not below the group order are
+// rejected and redrawn, so the scalar is uniform.
+//
+// It returns ErrCurveUnsupported for other curves, ErrScalarRejected if the reader keeps
+// producing invalid scalars, and the reader's error if it fails.
+func ECDH(curve ecdh.Curve, opts ...Option) (*ecdh.PrivateKey, error) {
+	cfg, err := newConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var bits int
+	switch curve {
+	case ecdh.X25519():
+		bits = 256
+	case ecdh.P256():
+		bits = 256
+	case ecdh.P384():
+		bits = 384
+	case ecdh.P521():
+		bits = 521
+	default:
+		return nil, ErrCurveUnsupported
+	}
+	return scalar(cfg.Reader, bits, curve.NewPrivateKey)
+}
+
+// ECDSA returns an ECDSA private key for curve, which must be elliptic.P224,
+// elliptic.P256, elliptic.P384 or elliptic.P521, made with ecdsa.ParseRawPrivateKey from
+// a scalar read from the configured reader and redrawn until valid, as in ECDH.
+//
+// It returns ErrCurveUnsupported for other curves, ErrScalarRejected if the reader keeps
+// producing invalid scalars, and the reader's error if it fails.
+func ECDSA(curve elliptic.Curve, opts ...Option) (*ecdsa.PrivateKey, error) {
+	cfg, err := newConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	switch curve {
+	case elliptic.P224(), elliptic.P256(), elliptic.P384(), elliptic.P521():
+	default:
+		return nil, ErrCurveUnsupported
+	}
+	return scalar(cfg.Reader, curve.Params().BitSize, func(b []byte) (*ecdsa.PrivateKey, error) {
+		return ecdsa.ParseRawPrivateKey(curve, b)
+	})
+}
+
+// scalar reads big-endian candidates of the given bit length from src, clearing the
+// excess high bits, until parse accepts one. The candidate buffer is wiped.
+func scalar[K any](src io.Reader, bits int, parse func([]byte) (K, error)) (K, error) {
+	var zero K
+	buf := make([]byte, (bits+7)/8)
+	defer Zeroize(buf)
+	topMask := byte(0xff >> ((8 - uint(bits)%8) % 8))
+
+	for attempt := 0; attempt < maxScalarAttempts; attempt++ {
+		if _, err := io.ReadFull(src, buf); err != nil {
+			return zero, err
+		}
+		buf[0] &= topMask
+		if k, err := parse(buf); err == nil {
+			return k, nil
+		}
+	}
+	return zero, ErrScalarRejected
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package keys
+
+import (
+	"crypto/ecdh"
+	"crypto/elliptic"
+	"fmt"
+	"testing"
+)
+
+// BenchmarkKeys_AES256 measures generating a 256-bit symmetric key.
+func BenchmarkKeys_AES256(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		_, _ = AES(256)
+	}
+}
+
+// BenchmarkKeys_Ed25519 measures seed generation plus public key derivation.
+func BenchmarkKeys_Ed25519(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		_, _ = Ed25519()
+	}
+}
+
+// BenchmarkKeys_ECDH measures private key generation on each supported curve.
+func BenchmarkKeys_ECDH(b *testing.B) {
+	for _, curve := range []ecdh.Curve{ecdh.X25519(), ecdh.P256(), ecdh.P384(), ecdh.P521()} {
+		b.Run(fmt.Sprint(curve), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				_, _ = ECDH(curve)
+			}
+		})
+	}
+}
+
+// BenchmarkKeys_ECDSA_P256 measures ECDSA P-256 private key generation.
+func BenchmarkKeys_ECDSA_P256(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		_, _ = ECDSA(elliptic.P256())
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package keys
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/sixafter/prng-chacha"
+	"github.com/stretchr/testify/assert"
+)
+
+var errRead = errors.New("read failed")
+
+// errReader fails every Read.
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) { return 0, errRead }
+
+// seeded returns a deterministic reader over a fixed seed.
+func seeded(t *testing.T) io.Reader {
+	t.Helper()
+	r, err := prng.NewSeededReader(bytes.Repeat([]byte{0x42}, prng.SeedSize))
+	if err != nil {
+		t.Fatalf("NewSeededReader failed: %v", err)
+	}
+	return r
+}
+
+// TestSymmetric verifies key sizes, validity with the std constructors and argument
+// validation.
+func TestSymmetric(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	for _, bits := range []int{128, 192, 256} {
+		key, err := AES(bits)
+		is.NoError(err)
+		is.Len(key, bits/8)
+		_, err = aes.NewCipher(key)
+		is.NoError(err)
+	}
+	_, err := AES(512)
+	is.ErrorIs(err, ErrAESKeySizeInvalid)
+
+	key, err := HMAC(crypto.SHA256)
+	is.NoError(err)
+	is.Len(key, sha256.Size)
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("message"))
+	is.Len(mac.Sum(nil), sha256.Size)
+	_, err = HMAC(crypto.Hash(0))
+	is.ErrorIs(err, ErrHashUnavailable)
+
+	a, err := Symmetric(64)
+	is.NoError(err)
+	b, err := Symmetric(64)
+	is.NoError(err)
+	is.NotEqual(a, b)
+
+	_, err = Symmetric(0)
+	is.ErrorIs(err, ErrKeySizeInvalid)
+	_, err = Symmetric(MaxSymmetricSize + 1)
+	is.ErrorIs(err, ErrKeySizeInvalid)
+	_, err = Symmetric(16, WithReader(nil))
+	is.ErrorIs(err, ErrNilReader)
+	_, err = Symmetric(16, WithReader(errReader{}))
+	is.ErrorIs(err, errRead)
+}
+
+// TestEd25519 verifies that keys sign and verify, and that the key is derived from the
+// first 32 bytes of the configured reader.
+func TestEd25519(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	priv, err := Ed25519()
+	is.NoError(err)
+	is.Len(priv, ed25519.PrivateKeySize)
+	msg := []byte("message")
+	pub := priv.Public().(ed25519.PublicKey)
+	is.True(ed25519.Verify(pub, msg, ed25519.Sign(priv, msg)))
+
+	priv, err = Ed25519(WithReader(seeded(t)))
+	is.NoError(err)
+	seed := make([]byte, ed25519.SeedSize)
+	_, err = io.ReadFull(seeded(t), seed)
+	is.NoError(err)
+	is.Equal(ed25519.NewKeyFromSeed(seed), priv)
+
+	_, err = Ed25519(WithReader(errReader{}))
+	is.ErrorIs(err, errRead)
+}
+
+// TestECDH verifies that two keys on each curve agree on a shared secret and that
+// X25519 keys are built from the reader's bytes.
+func TestECDH(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	for _, curve := range []ecdh.Curve{ecdh.X25519(), ecdh.P256(), ecdh.P384(), ecdh.P521()} {
+		a, err := ECDH(curve)
+		is.NoError(err)
+		b, err := ECDH(curve)
+		is.NoError(err)
+		is.Equal(curve, a.Curve())
+
+		s1, err := a.ECDH(b.PublicKey())
+		is.NoError(err)
+		s2, err := b.ECDH(a.PublicKey())
+		is.NoError(err)
+		is.Equal(s1, s2, "%v", curve)
+
+		// The private key round-trips through the std constructor.
+		again, err := curve.NewPrivateKey(a.Bytes())
+		is.NoError(err)
+		is.True(a.Equal(again))
+	}
+
+	priv, err := X25519(WithReader(seeded(t)))
+	is.NoError(err)
+	raw := make([]byte, 32)
+	_, err = io.ReadFull(seeded(t), raw)
+	is.NoError(err)
+	is.Equal(raw, priv.Bytes())
+
+	_, err = ECDH(nil)
+	is.ErrorIs(err, ErrCurveUnsupported)
+	_, err = X25519(WithReader(errReader{}))
+	is.ErrorIs(err, errRead)
+}
+
+// TestECDSA verifies that keys on each curve sign and verify.
+func TestECDSA(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	digest := sha256.Sum256([]byte("message"))
+	for _, curve := range []elliptic.Curve{elliptic.P224(), elliptic.P256(), elliptic.P384(), elliptic.P521()} {
+		priv, err := ECDSA(curve)
+		is.NoError(err)
+		sig, err := ecdsa.SignASN1(prng.Reader, priv, digest[:])
+		is.NoError(err)
+		is.True(ecdsa.VerifyASN1(&priv.PublicKey, digest[:], sig), curve.Params().Name)
+	}
+
+	_, err := ECDSA(nil)
+	is.ErrorIs(err, ErrCurveUnsupported)
+	_, err = ECDSA(elliptic.P256(), WithReader(errReader{}))
+	is.ErrorIs(err, errRead)
+}
+
+// TestScalar_Rejection verifies that out-of-range scalars are redrawn and that a reader
+// producing only invalid scalars is reported.
+func TestScalar_Rejection(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	// 32 bytes of 0xff exceed the P-256 order; the next candidate is accepted.
+	valid := bytes.Repeat([]byte{0x01}, 32)
+	src := io.MultiReader(bytes.NewReader(bytes.Repeat([]byte{0xff}, 32)), bytes.NewReader(valid))
+	priv, err := ECDH(ecdh.P256(), WithReader(src))
+	is.NoError(err)
+	is.Equal(valid, priv.Bytes())
+
+	// An all-zero scalar is never valid.
+	_, err = ECDSA(elliptic.P256(), WithReader(bytes.NewReader(make([]byte, 32*maxScalarAttempts))))
+	is.ErrorIs(err, ErrScalarRejected)
+
+	// P-521 scalars keep only the low bit of the first byte.
+	src = bytes.NewReader(bytes.Repeat([]byte{0xff}, 66*maxScalarAttempts))
+	_, err = ECDH(ecdh.P521(), WithReader(src))
+	is.ErrorIs(err, ErrScalarRejected)
+}
+
+// TestZeroize verifies that Zeroize clears the slice in place.
+func TestZeroize(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	key, err := AES(256)
+	is.NoError(err)
+	alias := key[:]
+	Zeroize(key)
+	is.Equal(make([]byte, 32), alias)
+	Zeroize(nil)
+}
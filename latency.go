@@ -0,0 +1,197 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package prng
+
+import (
+	"math"
+	"math/bits"
+	mrand "math/rand/v2"
+	"sync/atomic"
+	"time"
+)
+
+// Latency histogram layout.
+const (
+	// histogramMinShift is log2 of the upper bound of the first histogram bucket (64ns).
+	histogramMinShift = 6
+
+	// histogramBuckets is the number of histogram buckets. Bucket i (for i < histogramBuckets-1)
+	// counts durations below 2^(i+histogramMinShift) nanoseconds; the last bucket is unbounded.
+	// The largest bounded bucket ends at 2^35ns (about 34 seconds).
+	histogramBuckets = 31
+
+	// readSizeClasses is the number of request size classes tracked for Read latency.
+	readSizeClasses = 8
+)
+
+// readSizeClassMax lists the inclusive upper bound, in bytes, of each Read size class.
+// The last class is unbounded and reported with MaxBytes == 0.
+var readSizeClassMax = [readSizeClasses]int{16, 64, 256, 1 << 10, 4 << 10, 16 << 10, 64 << 10, 0}
+
+// Bucket is a single bucket of a latency Histogram.
+type Bucket struct {
+	// UpperBound is the exclusive upper bound of the bucket. The last bucket of a
+	// Histogram has UpperBound math.MaxInt64, meaning it is unbounded.
+	UpperBound time.Duration
+
+	// Count is the number of observations that fell into this bucket (not cumulative).
+	Count uint64
+}
+
+// Histogram is a snapshot of a log2-bucketed latency distribution.
+type Histogram struct {
+	// Count is the total number of observations.
+	Count uint64
+
+	// Sum is the total of all observed durations.
+	Sum time.Duration
+
+	// Buckets holds the per-bucket counts in ascending UpperBound order. Bucket bounds
+	// are powers of two nanoseconds starting at 64ns.
+	Buckets []Bucket
+}
+
+// Quantile returns an upper estimate of the q-quantile (0 <= q <= 1) of the observed
+// durations: the upper bound of the bucket containing it. It returns 0 if there are no
+// observations and math.MaxInt64 if the quantile falls into the unbounded bucket.
+func (h Histogram) Quantile(q float64) time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+	q = math.Max(0, math.Min(1, q))
+	rank := uint64(math.Ceil(q * float64(h.Count)))
+	if rank == 0 {
+		rank = 1
+	}
+
+	var seen uint64
+	for _, b := range h.Buckets {
+		seen += b.Count
+		if seen >= rank {
+			return b.UpperBound
+		}
+	}
+	return h.Buckets[len(h.Buckets)-1].UpperBound
+}
+
+// ReadLatency is the latency Histogram of Read calls in one request size class.
+type ReadLatency struct {
+	// MaxBytes is the inclusive upper bound of the size class in bytes. Zero means
+	// the class is unbounded (larger than every other class).
+	MaxBytes int
+
+	Histogram
+}
+
+// LatencyStats is a snapshot of the latency metrics collected when latency tracking is
+// enabled (see WithLatencyTracking). All values are cumulative since reader creation.
+type LatencyStats struct {
+	// Read holds one histogram per request size class, ordered by MaxBytes (the
+	// unbounded class last). Only sampled reads are recorded.
+	Read []ReadLatency
+
+	// Rekey is the distribution of successful rekey durations, measured from the start
+	// of the rekey until a new cipher is ready, including any backoff.
+	Rekey Histogram
+
+	// RekeyBackoff is the total time spent sleeping between failed rekey attempts.
+	RekeyBackoff time.Duration
+
+	// PoolGets is the number of PRNG instances taken from the pools by Read, Int and Prime.
+	PoolGets uint64
+
+	// PoolMisses is the number of PRNG instances the pools had to create because none
+	// was available.
+	PoolMisses uint64
+}
+
+// PoolMissRate returns PoolMisses / PoolGets, or 0 if no gets were recorded.
+func (l LatencyStats) PoolMissRate() float64 {
+	if l.PoolGets == 0 {
+		return 0
+	}
+	return float64(l.PoolMisses) / float64(l.PoolGets)
+}
+
+// histogram is a lock-free log2-bucketed latency histogram.
+type histogram struct {
+	buckets [histogramBuckets]atomic.Uint64
+	count   atomic.Uint64
+	sum     atomic.Uint64
+}
+
+// observe records a single duration.
+func (h *histogram) observe(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	ns := uint64(d)
+	i := bits.Len64(ns) - histogramMinShift
+	if i < 0 {
+		i = 0
+	} else if i >= histogramBuckets {
+		i = histogramBuckets - 1
+	}
+	h.buckets[i].Add(1)
+	h.count.Add(1)
+	h.sum.Add(ns)
+}
+
+// snapshot returns a copy of the histogram's current state.
+func (h *histogram) snapshot() Histogram {
+	s := Histogram{
+		Count:   h.count.Load(),
+		Sum:     time.Duration(h.sum.Load()),
+		Buckets: make([]Bucket, histogramBuckets),
+	}
+	for i := range h.buckets {
+		upper := time.Duration(math.MaxInt64)
+		if i < histogramBuckets-1 {
+			upper = time.Duration(1) << (i + histogramMinShift)
+		}
+		s.Buckets[i] = Bucket{UpperBound: upper, Count: h.buckets[i].Load()}
+	}
+	return s
+}
+
+// latencyTracker holds the latency metrics of a reader.
+type latencyTracker struct {
+	read         [readSizeClasses]histogram
+	rekey        histogram
+	rekeyBackoff atomic.Uint64
+}
+
+// readSizeClass returns the index of the size class containing a request of n bytes.
+func readSizeClass(n int) int {
+	if n <= readSizeClassMax[0] {
+		return 0
+	}
+	// Classes grow by a factor of four: (16, 64] is 1, (64, 256] is 2, and so on.
+	c := (bits.Len(uint(n-1)) - 3) / 2
+	if c >= readSizeClasses {
+		c = readSizeClasses - 1
+	}
+	return c
+}
+
+// sampled reports whether the current Read should be timed, given the configured
+// sampling interval: every read for 1, roughly one in interval reads otherwise.
+func sampled(interval int) bool {
+	return interval == 1 || (interval > 1 && mrand.IntN(interval) == 0)
+}
+
+// snapshot returns the current latency metrics; pool counters are filled in by the caller.
+func (t *latencyTracker) snapshot() LatencyStats {
+	s := LatencyStats{
+		Read:         make([]ReadLatency, readSizeClasses),
+		Rekey:        t.rekey.snapshot(),
+		RekeyBackoff: time.Duration(t.rekeyBackoff.Load()),
+	}
+	for i := range t.read {
+		s.Read[i] = ReadLatency{MaxBytes: readSizeClassMax[i], Histogram: t.read[i].snapshot()}
+	}
+	return s
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package prng
+
+import (
+	"math"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_Latency_ReadSizeClass verifies the boundaries of the Read size classes.
+func Test_Latency_ReadSizeClass(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	testCases := []struct {
+		size int
+		want int
+	}{
+		{1, 0}, {16, 0}, {17, 1}, {64, 1}, {65, 2}, {256, 2}, {257, 3}, {1024, 3},
+		{1025, 4}, {4096, 4}, {4097, 5}, {16384, 5}, {16385, 6}, {65536, 6},
+		{65537, 7}, {1 << 30, 7},
+	}
+	for _, tc := range testCases {
+		is.Equal(tc.want, readSizeClass(tc.size), "size %d", tc.size)
+		if max := readSizeClassMax[tc.want]; max != 0 {
+			is.LessOrEqual(tc.size, max, "size %d exceeds its class bound", tc.size)
+		}
+	}
+}
+
+// Test_Latency_Histogram verifies bucket placement, totals and quantile estimates.
+func Test_Latency_Histogram(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	var h histogram
+	is.Equal(time.Duration(0), h.snapshot().Quantile(0.5), "empty histogram quantile should be 0")
+
+	h.observe(10 * time.Nanosecond)  // bucket 0 (< 64ns)
+	h.observe(100 * time.Nanosecond) // bucket 1 (< 128ns)
+	h.observe(100 * time.Nanosecond) // bucket 1
+	h.observe(time.Hour)             // unbounded bucket
+	h.observe(-time.Second)          // clamped to 0
+
+	s := h.snapshot()
+	is.Equal(uint64(5), s.Count)
+	is.Equal(210*time.Nanosecond+time.Hour, s.Sum)
+	is.Len(s.Buckets, histogramBuckets)
+	is.Equal(uint64(2), s.Buckets[0].Count)
+	is.Equal(64*time.Nanosecond, s.Buckets[0].UpperBound)
+	is.Equal(uint64(2), s.Buckets[1].Count)
+	is.Equal(128*time.Nanosecond, s.Buckets[1].UpperBound)
+	is.Equal(uint64(1), s.Buckets[histogramBuckets-1].Count)
+	is.Equal(time.Duration(math.MaxInt64), s.Buckets[histogramBuckets-1].UpperBound)
+
+	is.Equal(64*time.Nanosecond, s.Quantile(0))
+	is.Equal(128*time.Nanosecond, s.Quantile(0.8))
+	is.Equal(time.Duration(math.MaxInt64), s.Quantile(1))
+}
+
+// Test_Latency_ReaderTracking verifies that a reader with tracking enabled records every
+// read in the right size class along with pool gets.
+func Test_Latency_ReaderTracking(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	r, err := NewReader(WithShards(2), WithLatencyTracking(1))
+	is.NoError(err)
+
+	small := make([]byte, 16)
+	large := make([]byte, 5000)
+	for i := 0; i < 10; i++ {
+		_, err = r.Read(small)
+		is.NoError(err)
+	}
+	for i := 0; i < 3; i++ {
+		_, err = r.Read(large)
+		is.NoError(err)
+	}
+
+	l := r.(StatsReporter).Stats().Latency
+	is.Len(l.Read, readSizeClasses)
+	is.Equal(16, l.Read[0].MaxBytes)
+	is.Equal(uint64(10), l.Read[0].Count)
+	is.Equal(uint64(3), l.Read[readSizeClass(5000)].Count)
+	is.Equal(0, l.Read[readSizeClasses-1].MaxBytes, "last class should be unbounded")
+	is.Greater(l.Read[0].Sum, time.Duration(0))
+	is.Equal(uint64(13), l.PoolGets)
+	is.LessOrEqual(l.PoolMisses, l.PoolGets)
+	is.GreaterOrEqual(l.PoolMissRate(), 0.0)
+	is.LessOrEqual(l.PoolMissRate(), 1.0)
+}
+
+// Test_Latency_Sampling verifies that a sampling interval records only a subset of reads
+// while still counting every pool access.
+func Test_Latency_Sampling(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	r, err := NewReader(WithShards(1), WithLatencyTracking(8))
+	is.NoError(err)
+
+	buf := make([]byte, 32)
+	const reads = 4000
+	for i := 0; i < reads; i++ {
+		_, err = r.Read(buf)
+		is.NoError(err)
+	}
+
+	l := r.(StatsReporter).Stats().Latency
+	is.Equal(uint64(reads), l.PoolGets)
+	// Expected 500 samples; the bounds are many standard deviations wide.
+	is.Greater(l.Read[1].Count, uint64(300))
+	is.Less(l.Read[1].Count, uint64(700))
+}
+
+// Test_Latency_Disabled verifies that nothing is recorded when tracking is disabled.
+func Test_Latency_Disabled(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	r, err := NewReader(WithShards(1))
+	is.NoError(err)
+
+	buf := make([]byte, 32)
+	_, err = r.Read(buf)
+	is.NoError(err)
+
+	l := r.(StatsReporter).Stats().Latency
+	is.Equal(uint64(0), l.PoolGets)
+	for _, c := range l.Read {
+		is.Equal(uint64(0), c.Count)
+	}
+	is.Equal(0.0, l.PoolMissRate())
+}
+
+// Test_Latency_Rekey verifies that rekey durations and backoff time are recorded.
+func Test_Latency_Rekey(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	cfg := DefaultConfig()
+	cfg.Shards = 1
+	cfg.EnableKeyRotation = true
+	cfg.LatencySampleInterval = 1
+	cfg.MaxRekeyAttempts = 2
+	cfg.RekeyBackoff = time.Millisecond
+	cfg.MaxRekeyBackoff = time.Millisecond
+	r := newReader(&cfg)
+
+	p, err := newPRNG(r, 0)
+	is.NoError(err)
+
+	// Successful rekey: one duration, no backoff.
+	atomic.StoreUint32(&p.rekeying, 1)
+	p.asyncRekey()
+	l := r.Stats().Latency
+	is.Equal(uint64(1), l.Rekey.Count)
+	is.Equal(time.Duration(0), l.RekeyBackoff)
+
+	// Failed rekey: the backoff between the two attempts is recorded, no additional duration.
+	r.entropy = failingSource{}
+	atomic.StoreUint32(&p.rekeying, 1)
+	p.asyncRekey()
+	l = r.Stats().Latency
+	is.Equal(uint64(1), l.Rekey.Count)
+	is.GreaterOrEqual(l.RekeyBackoff, time.Millisecond)
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package prng
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// capturedRecord is a flattened copy of a slog.Record for assertions.
+type capturedRecord struct {
+	level   slog.Level
+	message string
+	attrs   map[string]slog.Value
+}
+
+// captureHandler is a slog.Handler that stores every record it handles.
+type captureHandler struct {
+	mu      sync.Mutex
+	records []capturedRecord
+}
+
+func (h *captureHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *captureHandler) Handle(_ context.Context, r slog.Record) error {
+	rec := capturedRecord{level: r.Level, message: r.Message, attrs: map[string]slog.Value{}}
+	r.Attrs(func(a slog.Attr) bool {
+		rec.attrs[a.Key] = a.Value
+		return true
+	})
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, rec)
+	return nil
+}
+
+func (h *captureHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *captureHandler) WithGroup(string) slog.Handler { return h }
+
+// byMessage returns the captured records with the given message.
+func (h *captureHandler) byMessage(msg string) []capturedRecord {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var out []capturedRecord
+	for _, r := range h.records {
+		if r.message == msg {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// Test_Logger_NewReader verifies that NewReader logs instance creation for every shard
+// and a final initialization record at debug level.
+func Test_Logger_NewReader(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	h := &captureHandler{}
+	_, err := NewReader(WithShards(2), WithLogger(slog.New(h)))
+	is.NoError(err)
+
+	created := h.byMessage("prng: instance created")
+	is.Len(created, 2)
+	for _, r := range created {
+		is.Equal(slog.LevelDebug, r.level)
+		is.Contains(r.attrs, "shard")
+	}
+
+	initialized := h.byMessage("prng: reader initialized")
+	is.Len(initialized, 1)
+	is.Equal(int64(2), initialized[0].attrs["shards"].Int64())
+}
+
+// Test_Logger_InitFailure verifies that exhausting MaxInitRetries is logged at error level
+// from both the pool and NewReader.
+func Test_Logger_InitFailure(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	h := &captureHandler{}
+	_, err := NewReader(WithShards(1), WithMaxInitRetries(0), WithLogger(slog.New(h)))
+	is.Error(err)
+
+	failed := h.byMessage("prng: instance initialization failed")
+	is.Len(failed, 1)
+	is.Equal(slog.LevelError, failed[0].level)
+	is.Equal(int64(0), failed[0].attrs["shard"].Int64())
+
+	readerFailed := h.byMessage("prng: reader initialization failed")
+	is.Len(readerFailed, 1)
+	is.Equal(slog.LevelError, readerFailed[0].level)
+	is.NotNil(readerFailed[0].attrs["error"].Any())
+}
+
+// Test_Logger_RekeyFailure verifies that each failed rekey attempt is logged at warn level
+// with shard, attempt, backoff, usage and error, followed by a final error record.
+func Test_Logger_RekeyFailure(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	h := &captureHandler{}
+	cfg := DefaultConfig()
+	cfg.Shards = 8
+	cfg.EnableKeyRotation = true
+	cfg.MaxRekeyAttempts = 2
+	cfg.RekeyBackoff = time.Millisecond
+	cfg.MaxRekeyBackoff = 2 * time.Millisecond
+	cfg.Logger = slog.New(h)
+	r := newReader(&cfg)
+
+	p, err := newPRNG(r, 3)
+	is.NoError(err)
+	r.entropy = failingSource{}
+
+	atomic.StoreUint64(&p.usage, 4096)
+	atomic.StoreUint32(&p.rekeying, 1)
+	p.asyncRekey()
+
+	started := h.byMessage("prng: rekey started")
+	is.Len(started, 1)
+	is.Equal(uint64(4096), started[0].attrs["bytes_since_last_key"].Uint64())
+
+	attempts := h.byMessage("prng: rekey attempt failed")
+	is.Len(attempts, 2)
+	for i, rec := range attempts {
+		is.Equal(slog.LevelWarn, rec.level)
+		is.Equal(int64(3), rec.attrs["shard"].Int64())
+		is.Equal(int64(i+1), rec.attrs["attempt"].Int64())
+		if i < len(attempts)-1 {
+			is.GreaterOrEqual(rec.attrs["backoff"].Duration(), time.Millisecond)
+		} else {
+			is.Zero(rec.attrs["backoff"].Duration(), "no backoff follows the last attempt")
+		}
+		is.Equal(uint64(4096), rec.attrs["bytes_since_last_key"].Uint64())
+		is.ErrorIs(rec.attrs["error"].Any().(error), errEntropy)
+	}
+
+	final := h.byMessage("prng: rekey failed; keeping current key")
+	is.Len(final, 1)
+	is.Equal(slog.LevelError, final[0].level)
+	is.Equal(int64(2), final[0].attrs["attempts"].Int64())
+}
+
+// Test_Logger_KeyRotated verifies that a prepared key and its installation are logged at
+// debug level.
+func Test_Logger_KeyRotated(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	h := &captureHandler{}
+	cfg := DefaultConfig()
+	cfg.Shards = 8
+	cfg.EnableKeyRotation = true
+	cfg.Logger = slog.New(h)
+	r := newReader(&cfg)
+
+	p, err := newPRNG(r, 0)
+	is.NoError(err)
+
+	atomic.StoreUint32(&p.rekeying, 1)
+	atomic.StoreUint64(&p.usage, 100)
+	p.asyncRekey()
+
+	prepared := h.byMessage("prng: rekey prepared")
+	is.Len(prepared, 1)
+	is.Equal(int64(1), prepared[0].attrs["attempt"].Int64())
+	is.Empty(h.byMessage("prng: key rotated"), "the rotation is logged when installed")
+
+	_, err = p.Read(make([]byte, 16))
+	is.NoError(err)
+	rotated := h.byMessage("prng: key rotated")
+	is.Len(rotated, 1)
+	is.Equal(slog.LevelDebug, rotated[0].level)
+	is.Equal(uint64(100), rotated[0].attrs["bytes_since_last_key"].Uint64())
+	is.Empty(h.byMessage("prng: rekey attempt failed"))
+}
@@ -0,0 +1,414 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package metrics exports the runtime statistics of a prng reader through
+// expvar and the Prometheus text exposition format.
+//
+// A Collector reads prng.Stats on demand; it holds no state of its own and adds
+// no overhead to Read. It implements both expvar.Var (JSON) and http.Handler
+// (Prometheus text format 0.0.4), without depending on any Prometheus client
+// library.
+//
+// Example:
+//
+//	r, _ := prng.NewReader()
+//	c, err := metrics.New(r.(metrics.Source), metrics.WithLabels(map[string]string{"reader": "tokens"}))
+//	if err != nil {
+//	    // handle error
+//	}
+//	expvar.Publish("prng_tokens", c)
+//	http.Handle("/metrics", c)
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sixafter/prng-chacha"
+)
+
+var (
+	ErrNilSource        = fmt.Errorf("metrics: source must not be nil")
+	ErrInvalidNamespace = fmt.Errorf("metrics: namespace must match [a-zA-Z_:][a-zA-Z0-9_:]*")
+	ErrInvalidLabelName = fmt.Errorf("metrics: label names must match [a-zA-Z_][a-zA-Z0-9_]* and must not be reserved")
+)
+
+// ContentType is the HTTP Content-Type of the Prometheus text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// defaultNamespace prefixes every exported Prometheus metric name.
+const defaultNamespace = "prng"
+
+var (
+	namespacePattern = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	labelPattern     = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// reservedLabels are label names set by the Collector itself.
+var reservedLabels = map[string]bool{"shard": true, "max_bytes": true, "le": true}
+
+// Source is implemented by any value that reports cumulative prng statistics.
+//
+// It has the method set of prng.StatsReporter: every reader returned by prng.NewReader,
+// and the package-level prng.Reader, satisfy it through a type assertion such as
+// r.(metrics.Source).
+type Source interface {
+	Stats() prng.Stats
+}
+
+// Config defines the naming of exported metrics.
+type Config struct {
+	// Namespace prefixes every Prometheus metric name. Defaults to "prng".
+	Namespace string
+
+	// Labels are constant labels attached to every Prometheus sample, for example
+	// to distinguish several readers exported by the same process.
+	Labels map[string]string
+}
+
+// Option defines a functional option for customizing a Collector's Config.
+type Option func(*Config)
+
+// WithNamespace returns an Option that sets the Prometheus metric name prefix.
+func WithNamespace(ns string) Option {
+	return func(cfg *Config) {
+		cfg.Namespace = ns
+	}
+}
+
+// WithLabels returns an Option that attaches constant labels to every Prometheus sample.
+//
+// The label names "shard", "max_bytes" and "le" are reserved for per-shard and
+// histogram metrics.
+func WithLabels(labels map[string]string) Option {
+	return func(cfg *Config) {
+		cfg.Labels = labels
+	}
+}
+
+// label is a single constant label, pre-rendered at construction.
+type label struct {
+	name  string
+	value string
+}
+
+// Collector exports the statistics of a Source.
+//
+// It implements expvar.Var, http.Handler and io.WriterTo, and is safe for concurrent use.
+type Collector struct {
+	src       Source
+	namespace string
+	labels    []label
+}
+
+// New returns a Collector for src configured by opts.
+//
+// It returns ErrNilSource if src is nil, and ErrInvalidNamespace or ErrInvalidLabelName
+// if the resulting metric or label names would not be valid in the Prometheus
+// exposition format.
+func New(src Source, opts ...Option) (*Collector, error) {
+	if src == nil {
+		return nil, ErrNilSource
+	}
+
+	cfg := Config{Namespace: defaultNamespace}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if !namespacePattern.MatchString(cfg.Namespace) {
+		return nil, ErrInvalidNamespace
+	}
+
+	c := &Collector{
+		src:       src,
+		namespace: cfg.Namespace,
+		labels:    make([]label, 0, len(cfg.Labels)),
+	}
+	for name, value := range cfg.Labels {
+		if !labelPattern.MatchString(name) || reservedLabels[name] || strings.HasPrefix(name, "__") {
+			return nil, ErrInvalidLabelName
+		}
+		c.labels = append(c.labels, label{name: name, value: value})
+	}
+	// Sort for deterministic output.
+	sort.Slice(c.labels, func(i, j int) bool { return c.labels[i].name < c.labels[j].name })
+
+	return c, nil
+}
+
+// snapshot is the JSON representation of prng.Stats used by String.
+type snapshot struct {
+	BytesGenerated uint64          `json:"bytes_generated"`
+	KeyRotations   uint64          `json:"key_rotations"`
+	RekeyFailures  uint64          `json:"rekey_failures"`
+	Shards         []shardSnapshot `json:"shards"`
+	Latency        latencySnapshot `json:"latency"`
+}
+
+// shardSnapshot is the JSON representation of prng.ShardStats.
+type shardSnapshot struct {
+	BytesGenerated uint64 `json:"bytes_generated"`
+	KeyRotations   uint64 `json:"key_rotations"`
+	RekeyFailures  uint64 `json:"rekey_failures"`
+}
+
+// latencySnapshot is the JSON representation of prng.LatencyStats.
+type latencySnapshot struct {
+	Read                []histogramSnapshot `json:"read"`
+	Rekey               histogramSnapshot   `json:"rekey"`
+	RekeyBackoffSeconds float64             `json:"rekey_backoff_seconds"`
+	PoolGets            uint64              `json:"pool_gets"`
+	PoolMisses          uint64              `json:"pool_misses"`
+	PoolMissRate        float64             `json:"pool_miss_rate"`
+}
+
+// histogramSnapshot summarizes a prng.Histogram. Quantiles are bucket upper bounds;
+// a quantile in the unbounded bucket is reported as -1.
+type histogramSnapshot struct {
+	MaxBytes   int     `json:"max_bytes,omitempty"`
+	Count      uint64  `json:"count"`
+	SumSeconds float64 `json:"sum_seconds"`
+	P50Seconds float64 `json:"p50_seconds"`
+	P99Seconds float64 `json:"p99_seconds"`
+}
+
+// summarize converts h into its JSON representation.
+func summarize(h prng.Histogram) histogramSnapshot {
+	return histogramSnapshot{
+		Count:      h.Count,
+		SumSeconds: h.Sum.Seconds(),
+		P50Seconds: quantileSeconds(h, 0.5),
+		P99Seconds: quantileSeconds(h, 0.99),
+	}
+}
+
+// quantileSeconds returns h.Quantile(q) in seconds, or -1 if it is unbounded.
+func quantileSeconds(h prng.Histogram, q float64) float64 {
+	d := h.Quantile(q)
+	if d == math.MaxInt64 {
+		return -1
+	}
+	return d.Seconds()
+}
+
+// String implements expvar.Var. It returns the current statistics as a JSON object.
+func (c *Collector) String() string {
+	stats := c.src.Stats()
+	s := snapshot{
+		BytesGenerated: stats.BytesGenerated,
+		KeyRotations:   stats.KeyRotations,
+		RekeyFailures:  stats.RekeyFailures,
+		Shards:         make([]shardSnapshot, len(stats.Shards)),
+	}
+	for i, sh := range stats.Shards {
+		s.Shards[i] = shardSnapshot(sh)
+	}
+	l := stats.Latency
+	s.Latency = latencySnapshot{
+		Read:                make([]histogramSnapshot, len(l.Read)),
+		Rekey:               summarize(l.Rekey),
+		RekeyBackoffSeconds: l.RekeyBackoff.Seconds(),
+		PoolGets:            l.PoolGets,
+		PoolMisses:          l.PoolMisses,
+		PoolMissRate:        l.PoolMissRate(),
+	}
+	for i, rl := range l.Read {
+		s.Latency.Read[i] = summarize(rl.Histogram)
+		s.Latency.Read[i].MaxBytes = rl.MaxBytes
+	}
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		// Marshalling plain numbers cannot fail; keep expvar output valid regardless.
+		return "{}"
+	}
+	return string(b)
+}
+
+// ServeHTTP implements http.Handler. It responds with the current statistics in the
+// Prometheus text exposition format.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	if r.Method == http.MethodHead {
+		return
+	}
+	_, _ = c.WriteTo(w)
+}
+
+// metric describes a single exported counter family.
+type metric struct {
+	name  string
+	help  string
+	total func(prng.Stats) uint64
+	shard func(prng.ShardStats) uint64
+}
+
+// metricFamilies lists the exported counters in output order.
+var metricFamilies = []metric{
+	{
+		name:  "bytes_generated_total",
+		help:  "Total number of random bytes generated.",
+		total: func(s prng.Stats) uint64 { return s.BytesGenerated },
+		shard: func(s prng.ShardStats) uint64 { return s.BytesGenerated },
+	},
+	{
+		name:  "key_rotations_total",
+		help:  "Total number of successful key rotations.",
+		total: func(s prng.Stats) uint64 { return s.KeyRotations },
+		shard: func(s prng.ShardStats) uint64 { return s.KeyRotations },
+	},
+	{
+		name:  "rekey_failures_total",
+		help:  "Total number of failed rekey attempts.",
+		total: func(s prng.Stats) uint64 { return s.RekeyFailures },
+		shard: func(s prng.ShardStats) uint64 { return s.RekeyFailures },
+	},
+}
+
+// WriteTo implements io.WriterTo. It writes the current statistics to w in the Prometheus
+// text exposition format and returns the number of bytes written.
+//
+// Totals are exported as <namespace>_<name>; per-shard counters are exported as
+// <namespace>_shard_<name> with a "shard" label. Latency metrics are exported as the
+// histograms <namespace>_read_latency_seconds (with a "max_bytes" size class label) and
+// <namespace>_rekey_duration_seconds, plus the counters
+// <namespace>_rekey_backoff_seconds_total, <namespace>_pool_gets_total and
+// <namespace>_pool_misses_total. They remain zero unless latency tracking is enabled.
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	stats := c.src.Stats()
+
+	var b strings.Builder
+	for _, m := range metricFamilies {
+		name := c.namespace + "_" + m.name
+		writeHeader(&b, name, m.help, "counter")
+		c.writeSample(&b, name, formatUint(m.total(stats)))
+	}
+	for _, m := range metricFamilies {
+		name := c.namespace + "_shard_" + m.name
+		writeHeader(&b, name, "Per-shard "+strings.ToLower(m.help[:1])+m.help[1:], "counter")
+		for i, sh := range stats.Shards {
+			c.writeSample(&b, name, formatUint(m.shard(sh)), label{"shard", strconv.Itoa(i)})
+		}
+	}
+
+	l := stats.Latency
+	name := c.namespace + "_pool_gets_total"
+	writeHeader(&b, name, "Total number of PRNG instances taken from the pools.", "counter")
+	c.writeSample(&b, name, formatUint(l.PoolGets))
+
+	name = c.namespace + "_pool_misses_total"
+	writeHeader(&b, name, "Total number of PRNG instances created because a pool was empty.", "counter")
+	c.writeSample(&b, name, formatUint(l.PoolMisses))
+
+	name = c.namespace + "_rekey_backoff_seconds_total"
+	writeHeader(&b, name, "Total time spent in backoff between failed rekey attempts.", "counter")
+	c.writeSample(&b, name, formatFloat(l.RekeyBackoff.Seconds()))
+
+	name = c.namespace + "_read_latency_seconds"
+	writeHeader(&b, name, "Latency of sampled Read calls by request size class.", "histogram")
+	for _, rl := range l.Read {
+		class := "+Inf"
+		if rl.MaxBytes > 0 {
+			class = strconv.Itoa(rl.MaxBytes)
+		}
+		c.writeHistogram(&b, name, rl.Histogram, label{"max_bytes", class})
+	}
+
+	name = c.namespace + "_rekey_duration_seconds"
+	writeHeader(&b, name, "Duration of successful rekeys, including backoff.", "histogram")
+	c.writeHistogram(&b, name, l.Rekey)
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// writeHeader writes the HELP and TYPE lines of a metric family.
+func writeHeader(b *strings.Builder, name, help, typ string) {
+	b.WriteString("# HELP ")
+	b.WriteString(name)
+	b.WriteByte(' ')
+	b.WriteString(help)
+	b.WriteString("\n# TYPE ")
+	b.WriteString(name)
+	b.WriteByte(' ')
+	b.WriteString(typ)
+	b.WriteByte('\n')
+}
+
+// writeHistogram writes the cumulative _bucket, _sum and _count samples of h.
+func (c *Collector) writeHistogram(b *strings.Builder, name string, h prng.Histogram, extra ...label) {
+	var cumulative uint64
+	for _, bucket := range h.Buckets {
+		cumulative += bucket.Count
+		le := "+Inf"
+		if bucket.UpperBound != math.MaxInt64 {
+			le = formatFloat(bucket.UpperBound.Seconds())
+		}
+		c.writeSample(b, name+"_bucket", formatUint(cumulative), append(extra, label{"le", le})...)
+	}
+	if len(h.Buckets) == 0 {
+		c.writeSample(b, name+"_bucket", formatUint(h.Count), append(extra, label{"le", "+Inf"})...)
+	}
+	c.writeSample(b, name+"_sum", formatFloat(h.Sum.Seconds()), extra...)
+	c.writeSample(b, name+"_count", formatUint(h.Count), extra...)
+}
+
+// writeSample writes a single sample line with the constant labels followed by extra.
+func (c *Collector) writeSample(b *strings.Builder, name, value string, extra ...label) {
+	b.WriteString(name)
+	if len(c.labels) > 0 || len(extra) > 0 {
+		b.WriteByte('{')
+		sep := ""
+		for _, l := range c.labels {
+			b.WriteString(sep)
+			writeLabel(b, l.name, l.value)
+			sep = ","
+		}
+		for _, l := range extra {
+			b.WriteString(sep)
+			writeLabel(b, l.name, l.value)
+			sep = ","
+		}
+		b.WriteByte('}')
+	}
+	b.WriteByte(' ')
+	b.WriteString(value)
+	b.WriteByte('\n')
+}
+
+// formatUint formats a counter value.
+func formatUint(v uint64) string {
+	return strconv.FormatUint(v, 10)
+}
+
+// formatFloat formats a float sample value in the shortest exact representation.
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// labelEscaper escapes label values as required by the text exposition format.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// writeLabel writes name="value" with the value escaped.
+func writeLabel(b *strings.Builder, name, value string) {
+	b.WriteString(name)
+	b.WriteString(`="`)
+	_, _ = labelEscaper.WriteString(b, value)
+	b.WriteByte('"')
+}
@@ -0,0 +1,241 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package metrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sixafter/prng-chacha"
+	"github.com/stretchr/testify/assert"
+)
+
+// staticSource returns fixed statistics for deterministic output.
+type staticSource prng.Stats
+
+func (s staticSource) Stats() prng.Stats { return prng.Stats(s) }
+
+var fixedStats = staticSource{
+	BytesGenerated: 300,
+	KeyRotations:   3,
+	RekeyFailures:  1,
+	Shards: []prng.ShardStats{
+		{BytesGenerated: 100, KeyRotations: 1, RekeyFailures: 0},
+		{BytesGenerated: 200, KeyRotations: 2, RekeyFailures: 1},
+	},
+}
+
+// TestMetrics_New_Validation verifies that invalid namespaces and label names are rejected.
+func TestMetrics_New_Validation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		opts    []Option
+		wantErr error
+	}{
+		{"EmptyNamespace", []Option{WithNamespace("")}, ErrInvalidNamespace},
+		{"BadNamespace", []Option{WithNamespace("my-app")}, ErrInvalidNamespace},
+		{"BadLabel", []Option{WithLabels(map[string]string{"1x": "v"})}, ErrInvalidLabelName},
+		{"ReservedShardLabel", []Option{WithLabels(map[string]string{"shard": "v"})}, ErrInvalidLabelName},
+		{"ReservedLeLabel", []Option{WithLabels(map[string]string{"le": "v"})}, ErrInvalidLabelName},
+		{"ReservedPrefix", []Option{WithLabels(map[string]string{"__name": "v"})}, ErrInvalidLabelName},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			is := assert.New(t)
+
+			c, err := New(fixedStats, tc.opts...)
+			is.ErrorIs(err, tc.wantErr)
+			is.Nil(c)
+		})
+	}
+}
+
+// TestMetrics_NilSource verifies that New rejects a nil Source.
+func TestMetrics_NilSource(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	c, err := New(nil)
+	is.ErrorIs(err, ErrNilSource)
+	is.Nil(c)
+}
+
+// TestMetrics_String verifies that the expvar representation is valid JSON reflecting the Source.
+func TestMetrics_String(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	c, err := New(fixedStats)
+	is.NoError(err)
+
+	var v expvar.Var = c
+	var got snapshot
+	is.NoError(json.Unmarshal([]byte(v.String()), &got))
+	is.Equal(uint64(300), got.BytesGenerated)
+	is.Equal(uint64(3), got.KeyRotations)
+	is.Equal(uint64(1), got.RekeyFailures)
+	is.Len(got.Shards, 2)
+	is.Equal(uint64(200), got.Shards[1].BytesGenerated)
+}
+
+// TestMetrics_ServeHTTP verifies the Prometheus text output served over HTTP.
+func TestMetrics_ServeHTTP(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	c, err := New(fixedStats,
+		WithNamespace("app_prng"),
+		WithLabels(map[string]string{"reader": `to"k\ens`, "env": "test"}),
+	)
+	is.NoError(err)
+
+	srv := httptest.NewServer(c)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	is.NoError(err)
+	defer func() { _ = resp.Body.Close() }()
+
+	is.Equal(http.StatusOK, resp.StatusCode)
+	is.Equal(ContentType, resp.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(resp.Body)
+	is.NoError(err)
+	text := string(body)
+
+	is.Contains(text, "# TYPE app_prng_bytes_generated_total counter\n")
+	is.Contains(text, `app_prng_bytes_generated_total{env="test",reader="to\"k\\ens"} 300`+"\n")
+	is.Contains(text, `app_prng_key_rotations_total{env="test",reader="to\"k\\ens"} 3`+"\n")
+	is.Contains(text, `app_prng_rekey_failures_total{env="test",reader="to\"k\\ens"} 1`+"\n")
+	is.Contains(text, `app_prng_shard_bytes_generated_total{env="test",reader="to\"k\\ens",shard="0"} 100`+"\n")
+	is.Contains(text, `app_prng_shard_rekey_failures_total{env="test",reader="to\"k\\ens",shard="1"} 1`+"\n")
+
+	// Every non-comment line must be "<name>[{labels}] <value>".
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		is.Len(strings.Split(line[strings.LastIndex(line, "}")+1:], " "), 2, "malformed sample %q", line)
+	}
+}
+
+// TestMetrics_ServeHTTP_Methods verifies HEAD support and rejection of other methods.
+func TestMetrics_ServeHTTP_Methods(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	c, err := New(fixedStats)
+	is.NoError(err)
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/metrics", nil))
+	is.Equal(http.StatusOK, rec.Code)
+	is.Empty(rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/metrics", nil))
+	is.Equal(http.StatusMethodNotAllowed, rec.Code)
+}
+
+// TestMetrics_Latency verifies the export of latency histograms and pool counters.
+func TestMetrics_Latency(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	src := staticSource{
+		Latency: prng.LatencyStats{
+			Read: []prng.ReadLatency{
+				{MaxBytes: 16, Histogram: prng.Histogram{
+					Count: 3,
+					Sum:   400 * time.Nanosecond,
+					Buckets: []prng.Bucket{
+						{UpperBound: 64 * time.Nanosecond, Count: 1},
+						{UpperBound: 128 * time.Nanosecond, Count: 2},
+						{UpperBound: math.MaxInt64, Count: 0},
+					},
+				}},
+				{MaxBytes: 0},
+			},
+			Rekey: prng.Histogram{
+				Count:   1,
+				Sum:     time.Second,
+				Buckets: []prng.Bucket{{UpperBound: math.MaxInt64, Count: 1}},
+			},
+			RekeyBackoff: 1500 * time.Millisecond,
+			PoolGets:     10,
+			PoolMisses:   2,
+		},
+	}
+
+	c, err := New(src)
+	is.NoError(err)
+
+	var sb strings.Builder
+	_, err = c.WriteTo(&sb)
+	is.NoError(err)
+	text := sb.String()
+
+	is.Contains(text, "prng_pool_gets_total 10\n")
+	is.Contains(text, "prng_pool_misses_total 2\n")
+	is.Contains(text, "prng_rekey_backoff_seconds_total 1.5\n")
+	is.Contains(text, "# TYPE prng_read_latency_seconds histogram\n")
+	is.Contains(text, `prng_read_latency_seconds_bucket{max_bytes="16",le="6.4e-08"} 1`+"\n")
+	is.Contains(text, `prng_read_latency_seconds_bucket{max_bytes="16",le="1.28e-07"} 3`+"\n")
+	is.Contains(text, `prng_read_latency_seconds_bucket{max_bytes="16",le="+Inf"} 3`+"\n")
+	is.Contains(text, `prng_read_latency_seconds_sum{max_bytes="16"} 4e-07`+"\n")
+	is.Contains(text, `prng_read_latency_seconds_count{max_bytes="16"} 3`+"\n")
+	is.Contains(text, `prng_read_latency_seconds_bucket{max_bytes="+Inf",le="+Inf"} 0`+"\n")
+	is.Contains(text, `prng_rekey_duration_seconds_bucket{le="+Inf"} 1`+"\n")
+	is.Contains(text, "prng_rekey_duration_seconds_sum 1\n")
+
+	var got snapshot
+	is.NoError(json.Unmarshal([]byte(c.String()), &got))
+	is.Equal(uint64(10), got.Latency.PoolGets)
+	is.InDelta(0.2, got.Latency.PoolMissRate, 1e-9)
+	is.InDelta(1.5, got.Latency.RekeyBackoffSeconds, 1e-9)
+	is.Len(got.Latency.Read, 2)
+	is.Equal(16, got.Latency.Read[0].MaxBytes)
+	is.Equal(uint64(3), got.Latency.Read[0].Count)
+	is.InDelta(128e-9, got.Latency.Read[0].P99Seconds, 1e-15)
+	is.Equal(-1.0, got.Latency.Rekey.P50Seconds, "unbounded quantile should be -1")
+}
+
+// TestMetrics_Reader verifies end-to-end export from a live reader.
+func TestMetrics_Reader(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	r, err := prng.NewReader(prng.WithShards(2))
+	is.NoError(err)
+
+	buf := make([]byte, 64)
+	for i := 0; i < 4; i++ {
+		_, err = r.Read(buf)
+		is.NoError(err)
+	}
+
+	c, err := New(r.(Source))
+	is.NoError(err)
+
+	var sb strings.Builder
+	n, err := c.WriteTo(&sb)
+	is.NoError(err)
+	is.Equal(int64(sb.Len()), n)
+	is.Contains(sb.String(), "prng_bytes_generated_total 256\n")
+	is.Contains(sb.String(), `prng_shard_bytes_generated_total{shard="1"}`)
+}
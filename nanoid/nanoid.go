@@ -0,0 +1,212 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package nanoid generates NanoID-compatible identifiers from the prng package.
+//
+// IDs are produced with the reference NanoID algorithm: random bytes are masked to the
+// smallest power of two covering the alphabet and out-of-range values are rejected, so
+// every character is uniformly distributed. Random bytes are read in batches of the
+// reference "step" size, about 1.6 times the expected need, so an ID practically always
+// costs a single Read; with an alphabet whose size is a power of two, such as the
+// default, it always does.
+//
+// Example:
+//
+//	id, err := nanoid.New()
+//	if err != nil {
+//	    // handle error
+//	}
+//	fmt.Println(id) // V1StGXR8_Z5jdHi6B-myT
+package nanoid
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/bits"
+	"sync"
+	"unicode/utf8"
+	"unsafe"
+
+	"github.com/sixafter/prng-chacha"
+)
+
+var (
+	ErrAlphabetTooShort  = fmt.Errorf("nanoid: alphabet must contain at least 2 characters")
+	ErrAlphabetNotASCII  = fmt.Errorf("nanoid: alphabet must contain only ASCII characters")
+	ErrAlphabetDuplicate = fmt.Errorf("nanoid: alphabet must not contain duplicate characters")
+	ErrSizeInvalid       = fmt.Errorf("nanoid: size must be greater than zero")
+	ErrNilReader         = fmt.Errorf("nanoid: reader must not be nil")
+)
+
+const (
+	// DefaultAlphabet is the URL-safe alphabet of the reference implementation.
+	DefaultAlphabet = "useandom-26T198340PX75pxJACKVERYMINDBUSHWOLF_GQZbfghjklqvwyzrict"
+
+	// DefaultSize is the default ID length, giving a collision probability similar to
+	// UUID version 4.
+	DefaultSize = 21
+)
+
+// Config defines the alphabet, size and randomness source of a Generator.
+type Config struct {
+	// Alphabet is the set of ASCII characters IDs are drawn from, with at least two
+	// distinct characters. Defaults to DefaultAlphabet. Use the token package for
+	// alphabets with multi-byte characters.
+	Alphabet string
+
+	// Size is the number of characters in each ID. Defaults to DefaultSize.
+	Size int
+
+	// Reader is the source of randomness. Defaults to prng.Reader.
+	Reader io.Reader
+}
+
+// Option defines a functional option for customizing a Generator's Config.
+type Option func(*Config)
+
+// WithAlphabet returns an Option that sets the characters IDs are drawn from.
+func WithAlphabet(alphabet string) Option {
+	return func(cfg *Config) {
+		cfg.Alphabet = alphabet
+	}
+}
+
+// WithSize returns an Option that sets the number of characters in each ID.
+func WithSize(n int) Option {
+	return func(cfg *Config) {
+		cfg.Size = n
+	}
+}
+
+// WithReader returns an Option that sets the source of randomness, typically a
+// prng.Interface returned by prng.NewReader.
+func WithReader(r io.Reader) Option {
+	return func(cfg *Config) {
+		cfg.Reader = r
+	}
+}
+
+// Generator produces NanoIDs. It is immutable after construction and safe for
+// concurrent use.
+type Generator struct {
+	alphabet string
+	size     int
+	mask     byte
+	step     int
+	src      io.Reader
+	bufs     sync.Pool
+}
+
+// defaultGenerator backs the package-level New.
+var defaultGenerator = func() *Generator {
+	g, err := NewGenerator()
+	if err != nil {
+		panic(err)
+	}
+	return g
+}()
+
+// NewGenerator returns a Generator configured by opts.
+//
+// It returns ErrAlphabetTooShort, ErrAlphabetNotASCII or ErrAlphabetDuplicate for an
+// unusable alphabet, ErrSizeInvalid for a non-positive size, and ErrNilReader if the
+// configured reader is nil.
+func NewGenerator(opts ...Option) (*Generator, error) {
+	cfg := Config{
+		Alphabet: DefaultAlphabet,
+		Size:     DefaultSize,
+		Reader:   prng.Reader,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.Reader == nil {
+		return nil, ErrNilReader
+	}
+	if cfg.Size <= 0 {
+		return nil, ErrSizeInvalid
+	}
+	n := len(cfg.Alphabet)
+	if n < 2 {
+		return nil, ErrAlphabetTooShort
+	}
+	var seen [utf8.RuneSelf]bool
+	for i := 0; i < n; i++ {
+		c := cfg.Alphabet[i]
+		if c >= utf8.RuneSelf {
+			return nil, ErrAlphabetNotASCII
+		}
+		if seen[c] {
+			return nil, ErrAlphabetDuplicate
+		}
+		seen[c] = true
+	}
+
+	// As in the reference implementation:
+	//   mask = (2 << (31 - clz32((length - 1) | 1))) - 1
+	//   step = ceil(1.6 * mask * size / length)
+	mask := byte(1<<bits.Len8(uint8((n-1)|1)) - 1)
+	step := int(math.Ceil(1.6 * float64(mask) * float64(cfg.Size) / float64(n)))
+
+	g := &Generator{
+		alphabet: cfg.Alphabet,
+		size:     cfg.Size,
+		mask:     mask,
+		step:     step,
+		src:      cfg.Reader,
+	}
+	g.bufs.New = func() any {
+		b := make([]byte, g.step)
+		return &b
+	}
+	return g, nil
+}
+
+// New returns a NanoID from the default Generator: 21 characters of DefaultAlphabet.
+func New() (string, error) {
+	return defaultGenerator.New()
+}
+
+// Alphabet returns the alphabet IDs are drawn from.
+func (g *Generator) Alphabet() string {
+	return g.alphabet
+}
+
+// Size returns the number of characters in each ID.
+func (g *Generator) Size() int {
+	return g.size
+}
+
+// New returns a new ID.
+//
+// It returns an error only if the underlying reader fails.
+func (g *Generator) New() (string, error) {
+	bp := g.bufs.Get().(*[]byte)
+	defer g.bufs.Put(bp)
+	buf := *bp
+
+	id := make([]byte, g.size)
+	for i := 0; ; {
+		if _, err := io.ReadFull(g.src, buf); err != nil {
+			clear(buf)
+			return "", err
+		}
+		for _, b := range buf {
+			idx := int(b & g.mask)
+			if idx >= len(g.alphabet) {
+				continue
+			}
+			id[i] = g.alphabet[idx]
+			i++
+			if i == g.size {
+				clear(buf)
+				// id is never modified after this point, so it can back the string directly.
+				return unsafe.String(unsafe.SliceData(id), len(id)), nil
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package nanoid
+
+import (
+	"strconv"
+	"testing"
+)
+
+// BenchmarkNanoID_Serial measures default IDs in a serial loop.
+func BenchmarkNanoID_Serial(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		_, _ = New()
+	}
+}
+
+// BenchmarkNanoID_Parallel measures default IDs with RunParallel.
+func BenchmarkNanoID_Parallel(b *testing.B) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = New()
+		}
+	})
+}
+
+// BenchmarkNanoID_Sizes measures default-alphabet IDs across sizes.
+func BenchmarkNanoID_Sizes(b *testing.B) {
+	for _, n := range []int{8, 21, 36, 64, 128} {
+		b.Run("Size_"+strconv.Itoa(n), func(b *testing.B) {
+			g, err := NewGenerator(WithSize(n))
+			if err != nil {
+				b.Fatalf("NewGenerator failed: %v", err)
+			}
+			b.ReportAllocs()
+			for b.Loop() {
+				_, _ = g.New()
+			}
+		})
+	}
+}
+
+// BenchmarkNanoID_CustomAlphabet measures a 36-character alphabet, where rejection
+// discards about 44% of the random bytes.
+func BenchmarkNanoID_CustomAlphabet(b *testing.B) {
+	g, err := NewGenerator(WithAlphabet("abcdefghijklmnopqrstuvwxyz0123456789"))
+	if err != nil {
+		b.Fatalf("NewGenerator failed: %v", err)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		_, _ = g.New()
+	}
+}
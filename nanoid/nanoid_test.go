@@ -0,0 +1,230 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package nanoid
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sixafter/prng-chacha"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingReader wraps a reader and counts Read calls.
+type countingReader struct {
+	r     io.Reader
+	mu    sync.Mutex
+	calls int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	c.mu.Lock()
+	c.calls++
+	c.mu.Unlock()
+	return c.r.Read(p)
+}
+
+// errReader always fails.
+type errReader struct{}
+
+var errRead = errors.New("read failed")
+
+func (errReader) Read([]byte) (int, error) { return 0, errRead }
+
+// referenceCustomRandom is a direct port of customRandom from the reference JavaScript
+// implementation, used to check that Generator consumes random bytes identically.
+func referenceCustomRandom(alphabet string, size int, random func(int) []byte) string {
+	clz32 := func(x uint32) int {
+		n := 0
+		for i := 31; i >= 0 && x&(1<<i) == 0; i-- {
+			n++
+		}
+		return n
+	}
+	mask := (2 << (31 - clz32(uint32((len(alphabet)-1)|1)))) - 1
+	step := int(math.Ceil(1.6 * float64(mask) * float64(size) / float64(len(alphabet))))
+
+	id := ""
+	for {
+		bytes := random(step)
+		for i := 0; i < step; i++ {
+			idx := int(bytes[i]) & mask
+			if idx < len(alphabet) {
+				id += string(alphabet[idx])
+				if len(id) == size {
+					return id
+				}
+			}
+		}
+	}
+}
+
+// TestNanoID_Validation verifies that invalid configurations are rejected.
+func TestNanoID_Validation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		opts    []Option
+		wantErr error
+	}{
+		{"EmptyAlphabet", []Option{WithAlphabet("")}, ErrAlphabetTooShort},
+		{"SingleCharacter", []Option{WithAlphabet("x")}, ErrAlphabetTooShort},
+		{"NonASCII", []Option{WithAlphabet("abcé")}, ErrAlphabetNotASCII},
+		{"Duplicate", []Option{WithAlphabet("abcb")}, ErrAlphabetDuplicate},
+		{"ZeroSize", []Option{WithSize(0)}, ErrSizeInvalid},
+		{"NilReader", []Option{WithReader(nil)}, ErrNilReader},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			is := assert.New(t)
+
+			g, err := NewGenerator(tc.opts...)
+			is.ErrorIs(err, tc.wantErr)
+			is.Nil(g)
+		})
+	}
+}
+
+// TestNanoID_Default verifies the default size and alphabet.
+func TestNanoID_Default(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	seen := make(map[string]struct{})
+	for i := 0; i < 1000; i++ {
+		id, err := New()
+		is.NoError(err)
+		is.Len(id, DefaultSize)
+		for _, c := range id {
+			is.True(strings.ContainsRune(DefaultAlphabet, c), "unexpected character %q", c)
+		}
+		_, dup := seen[id]
+		is.False(dup)
+		seen[id] = struct{}{}
+	}
+}
+
+// TestNanoID_MatchesReference verifies that, given the same random bytes, Generator
+// produces exactly the IDs of the reference algorithm.
+func TestNanoID_MatchesReference(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		alphabet string
+		size     int
+	}{
+		{DefaultAlphabet, DefaultSize},
+		{"0123456789", 12},
+		{"abcdefghijklmnopqrstuvwxyz0123456789", 30},
+		{"ab", 5},
+		{"abc", 100},
+	}
+
+	for _, tc := range testCases {
+		src := make([]byte, 1<<16)
+		_, err := prng.Reader.Read(src)
+		assert.NoError(t, err)
+
+		g, err := NewGenerator(WithAlphabet(tc.alphabet), WithSize(tc.size), WithReader(bytes.NewReader(src)))
+		assert.NoError(t, err)
+
+		ref := bytes.NewReader(src)
+		random := func(n int) []byte {
+			b := make([]byte, n)
+			_, _ = io.ReadFull(ref, b)
+			return b
+		}
+		for i := 0; i < 20; i++ {
+			got, err := g.New()
+			assert.NoError(t, err)
+			assert.Equal(t, referenceCustomRandom(tc.alphabet, tc.size, random), got, "alphabet %q", tc.alphabet)
+		}
+	}
+}
+
+// TestNanoID_SingleRead verifies that each ID costs exactly one Read with a power-of-two
+// alphabet.
+func TestNanoID_SingleRead(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	src := &countingReader{r: prng.Reader}
+	g, err := NewGenerator(WithReader(src))
+	is.NoError(err)
+
+	for i := 0; i < 100; i++ {
+		_, err = g.New()
+		is.NoError(err)
+	}
+	is.Equal(100, src.calls)
+}
+
+// TestNanoID_ChiSquared checks that characters are uniformly distributed, as in the
+// reference implementation, for power-of-two and rejection-heavy alphabets.
+func TestNanoID_ChiSquared(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		alphabet string
+		critical float64 // chi-squared critical value at p = 0.0001 for len(alphabet)-1 degrees of freedom
+	}{
+		{"Default", DefaultAlphabet, 114.3},
+		{"Digits", "0123456789", 33.7},
+		{"Base36", "abcdefghijklmnopqrstuvwxyz0123456789", 71.0},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			is := assert.New(t)
+
+			g, err := NewGenerator(WithAlphabet(tc.alphabet))
+			is.NoError(err)
+
+			counts := make(map[byte]int)
+			const ids = 20000
+			for i := 0; i < ids; i++ {
+				id, err := g.New()
+				is.NoError(err)
+				for j := 0; j < len(id); j++ {
+					counts[id[j]]++
+				}
+			}
+
+			expected := float64(ids*DefaultSize) / float64(len(tc.alphabet))
+			var chi2 float64
+			for i := 0; i < len(tc.alphabet); i++ {
+				d := float64(counts[tc.alphabet[i]]) - expected
+				chi2 += d * d / expected
+			}
+			is.Less(chi2, tc.critical, "chi-squared statistic %.2f suggests bias", chi2)
+		})
+	}
+}
+
+// TestNanoID_ReadError verifies that reader errors are returned.
+func TestNanoID_ReadError(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	g, err := NewGenerator(WithReader(errReader{}))
+	is.NoError(err)
+
+	id, err := g.New()
+	is.ErrorIs(err, errRead)
+	is.Empty(id)
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package prng
+
+// Observer receives notifications about lifecycle events of a PRNG reader.
+//
+// Observers are intended for alerting and auditing: for example, paging when rekeys fail
+// or recording every key rotation. Callbacks only ever receive shard indices, attempt counts
+// and errors; key material, nonces and keystream output are never exposed.
+//
+// Callbacks are invoked synchronously, on the goroutine where the event happens:
+//   - OnKeyRotated runs once per rotation, inside the Read call that installs the new
+//     cipher, before that Read generates any output.
+//   - OnRekeyFailed runs on the background goroutine performing the rekey or, with
+//     Config.FailClosed, inside the Read call that exhausted the key and is rekeying it.
+//   - OnInstanceCreated and OnInitFailed run when a pool creates a new PRNG instance, which
+//     happens during NewReader and, rarely, when a pool is empty during Read.
+//
+// Callbacks may be invoked concurrently from multiple goroutines and must be safe for
+// concurrent use. They must return quickly and must not block: a slow OnKeyRotated stalls
+// the Read that calls it, and slow callbacks in general delay key rotation or pool
+// refills. Hand any slow work, such as network I/O, to another goroutine. Embed
+// NopObserver to implement only the callbacks of interest.
+//
+// Example:
+//
+//	type alerts struct{ prng.NopObserver }
+//
+//	func (alerts) OnRekeyFailed(shard int, err error, attempt int) {
+//	    log.Printf("rekey failed on shard %d (attempt %d): %v", shard, attempt, err)
+//	}
+//
+//	r, err := prng.NewReader(prng.WithObserver(alerts{}))
+type Observer interface {
+	// OnKeyRotated is called after a new key and nonce have been installed on an
+	// instance in the given shard, which happens on that instance's first Read after a
+	// successful rekey. It runs synchronously inside that Read and must not block. A
+	// prepared key whose instance is dropped before then is never reported.
+	OnKeyRotated(shard int)
+
+	// OnRekeyFailed is called after each failed rekey attempt for an instance in the given
+	// shard. attempt is 1-based. By default the existing key stays in use while attempts
+	// fail; with Config.FailClosed the exhausted key produces no further output, and Read
+	// returns ErrRekeyFailed once every attempt has failed.
+	OnRekeyFailed(shard int, err error, attempt int)
+
+	// OnInstanceCreated is called after a pool successfully creates a new PRNG instance
+	// for the given shard.
+	OnInstanceCreated(shard int)
+
+	// OnInitFailed is called when a pool exhausts MaxInitRetries without creating a PRNG
+	// instance for the given shard.
+	OnInitFailed(shard int, err error)
+}
+
+// NopObserver is an Observer whose callbacks do nothing.
+//
+// Embed it in a struct to implement only a subset of the Observer callbacks.
+type NopObserver struct{}
+
+// OnKeyRotated implements Observer and does nothing.
+func (NopObserver) OnKeyRotated(int) {}
+
+// OnRekeyFailed implements Observer and does nothing.
+func (NopObserver) OnRekeyFailed(int, error, int) {}
+
+// OnInstanceCreated implements Observer and does nothing.
+func (NopObserver) OnInstanceCreated(int) {}
+
+// OnInitFailed implements Observer and does nothing.
+func (NopObserver) OnInitFailed(int, error) {}
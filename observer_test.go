@@ -0,0 +1,198 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package prng
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var _ Observer = NopObserver{}
+
+// errEntropy is returned by failingSource to simulate an unavailable entropy source.
+var errEntropy = errors.New("entropy source unavailable")
+
+// failingSource is an io.Reader that always fails, used to force cipher construction errors.
+type failingSource struct{}
+
+func (failingSource) Read([]byte) (int, error) { return 0, errEntropy }
+
+// rekeyFailure records the arguments of a single OnRekeyFailed callback.
+type rekeyFailure struct {
+	shard   int
+	err     error
+	attempt int
+}
+
+// recordingObserver captures every callback it receives for later assertions.
+type recordingObserver struct {
+	mu           sync.Mutex
+	rotated      []int
+	created      []int
+	rekeyFailed  []rekeyFailure
+	initFailed   []int
+	initFailures []error
+}
+
+func (o *recordingObserver) OnKeyRotated(shard int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.rotated = append(o.rotated, shard)
+}
+
+func (o *recordingObserver) OnRekeyFailed(shard int, err error, attempt int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.rekeyFailed = append(o.rekeyFailed, rekeyFailure{shard: shard, err: err, attempt: attempt})
+}
+
+func (o *recordingObserver) OnInstanceCreated(shard int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.created = append(o.created, shard)
+}
+
+func (o *recordingObserver) OnInitFailed(shard int, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.initFailed = append(o.initFailed, shard)
+	o.initFailures = append(o.initFailures, err)
+}
+
+// Test_Observer_InstanceCreated verifies that eager pool initialization reports one
+// created instance per shard.
+func Test_Observer_InstanceCreated(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	obs := &recordingObserver{}
+	_, err := NewReader(WithShards(3), WithObserver(obs))
+	is.NoError(err)
+
+	obs.mu.Lock()
+	defer obs.mu.Unlock()
+	created := append([]int(nil), obs.created...)
+	sort.Ints(created)
+	is.Equal([]int{0, 1, 2}, created, "each shard should report its first instance")
+	is.Empty(obs.initFailed)
+}
+
+// Test_Observer_InitFailed verifies that a pool that cannot create an instance reports
+// the failure with its shard index and an error.
+func Test_Observer_InitFailed(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	obs := &recordingObserver{}
+	rdr, err := NewReader(WithShards(1), WithMaxInitRetries(0), WithObserver(obs))
+	is.Error(err)
+	is.Nil(rdr)
+
+	obs.mu.Lock()
+	defer obs.mu.Unlock()
+	is.Equal([]int{0}, obs.initFailed)
+	is.Len(obs.initFailures, 1)
+	is.Error(obs.initFailures[0])
+	is.Empty(obs.created)
+}
+
+// Test_Observer_InitFailed_Cause verifies that the init failure reported to the observer
+// and returned by newInstance wraps the underlying entropy error.
+func Test_Observer_InitFailed_Cause(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	obs := &recordingObserver{}
+	cfg := DefaultConfig()
+	cfg.Shards = 8
+	cfg.Observer = obs
+	r := newReader(&cfg)
+	r.entropy = failingSource{}
+
+	p, err := r.newInstance(2)
+	is.Nil(p)
+	is.ErrorIs(err, errEntropy)
+
+	obs.mu.Lock()
+	defer obs.mu.Unlock()
+	is.Equal([]int{2}, obs.initFailed)
+	is.ErrorIs(obs.initFailures[0], errEntropy)
+}
+
+// Test_Observer_KeyRotated verifies that a successful rekey reports the shard of the
+// rotated instance.
+func Test_Observer_KeyRotated(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	obs := &recordingObserver{}
+	cfg := DefaultConfig()
+	cfg.Shards = 8
+	cfg.EnableKeyRotation = true
+	cfg.Observer = obs
+	r := newReader(&cfg)
+
+	p, err := newPRNG(r, 5)
+	is.NoError(err)
+
+	atomic.StoreUint32(&p.rekeying, 1)
+	p.asyncRekey()
+
+	// The rotation is reported when the owner installs the new cipher.
+	_, err = p.Read(make([]byte, 16))
+	is.NoError(err)
+
+	obs.mu.Lock()
+	defer obs.mu.Unlock()
+	is.Equal([]int{5}, obs.rotated)
+	is.Empty(obs.rekeyFailed)
+}
+
+// Test_Observer_RekeyFailed verifies that each failed rekey attempt is reported with a
+// 1-based attempt number and the underlying error, and that no rotation is reported.
+func Test_Observer_RekeyFailed(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	obs := &recordingObserver{}
+	cfg := DefaultConfig()
+	cfg.Shards = 8
+	cfg.EnableKeyRotation = true
+	cfg.MaxRekeyAttempts = 3
+	cfg.RekeyBackoff = time.Millisecond
+	cfg.MaxRekeyBackoff = 2 * time.Millisecond
+	cfg.Observer = obs
+	r := newReader(&cfg)
+
+	p, err := newPRNG(r, 1)
+	is.NoError(err)
+
+	// Break the entropy source only after the instance has been created.
+	r.entropy = failingSource{}
+
+	atomic.StoreUint32(&p.rekeying, 1)
+	p.asyncRekey()
+
+	is.Equal(uint32(0), atomic.LoadUint32(&p.rekeying), "flag should be cleared after giving up")
+	is.Nil(p.pending.Load(), "no cipher should be pending after failures")
+	is.Equal(uint64(0), r.Stats().KeyRotations)
+
+	obs.mu.Lock()
+	defer obs.mu.Unlock()
+	is.Empty(obs.rotated)
+	is.Len(obs.rekeyFailed, 3)
+	for i, f := range obs.rekeyFailed {
+		is.Equal(1, f.shard)
+		is.Equal(i+1, f.attempt)
+		is.ErrorIs(f.err, errEntropy)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package password
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/sixafter/prng-chacha/internal/randutil"
+)
+
+var (
+	ErrWordListTooShort  = fmt.Errorf("password: word list must contain at least 2 words")
+	ErrWordListDuplicate = fmt.Errorf("password: word list must not contain duplicate or empty words")
+	ErrWordCountInvalid  = fmt.Errorf("password: word count must be between 1 and %d", MaxWordCount)
+	ErrSeparatorInvalid  = fmt.Errorf("password: separator must be non-empty and share no characters with the words")
+)
+
+const (
+	// MaxWordCount is the largest number of words in a passphrase.
+	MaxWordCount = 64
+
+	// defaultWordCount is the default number of words in a passphrase.
+	defaultWordCount = 6
+
+	// defaultSeparator is the default separator between passphrase words.
+	defaultSeparator = "-"
+)
+
+// Passphrase produces passphrases of words picked uniformly and independently from a
+// word list. It is immutable after construction and safe for concurrent use.
+type Passphrase struct {
+	words     []string
+	count     int
+	separator string
+	src       io.Reader
+}
+
+// NewPassphrase returns a Passphrase generator drawing from words, configured by opts.
+// The word list is copied.
+//
+// It returns ErrWordListTooShort or ErrWordListDuplicate for an unusable word list,
+// ErrWordCountInvalid for an out-of-range word count, ErrSeparatorInvalid if passphrases
+// have more than one word and the separator is empty or shares a character with a word,
+// and ErrNilReader if the configured reader is nil.
+func NewPassphrase(words []string, opts ...Option) (*Passphrase, error) {
+	cfg := defaultConfig()
+	cfg.Words = words
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.Reader == nil {
+		return nil, ErrNilReader
+	}
+	if cfg.WordCount <= 0 || cfg.WordCount > MaxWordCount {
+		return nil, ErrWordCountInvalid
+	}
+	if len(cfg.Words) < 2 {
+		return nil, ErrWordListTooShort
+	}
+	// Words separated by characters they never contain can be split back apart, so
+	// each passphrase comes from exactly one word sequence and EntropyBits is exact.
+	if cfg.WordCount > 1 && cfg.Separator == "" {
+		return nil, ErrSeparatorInvalid
+	}
+	seen := make(map[string]struct{}, len(cfg.Words))
+	for _, w := range cfg.Words {
+		if _, dup := seen[w]; dup || w == "" {
+			return nil, ErrWordListDuplicate
+		}
+		if cfg.WordCount > 1 && strings.ContainsAny(w, cfg.Separator) {
+			return nil, ErrSeparatorInvalid
+		}
+		seen[w] = struct{}{}
+	}
+
+	return &Passphrase{
+		words:     append([]string(nil), cfg.Words...),
+		count:     cfg.WordCount,
+		separator: cfg.Separator,
+		src:       cfg.Reader,
+	}, nil
+}
+
+// WordCount returns the number of words in each passphrase.
+func (p *Passphrase) WordCount() int {
+	return p.count
+}
+
+// EntropyBits returns the entropy of a generated passphrase in bits: the word count
+// times log2 of the word list size. Separators add no entropy. The value is exact because
+// NewPassphrase rejects separators that would let two word sequences produce the same
+// passphrase.
+func (p *Passphrase) EntropyBits() float64 {
+	return float64(p.count) * math.Log2(float64(len(p.words)))
+}
+
+// Generate returns a new passphrase.
+//
+// It returns an error only if the underlying reader fails.
+func (p *Passphrase) Generate() (string, error) {
+	r := randutil.NewReader(p.src)
+	defer r.Wipe()
+
+	var b strings.Builder
+	for i := 0; i < p.count; i++ {
+		j, err := r.Intn(len(p.words))
+		if err != nil {
+			return "", err
+		}
+		if i > 0 {
+			b.WriteString(p.separator)
+		}
+		b.WriteString(p.words[j])
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,431 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package password generates passwords that satisfy composition policies and
+// word-based passphrases, using randomness from the prng package.
+//
+// Passwords are drawn uniformly from the set of all strings that satisfy the policy,
+// rather than generated and then patched to comply, which would over-represent some
+// characters and positions. The Generator counts the compliant passwords exactly,
+// picks how many characters of each class to use with probability proportional to the
+// number of passwords having those counts, then places the classes in a uniformly
+// random order and picks each character uniformly within its class.
+//
+// Example:
+//
+//	g, err := password.New(password.WithLength(16), password.WithMinDigits(2), password.WithExcludeAmbiguous())
+//	if err != nil {
+//	    // handle error
+//	}
+//	pw, err := g.Generate()
+//	fmt.Printf("%s (%.1f bits)\n", pw, g.EntropyBits())
+package password
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/sixafter/prng-chacha"
+	"github.com/sixafter/prng-chacha/internal/randutil"
+)
+
+var (
+	ErrLengthInvalid        = fmt.Errorf("password: length must be between 1 and %d", MaxLength)
+	ErrMinimumNegative      = fmt.Errorf("password: minimum character counts cannot be negative")
+	ErrMinimumsExceedLength = fmt.Errorf("password: minimum character counts exceed the length")
+	ErrClassEmpty           = fmt.Errorf("password: a character class with a minimum count has no characters left after exclusions")
+	ErrClassesOverlap       = fmt.Errorf("password: character classes must not share characters")
+	ErrNoCharacters         = fmt.Errorf("password: no characters available")
+	ErrNotASCII             = fmt.Errorf("password: character classes must contain only ASCII characters")
+	ErrNilReader            = fmt.Errorf("password: reader must not be nil")
+)
+
+// Default character classes.
+const (
+	DefaultUpper   = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	DefaultLower   = "abcdefghijklmnopqrstuvwxyz"
+	DefaultDigits  = "0123456789"
+	DefaultSymbols = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+
+	// Ambiguous lists characters that are easily confused when read or typed:
+	// zero and capital O, one with capital I and lower-case l, the vertical bar, and quotes.
+	Ambiguous = "0Oo1Il|`'\""
+)
+
+const (
+	// MaxLength is the longest password a Generator produces. New counts compliant
+	// passwords with a number of big-integer products quadratic in the length, so the
+	// limit keeps construction within tens of milliseconds.
+	MaxLength = 256
+
+	// defaultLength is the default password length.
+	defaultLength = 16
+)
+
+// Config defines a password policy, or a passphrase format, and the randomness source.
+//
+// Length, the Min fields, the class fields and Exclude apply to New; Words, WordCount
+// and Separator apply to NewPassphrase.
+type Config struct {
+	// Length is the number of characters in each password. Defaults to 16.
+	Length int
+
+	// MinUpper, MinLower, MinDigits and MinSymbols are the minimum number of characters
+	// from each class. They default to zero.
+	MinUpper   int
+	MinLower   int
+	MinDigits  int
+	MinSymbols int
+
+	// Upper, Lower, Digits and Symbols are the ASCII characters of each class and
+	// default to DefaultUpper, DefaultLower, DefaultDigits and DefaultSymbols. An empty
+	// class is not used. Classes must not share characters.
+	Upper   string
+	Lower   string
+	Digits  string
+	Symbols string
+
+	// Exclude lists characters removed from every class, such as Ambiguous.
+	Exclude string
+
+	// Words is the passphrase word list. Duplicates are rejected because they would
+	// make some words more likely than others.
+	Words []string
+
+	// WordCount is the number of words in each passphrase. Defaults to 6.
+	WordCount int
+
+	// Separator is placed between passphrase words. Defaults to "-". It must be non-empty
+	// and share no characters with the words unless WordCount is 1.
+	Separator string
+
+	// Reader is the source of randomness. Defaults to prng.Reader.
+	Reader io.Reader
+}
+
+// Option defines a functional option for customizing a Config.
+type Option func(*Config)
+
+// WithLength returns an Option that sets the password length.
+func WithLength(n int) Option {
+	return func(cfg *Config) {
+		cfg.Length = n
+	}
+}
+
+// WithMinUpper returns an Option that requires at least n upper-case characters.
+func WithMinUpper(n int) Option {
+	return func(cfg *Config) {
+		cfg.MinUpper = n
+	}
+}
+
+// WithMinLower returns an Option that requires at least n lower-case characters.
+func WithMinLower(n int) Option {
+	return func(cfg *Config) {
+		cfg.MinLower = n
+	}
+}
+
+// WithMinDigits returns an Option that requires at least n digits.
+func WithMinDigits(n int) Option {
+	return func(cfg *Config) {
+		cfg.MinDigits = n
+	}
+}
+
+// WithMinSymbols returns an Option that requires at least n symbols.
+func WithMinSymbols(n int) Option {
+	return func(cfg *Config) {
+		cfg.MinSymbols = n
+	}
+}
+
+// WithSymbols returns an Option that sets the symbol class. Pass "" to exclude symbols.
+func WithSymbols(symbols string) Option {
+	return func(cfg *Config) {
+		cfg.Symbols = symbols
+	}
+}
+
+// WithClasses returns an Option that sets all four character classes. Pass "" for a
+// class to exclude it.
+func WithClasses(upper, lower, digits, symbols string) Option {
+	return func(cfg *Config) {
+		cfg.Upper, cfg.Lower, cfg.Digits, cfg.Symbols = upper, lower, digits, symbols
+	}
+}
+
+// WithExclude returns an Option that removes chars from every class.
+func WithExclude(chars string) Option {
+	return func(cfg *Config) {
+		cfg.Exclude += chars
+	}
+}
+
+// WithExcludeAmbiguous returns an Option that removes the Ambiguous characters.
+func WithExcludeAmbiguous() Option {
+	return WithExclude(Ambiguous)
+}
+
+// WithWordCount returns an Option that sets the number of words in a passphrase.
+func WithWordCount(n int) Option {
+	return func(cfg *Config) {
+		cfg.WordCount = n
+	}
+}
+
+// WithSeparator returns an Option that sets the separator between passphrase words.
+func WithSeparator(sep string) Option {
+	return func(cfg *Config) {
+		cfg.Separator = sep
+	}
+}
+
+// WithReader returns an Option that sets the source of randomness, typically a
+// prng.Interface returned by prng.NewReader.
+func WithReader(r io.Reader) Option {
+	return func(cfg *Config) {
+		cfg.Reader = r
+	}
+}
+
+// defaultConfig returns the default Config before options are applied.
+func defaultConfig() Config {
+	return Config{
+		Length:    defaultLength,
+		Upper:     DefaultUpper,
+		Lower:     DefaultLower,
+		Digits:    DefaultDigits,
+		Symbols:   DefaultSymbols,
+		WordCount: defaultWordCount,
+		Separator: defaultSeparator,
+		Reader:    prng.Reader,
+	}
+}
+
+// class is a character class remaining after exclusions.
+type class struct {
+	chars []byte
+	min   int
+}
+
+// Generator produces passwords uniformly at random from the set of strings satisfying
+// its policy. It is immutable after construction and safe for concurrent use.
+type Generator struct {
+	length  int
+	classes []class
+	src     io.Reader
+
+	// ways[c][r] is the number of ways to fill r positions using classes c and later,
+	// respecting their minimums. ways[0][length] is the number of compliant passwords.
+	ways [][]*big.Int
+
+	// pow[c][k] is len(classes[c].chars)^k.
+	pow [][]*big.Int
+}
+
+// New returns a password Generator for the policy configured by opts.
+//
+// It returns ErrLengthInvalid, ErrMinimumNegative or ErrMinimumsExceedLength for
+// inconsistent sizes; ErrNotASCII or ErrClassesOverlap for invalid classes;
+// ErrClassEmpty if exclusions empty a class that has a minimum; ErrNoCharacters if no
+// characters remain; and ErrNilReader if the configured reader is nil.
+func New(opts ...Option) (*Generator, error) {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.Reader == nil {
+		return nil, ErrNilReader
+	}
+	if cfg.Length <= 0 || cfg.Length > MaxLength {
+		return nil, ErrLengthInvalid
+	}
+	mins := []int{cfg.MinUpper, cfg.MinLower, cfg.MinDigits, cfg.MinSymbols}
+	total := 0
+	for _, m := range mins {
+		if m < 0 {
+			return nil, ErrMinimumNegative
+		}
+		total += m
+	}
+	if total > cfg.Length {
+		return nil, ErrMinimumsExceedLength
+	}
+
+	var used [128]bool
+	var classes []class
+	for i, set := range []string{cfg.Upper, cfg.Lower, cfg.Digits, cfg.Symbols} {
+		var chars []byte
+		for j := 0; j < len(set); j++ {
+			c := set[j]
+			if c >= 128 {
+				return nil, ErrNotASCII
+			}
+			if used[c] {
+				return nil, ErrClassesOverlap
+			}
+			used[c] = true
+			if !strings.ContainsRune(cfg.Exclude, rune(c)) {
+				chars = append(chars, c)
+			}
+		}
+		if len(chars) == 0 {
+			if mins[i] > 0 {
+				return nil, ErrClassEmpty
+			}
+			continue
+		}
+		classes = append(classes, class{chars: chars, min: mins[i]})
+	}
+	if len(classes) == 0 {
+		return nil, ErrNoCharacters
+	}
+
+	g := &Generator{
+		length:  cfg.Length,
+		classes: classes,
+		src:     cfg.Reader,
+	}
+	g.count()
+	return g, nil
+}
+
+// count fills the pow and ways tables.
+func (g *Generator) count() {
+	n := g.length
+
+	g.pow = make([][]*big.Int, len(g.classes))
+	for c, cl := range g.classes {
+		size := big.NewInt(int64(len(cl.chars)))
+		g.pow[c] = make([]*big.Int, n+1)
+		g.pow[c][0] = big.NewInt(1)
+		for k := 1; k <= n; k++ {
+			g.pow[c][k] = new(big.Int).Mul(g.pow[c][k-1], size)
+		}
+	}
+
+	g.ways = make([][]*big.Int, len(g.classes)+1)
+	for c := range g.ways {
+		g.ways[c] = make([]*big.Int, n+1)
+	}
+	last := g.ways[len(g.classes)]
+	for r := range last {
+		last[r] = new(big.Int)
+	}
+	last[0].SetInt64(1)
+
+	// Only one row of Pascal's triangle is kept: binom[k] is r choose k while row r of
+	// ways is filled, so memory stays linear in the length.
+	binom := make([]*big.Int, n+1)
+	w := new(big.Int)
+	for r := 0; r <= n; r++ {
+		binom[r] = big.NewInt(1)
+		for k := r - 1; k > 0; k-- {
+			binom[k].Add(binom[k], binom[k-1])
+		}
+		for c := len(g.classes) - 1; c >= 0; c-- {
+			sum := new(big.Int)
+			for k := g.classes[c].min; k <= r; k++ {
+				sum.Add(sum, g.weight(w, binom[k], c, r, k))
+			}
+			g.ways[c][r] = sum
+		}
+	}
+}
+
+// weight sets w to the number of ways to fill r positions with exactly k characters of
+// class c and the rest from later classes, given binom = r choose k, and returns w.
+func (g *Generator) weight(w, binom *big.Int, c, r, k int) *big.Int {
+	w.Mul(binom, g.pow[c][k])
+	return w.Mul(w, g.ways[c+1][r-k])
+}
+
+// Length returns the number of characters in each password.
+func (g *Generator) Length() int {
+	return g.length
+}
+
+// Count returns the number of distinct passwords satisfying the policy.
+func (g *Generator) Count() *big.Int {
+	return new(big.Int).Set(g.ways[0][g.length])
+}
+
+// EntropyBits returns log2 of the number of compliant passwords: the entropy of a
+// generated password in bits.
+func (g *Generator) EntropyBits() float64 {
+	return log2(g.ways[0][g.length])
+}
+
+// Generate returns a new password.
+//
+// It returns an error only if the underlying reader fails.
+func (g *Generator) Generate() (string, error) {
+	r := randutil.NewReader(g.src)
+	defer r.Wipe()
+
+	// Choose the number of characters from each class.
+	labels := make([]byte, 0, g.length)
+	w, binom := new(big.Int), new(big.Int)
+	remaining := g.length
+	for c := range g.classes {
+		x, err := r.Int(g.ways[c][remaining])
+		if err != nil {
+			return "", err
+		}
+		k := g.classes[c].min
+		binom.Binomial(int64(remaining), int64(k))
+		for ; k < remaining; k++ {
+			g.weight(w, binom, c, remaining, k)
+			if x.Cmp(w) < 0 {
+				break
+			}
+			x.Sub(x, w)
+			// Advance binom from remaining choose k to remaining choose k+1.
+			binom.Mul(binom, w.SetInt64(int64(remaining-k)))
+			binom.Quo(binom, w.SetInt64(int64(k+1)))
+		}
+		for i := 0; i < k; i++ {
+			labels = append(labels, byte(c))
+		}
+		remaining -= k
+	}
+
+	// Arrange the classes uniformly, then pick characters within each class.
+	if err := r.Shuffle(len(labels), func(i, j int) { labels[i], labels[j] = labels[j], labels[i] }); err != nil {
+		clear(labels)
+		return "", err
+	}
+	out := make([]byte, g.length)
+	for i, c := range labels {
+		chars := g.classes[c].chars
+		j, err := r.Intn(len(chars))
+		if err != nil {
+			clear(labels)
+			clear(out)
+			return "", err
+		}
+		out[i] = chars[j]
+	}
+	clear(labels)
+	return string(out), nil
+}
+
+// log2 returns the base-2 logarithm of a positive big integer.
+func log2(n *big.Int) float64 {
+	f, _ := new(big.Float).SetInt(n).Float64()
+	if !math.IsInf(f, 0) {
+		return math.Log2(f)
+	}
+	// Beyond float64 range: scale down by the excess bits first.
+	shift := n.BitLen() - 1000
+	f, _ = new(big.Float).SetInt(new(big.Int).Rsh(n, uint(shift))).Float64()
+	return math.Log2(f) + float64(shift)
+}
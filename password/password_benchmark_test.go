@@ -0,0 +1,57 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package password
+
+import (
+	"strconv"
+	"testing"
+)
+
+// BenchmarkPassword_Lengths measures policy-constrained passwords across lengths.
+func BenchmarkPassword_Lengths(b *testing.B) {
+	for _, n := range []int{12, 16, 32, 64} {
+		b.Run("Length_"+strconv.Itoa(n), func(b *testing.B) {
+			g, err := New(WithLength(n), WithMinUpper(1), WithMinLower(1), WithMinDigits(1), WithMinSymbols(1))
+			if err != nil {
+				b.Fatalf("New failed: %v", err)
+			}
+			b.ReportAllocs()
+			for b.Loop() {
+				_, _ = g.Generate()
+			}
+		})
+	}
+}
+
+// BenchmarkPassword_Parallel measures a 16-character policy with RunParallel.
+func BenchmarkPassword_Parallel(b *testing.B) {
+	g, err := New(WithMinUpper(1), WithMinLower(1), WithMinDigits(1), WithMinSymbols(1))
+	if err != nil {
+		b.Fatalf("New failed: %v", err)
+	}
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = g.Generate()
+		}
+	})
+}
+
+// BenchmarkPassphrase measures six-word passphrases from a 7776-word list.
+func BenchmarkPassphrase(b *testing.B) {
+	words := make([]string, 7776)
+	for i := range words {
+		words[i] = "word" + strconv.Itoa(i)
+	}
+	p, err := NewPassphrase(words)
+	if err != nil {
+		b.Fatalf("NewPassphrase failed: %v", err)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		_, _ = p.Generate()
+	}
+}
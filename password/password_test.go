@@ -0,0 +1,263 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package password
+
+import (
+	"errors"
+	"math"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/sixafter/prng-chacha"
+	"github.com/stretchr/testify/assert"
+)
+
+// errReader always fails.
+type errReader struct{}
+
+var errRead = errors.New("read failed")
+
+func (errReader) Read([]byte) (int, error) { return 0, errRead }
+
+// countClass returns the number of characters of s that appear in set.
+func countClass(s, set string) int {
+	n := 0
+	for _, c := range s {
+		if strings.ContainsRune(set, c) {
+			n++
+		}
+	}
+	return n
+}
+
+// TestNew_Validation verifies that invalid policies are rejected.
+func TestNew_Validation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts []Option
+		err  error
+	}{
+		{"ZeroLength", []Option{WithLength(0)}, ErrLengthInvalid},
+		{"TooLong", []Option{WithLength(MaxLength + 1)}, ErrLengthInvalid},
+		{"NegativeMinimum", []Option{WithMinDigits(-1)}, ErrMinimumNegative},
+		{"MinimumsExceedLength", []Option{WithLength(4), WithMinUpper(2), WithMinDigits(3)}, ErrMinimumsExceedLength},
+		{"ClassExcludedAway", []Option{WithMinDigits(1), WithExclude(DefaultDigits)}, ErrClassEmpty},
+		{"ClassDisabled", []Option{WithMinSymbols(1), WithSymbols("")}, ErrClassEmpty},
+		{"Overlap", []Option{WithSymbols("!a")}, ErrClassesOverlap},
+		{"NotASCII", []Option{WithSymbols("§")}, ErrNotASCII},
+		{"NoCharacters", []Option{WithClasses("", "", "", "")}, ErrNoCharacters},
+		{"NilReader", []Option{WithReader(nil)}, ErrNilReader},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			g, err := New(tc.opts...)
+			assert.ErrorIs(t, err, tc.err)
+			assert.Nil(t, g)
+		})
+	}
+}
+
+// TestGenerate_Policy verifies that generated passwords satisfy the policy and avoid
+// excluded characters.
+func TestGenerate_Policy(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	g, err := New(
+		WithLength(12),
+		WithMinUpper(2),
+		WithMinLower(2),
+		WithMinDigits(3),
+		WithMinSymbols(1),
+		WithExcludeAmbiguous(),
+	)
+	is.NoError(err)
+	is.Equal(12, g.Length())
+
+	for i := 0; i < 2000; i++ {
+		pw, err := g.Generate()
+		is.NoError(err)
+		is.Len(pw, 12)
+		is.GreaterOrEqual(countClass(pw, DefaultUpper), 2)
+		is.GreaterOrEqual(countClass(pw, DefaultLower), 2)
+		is.GreaterOrEqual(countClass(pw, DefaultDigits), 3)
+		is.GreaterOrEqual(countClass(pw, DefaultSymbols), 1)
+		is.False(strings.ContainsAny(pw, Ambiguous), "password %q contains an ambiguous character", pw)
+	}
+}
+
+// TestGenerate_Uniform enumerates a small policy, checks that Count matches the number
+// of compliant strings, and applies a chi-squared test to generated passwords.
+func TestGenerate_Uniform(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	const upper, lower, digits = "AB", "ab", "01"
+	g, err := New(
+		WithLength(4),
+		WithClasses(upper, lower, digits, ""),
+		WithMinUpper(1),
+		WithMinDigits(1),
+		WithReader(prng.Reader),
+	)
+	is.NoError(err)
+
+	// Enumerate every string over the six characters and keep the compliant ones.
+	all := upper + lower + digits
+	compliant := make(map[string]int)
+	for i := 0; i < 6*6*6*6; i++ {
+		b := make([]byte, 4)
+		for j, v := 0, i; j < 4; j, v = j+1, v/6 {
+			b[j] = all[v%6]
+		}
+		s := string(b)
+		if countClass(s, upper) >= 1 && countClass(s, digits) >= 1 {
+			compliant[s] = 0
+		}
+	}
+	// 6^4 - 4^4 - 4^4 + 2^4 by inclusion-exclusion.
+	is.Len(compliant, 800)
+	is.Equal(int64(800), g.Count().Int64())
+	is.InDelta(math.Log2(800), g.EntropyBits(), 1e-9)
+
+	const draws = 80000
+	for i := 0; i < draws; i++ {
+		pw, err := g.Generate()
+		is.NoError(err)
+		_, ok := compliant[pw]
+		if !is.True(ok, "password %q does not satisfy the policy", pw) {
+			return
+		}
+		compliant[pw]++
+	}
+
+	expected := float64(draws) / float64(len(compliant))
+	var chi2 float64
+	for _, c := range compliant {
+		d := float64(c) - expected
+		chi2 += d * d / expected
+	}
+	// 799 degrees of freedom: mean 799, standard deviation about 40. The threshold is
+	// about 4 standard deviations above the mean.
+	is.Less(chi2, 960.0)
+}
+
+// TestNew_MaxLengthCost bounds the memory allocated to count the passwords of a
+// constrained policy at MaxLength. It is not parallel so that no other test allocates
+// during the measurement.
+func TestNew_MaxLengthCost(t *testing.T) {
+	is := assert.New(t)
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	g, err := New(WithLength(MaxLength), WithMinUpper(1), WithMinLower(1), WithMinDigits(3), WithMinSymbols(2))
+	runtime.ReadMemStats(&after)
+	is.NoError(err)
+	is.Less(after.TotalAlloc-before.TotalAlloc, uint64(64<<20), "New should allocate less than 64 MiB")
+
+	pw, err := g.Generate()
+	is.NoError(err)
+	is.Len(pw, MaxLength)
+}
+
+// TestEntropyBits verifies entropy for unconstrained policies, including one whose
+// count exceeds the float64 range.
+func TestEntropyBits(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	g, err := New()
+	is.NoError(err)
+	is.InDelta(16*math.Log2(94), g.EntropyBits(), 1e-9)
+
+	g, err = New(WithLength(MaxLength))
+	is.NoError(err)
+	is.InDelta(MaxLength*math.Log2(94), g.EntropyBits(), 1e-6)
+
+	pw, err := g.Generate()
+	is.NoError(err)
+	is.Len(pw, MaxLength)
+
+	// Requiring characters from a class removes passwords and therefore entropy.
+	c, err := New(WithMinSymbols(4))
+	is.NoError(err)
+	is.Less(c.EntropyBits(), 16*math.Log2(94))
+}
+
+// TestGenerate_ReaderError verifies that reader failures are returned.
+func TestGenerate_ReaderError(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	g, err := New(WithReader(errReader{}))
+	is.NoError(err)
+	pw, err := g.Generate()
+	is.ErrorIs(err, errRead)
+	is.Empty(pw)
+}
+
+// TestPassphrase verifies passphrase structure, validation and entropy.
+func TestPassphrase(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	words := []string{"correct", "horse", "battery", "staple", "orbit", "lantern", "maple", "quartz"}
+	p, err := NewPassphrase(words, WithWordCount(5), WithSeparator(" "))
+	is.NoError(err)
+	is.Equal(5, p.WordCount())
+	is.InDelta(15.0, p.EntropyBits(), 1e-9)
+
+	seen := make(map[string]int)
+	for i := 0; i < 4000; i++ {
+		s, err := p.Generate()
+		is.NoError(err)
+		parts := strings.Split(s, " ")
+		is.Len(parts, 5)
+		for _, w := range parts {
+			is.Contains(words, w)
+			seen[w]++
+		}
+	}
+	is.Len(seen, len(words))
+	for w, c := range seen {
+		is.InDelta(2500, c, 300, "word %q", w)
+	}
+
+	// The word list is copied.
+	words[0] = "mutated"
+	s, err := p.Generate()
+	is.NoError(err)
+	is.NotContains(s, "mutated")
+
+	_, err = NewPassphrase([]string{"one"})
+	is.ErrorIs(err, ErrWordListTooShort)
+	_, err = NewPassphrase([]string{"one", "two", "one"})
+	is.ErrorIs(err, ErrWordListDuplicate)
+	_, err = NewPassphrase([]string{"one", ""})
+	is.ErrorIs(err, ErrWordListDuplicate)
+	_, err = NewPassphrase(words, WithWordCount(0))
+	is.ErrorIs(err, ErrWordCountInvalid)
+	_, err = NewPassphrase(words, WithSeparator(""))
+	is.ErrorIs(err, ErrSeparatorInvalid, "an empty separator makes passphrases ambiguous")
+	_, err = NewPassphrase([]string{"one", "t-shirt"})
+	is.ErrorIs(err, ErrSeparatorInvalid, "a word containing the separator is ambiguous")
+	_, err = NewPassphrase([]string{"xa", "y", "x", "ay"}, WithSeparator("aa"))
+	is.ErrorIs(err, ErrSeparatorInvalid, "xa|aa|y and x|aa|ay are the same string")
+	_, err = NewPassphrase(words, WithSeparator(""), WithWordCount(1))
+	is.NoError(err, "a single word needs no separator")
+	_, err = NewPassphrase(words, WithReader(nil))
+	is.ErrorIs(err, ErrNilReader)
+
+	p, err = NewPassphrase(words, WithReader(errReader{}))
+	is.NoError(err)
+	_, err = p.Generate()
+	is.ErrorIs(err, errRead)
+}
@@ -11,10 +11,12 @@
 package prng
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/binary"
 	"fmt"
 	"io"
+	"log/slog"
 	mrand "math/rand/v2"
 	"runtime"
 	"sync"
@@ -25,18 +27,25 @@ import (
 )
 
 var (
-	ErrMaxBytesPerKeyZero        = fmt.Errorf("prng: MaxBytesPerKey must be greater than zero")
-	ErrMaxInitRetriesNegative    = fmt.Errorf("prng: MaxInitRetries cannot be negative")
-	ErrMaxRekeyAttemptsNegative  = fmt.Errorf("prng: MaxRekeyAttempts cannot be negative")
-	ErrDefaultBufferSizeNegative = fmt.Errorf("prng: DefaultBufferSize cannot be negative")
-	ErrRekeyBackoffNegative      = fmt.Errorf("prng: RekeyBackoff cannot be negative")
-	ErrMaxRekeyBackoffNegative   = fmt.Errorf("prng: MaxRekeyBackoff cannot be negative")
-	ErrMaxRekeyBackoffTooSmall   = fmt.Errorf("prng: MaxRekeyBackoff must be >= RekeyBackoff")
+	ErrMaxBytesPerKeyZero            = fmt.Errorf("prng: MaxBytesPerKey must be greater than zero")
+	ErrMaxInitRetriesNegative        = fmt.Errorf("prng: MaxInitRetries cannot be negative")
+	ErrMaxRekeyAttemptsNegative      = fmt.Errorf("prng: MaxRekeyAttempts cannot be negative")
+	ErrDefaultBufferSizeNegative     = fmt.Errorf("prng: DefaultBufferSize cannot be negative")
+	ErrRekeyBackoffNegative          = fmt.Errorf("prng: RekeyBackoff cannot be negative")
+	ErrMaxRekeyBackoffNegative       = fmt.Errorf("prng: MaxRekeyBackoff cannot be negative")
+	ErrMaxRekeyBackoffTooSmall       = fmt.Errorf("prng: MaxRekeyBackoff must be >= RekeyBackoff")
+	ErrShardsImmutable               = fmt.Errorf("prng: Shards cannot be changed by Update; construct a new reader")
+	ErrLatencySampleIntervalNegative = fmt.Errorf("prng: LatencySampleInterval cannot be negative")
 )
 
+// ErrRekeyFailed is wrapped by the error Read returns when Config.FailClosed is set and a
+// key reaching MaxBytesPerKey cannot be replaced within MaxRekeyAttempts.
+var ErrRekeyFailed = fmt.Errorf("prng: key rotation failed; refusing to generate output")
+
 // Reader is a global, cryptographically secure random source.
 // It is initialized at package load time and is safe for concurrent use.
 // If initialization fails (e.g., crypto/rand is unavailable), the package will panic.
+// Its first Read runs SelfTest; if the self-test fails, every Read returns its error.
 //
 // Example usage:
 //
@@ -58,22 +67,64 @@ var Reader io.Reader
 //
 // All methods are safe for concurrent use unless otherwise noted.
 //
-// The Config method allows callers to retrieve a copy of the immutable,
-// non-secret configuration associated with the PRNG instance. This enables
-// inspection of operational parameters—such as nonce, pool size, or reseed
-// interval—without exposing any sensitive key material or mutable internal state.
+// The Config method allows callers to retrieve a copy of the non-secret
+// configuration associated with the PRNG instance. This enables inspection of
+// operational parameters—such as nonce, pool size, or reseed interval—without
+// exposing any sensitive key material or mutable internal state.
+//
+// Readers returned by NewReader also implement Updater and StatsReporter, which
+// callers find with a type assertion.
 type Interface interface {
 	io.Reader
 
 	// Config returns a copy of the PRNG configuration in effect for this source.
 	//
-	// The returned Config contains only non-secret, immutable parameters and
-	// omits any runtime state or cryptographic keys. Callers may safely inspect
-	// the returned value to determine operational behavior without risk of
-	// secret exposure or race conditions.
+	// The returned Config contains only non-secret parameters and omits any
+	// runtime state or cryptographic keys. Callers may safely inspect the
+	// returned value to determine operational behavior without risk of secret
+	// exposure or race conditions.
 	Config() Config
 }
 
+// StatsReporter is implemented by sources that report cumulative runtime statistics.
+// Every Interface returned by NewReader, and the package-level Reader, implement it. It
+// is kept separate from Interface so that existing implementations of Interface remain
+// valid.
+//
+// Example:
+//
+//	if sr, ok := r.(prng.StatsReporter); ok {
+//	    fmt.Println(sr.Stats().BytesGenerated)
+//	}
+type StatsReporter interface {
+	// Stats returns a snapshot of the cumulative runtime statistics for this
+	// source, including per-shard counters. It is safe to call concurrently
+	// with Read and is intended for metrics exporters.
+	Stats() Stats
+}
+
+// Updater is implemented by sources whose configuration can be changed while they are
+// in use. Every Interface returned by NewReader, and the package-level Reader, implement
+// it. It is kept separate from Interface so that existing implementations of Interface
+// remain valid.
+//
+// Example:
+//
+//	if u, ok := r.(prng.Updater); ok {
+//	    if err := u.Update(prng.WithEnableKeyRotation(true)); err != nil {
+//	        // Handle error
+//	    }
+//	}
+type Updater interface {
+	// Update applies the given options to the configuration in effect for this
+	// source. The new configuration is validated as a whole and swapped in
+	// atomically; if validation fails, the current configuration is kept and
+	// the error is returned.
+	//
+	// See the implementation's documentation for when each field takes effect.
+	Update(opts ...Option) error
+}
+
 // init sets up the package‐level Reader by creating a new pooled PRNG instance.
 // It is invoked automatically at program startup (package initialization).
 // If NewReader fails (e.g., OS entropy unavailable), init will panic to prevent
@@ -85,25 +136,16 @@ type Interface interface {
 func init() {
 	cfg := DefaultConfig()
 
-	r := &reader{
-		config: &cfg,
-		pools:  make([]*sync.Pool, cfg.Shards),
-	}
-
+	r := newReader(&cfg)
 	for i := range r.pools {
-		cfg := cfg
 		r.pools[i] = &sync.Pool{
 			New: func() interface{} {
-				var (
-					p   *prng
-					err error
-				)
-				for attempt := 0; attempt < cfg.MaxInitRetries; attempt++ {
-					if p, err = newPRNG(&cfg, &r.keyRotations); err == nil {
-						return p
-					}
+				r.recordPoolMiss(i)
+				p, err := r.newInstance(i)
+				if err != nil {
+					panic(err.Error())
 				}
-				panic(fmt.Sprintf("prng pool init failed after %d retries: %v", cfg.MaxInitRetries, err))
+				return p
 			},
 		}
 
@@ -111,6 +153,10 @@ func init() {
 		r.pools[i].Put(item)
 	}
 
+	// Run the power-on self-test lazily, on the first Read, so that importing the
+	// package costs nothing and a failure surfaces as an error rather than a panic.
+	r.selfTest = sync.OnceValue(SelfTest)
+
 	Reader = r
 }
 
@@ -123,11 +169,30 @@ func init() {
 // each prng (including seeding and atomic cipher setup). This design
 // minimizes allocations and contention on crypto/rand while ensuring
 // each goroutine can obtain a fresh or recycled PRNG instance quickly.
+//
+// The active configuration is held in an atomic pointer shared with every
+// prng created by the pools, so Update can replace it without rebuilding
+// the pools. updateMu serializes concurrent calls to Update.
+//
+// Counters are kept per shard (stats[i] belongs to pools[i]) so that
+// concurrent reads on different shards do not contend on one cache line.
 type reader struct {
-	config         *Config
-	pools          []*sync.Pool
-	bytesGenerated atomic.Uint64
-	keyRotations   atomic.Uint64
+	config   atomic.Pointer[Config]
+	updateMu sync.Mutex
+	pools    []*sync.Pool
+	stats    []shardStats
+	latency  latencyTracker
+
+	// entropy overrides crypto/rand.Reader as the source of key and nonce
+	// material when non-nil. It is only set by tests.
+	entropy io.Reader
+
+	// health holds the continuous health test state used while HealthTests is enabled.
+	health healthTests
+
+	// selfTest, when non-nil, runs the power-on self-test once and returns its cached
+	// result. Only the global Reader sets it, deferring the test to its first Read.
+	selfTest func() error
 }
 
 // Stats represents cumulative runtime metrics for a PRNG reader instance.
@@ -136,9 +201,58 @@ type Stats struct {
 	// BytesGenerated is the total number of random bytes produced across all Read() calls.
 	BytesGenerated uint64
 
-	// KeyRotations is the total number of successful cipher rekey operations performed
+	// KeyRotations is the total number of new keys installed by successful rekey operations
 	// to maintain forward secrecy when the per-key output threshold is exceeded.
 	KeyRotations uint64
+
+	// RekeyFailures is the total number of failed rekey attempts. Each retry performed by
+	// a rekey counts separately.
+	RekeyFailures uint64
+
+	// Shards holds the per-shard breakdown of the totals above, indexed by shard.
+	Shards []ShardStats
+
+	// Latency holds the latency histograms and pool counters recorded while latency
+	// tracking is enabled. It is all zeros if tracking has never been enabled.
+	Latency LatencyStats
+}
+
+// ShardStats represents the cumulative runtime metrics of a single pool shard.
+type ShardStats struct {
+	// BytesGenerated is the number of random bytes produced by this shard.
+	BytesGenerated uint64
+
+	// KeyRotations is the number of new keys installed in this shard.
+	KeyRotations uint64
+
+	// RekeyFailures is the number of failed rekey attempts in this shard.
+	RekeyFailures uint64
+}
+
+// shardStats holds the live counters of a single shard.
+//
+// It is padded to a 64-byte cache line so that counters of neighboring shards,
+// which are updated concurrently, do not share a line. poolGets and poolMisses
+// are only maintained while latency tracking is enabled.
+type shardStats struct {
+	bytesGenerated atomic.Uint64
+	keyRotations   atomic.Uint64
+	rekeyFailures  atomic.Uint64
+	poolGets       atomic.Uint64
+	poolMisses     atomic.Uint64
+	_              [24]byte
+}
+
+// newReader allocates a reader for cfg with one pool slot and one counter set per shard.
+//
+// The pools are left nil; callers install a sync.Pool for each shard.
+func newReader(cfg *Config) *reader {
+	r := &reader{
+		pools: make([]*sync.Pool, cfg.Shards),
+		stats: make([]shardStats, cfg.Shards),
+	}
+	r.config.Store(cfg)
+	return r
 }
 
 // NewReader constructs and returns an io.Reader that produces cryptographically secure
@@ -173,26 +287,20 @@ func NewReader(opts ...Option) (Interface, error) {
 	}
 
 	// Validate configuration
-	if cfg.MaxBytesPerKey == 0 {
-		return nil, ErrMaxBytesPerKeyZero
-	}
-	if cfg.MaxInitRetries < 0 {
-		return nil, ErrMaxInitRetriesNegative
-	}
-	if cfg.MaxRekeyAttempts < 0 {
-		return nil, ErrMaxRekeyAttemptsNegative
-	}
-	if cfg.DefaultBufferSize < 0 {
-		return nil, ErrDefaultBufferSizeNegative
-	}
-	if cfg.RekeyBackoff < 0 {
-		return nil, ErrRekeyBackoffNegative
-	}
-	if cfg.MaxRekeyBackoff < 0 {
-		return nil, ErrMaxRekeyBackoffNegative
+	if err := validateConfig(&cfg); err != nil {
+		return nil, err
 	}
-	if cfg.MaxRekeyBackoff > 0 && cfg.MaxRekeyBackoff < cfg.RekeyBackoff {
-		return nil, ErrMaxRekeyBackoffTooSmall
+
+	// Verify the cipher and output paths before producing any output, if requested.
+	if cfg.SelfTest {
+		if err := SelfTest(); err != nil {
+			if cfg.Logger != nil {
+				cfg.Logger.LogAttrs(context.Background(), slog.LevelError, "prng: self-test failed",
+					slog.Any("error", err),
+				)
+			}
+			return nil, err
+		}
 	}
 
 	// If n <= 0, the number of shards defaults to runtime.GOMAXPROCS(0),
@@ -206,44 +314,45 @@ func NewReader(opts ...Option) (Interface, error) {
 	// The pool's New function attempts to construct a new *prng,
 	// retrying up to cfg.MaxInitRetries times in case of failure (e.g., low entropy).
 	// If all attempts fail, the function returns nil, which is caught during eager initialization below.
-	r := &reader{
-		config: &cfg,
-		pools:  make([]*sync.Pool, cfg.Shards),
-	}
+	r := newReader(&cfg)
 	for i := range r.pools {
-		cfg := cfg // Capture the current configuration for this shard
 		r.pools[i] = &sync.Pool{
 			New: func() interface{} {
-				var (
-					p   *prng
-					err error
-				)
-				for attempts := 0; attempts < cfg.MaxInitRetries; attempts++ {
-					if p, err = newPRNG(&cfg, &r.keyRotations); err == nil {
-						return p
-					}
+				r.recordPoolMiss(i)
+				p, err := r.newInstance(i)
+				if err != nil {
+					// If initialization fails after all retries, return nil instead of panicking.
+					// The eager initialization step below will detect and return this as an error.
+					return nil
 				}
-				// If initialization fails after all retries, return nil instead of panicking.
-				// The eager initialization step below will detect and return this as an error.
-				return nil
+				return p
 			},
 		}
 
 		// Eagerly test the pool initialization to ensure that any catastrophic
 		// failure is caught immediately, not deferred to the first use.
-		// This triggers pool.New, which may return nil on failure. Any nil value is converted to an error.
-		var err error
-		item := r.pools[i].Get()
-		if item == nil {
-			err = fmt.Errorf("prng pool initialization failed after %d retries", cfg.MaxInitRetries)
-		} else {
-			r.pools[i].Put(item)
-		}
-
-		// If initialization failed, return it as an error.
+		// The first instance is created directly so the underlying failure can be returned.
+		item, err := r.newInstance(i)
 		if err != nil {
+			if cfg.Logger != nil {
+				cfg.Logger.LogAttrs(context.Background(), slog.LevelError, "prng: reader initialization failed",
+					slog.Int("shard", i),
+					slog.Int("shards", cfg.Shards),
+					slog.Any("error", err),
+				)
+			}
 			return nil, err
 		}
+		r.pools[i].Put(item)
+	}
+
+	if cfg.Logger != nil {
+		cfg.Logger.LogAttrs(context.Background(), slog.LevelDebug, "prng: reader initialized",
+			slog.Int("shards", cfg.Shards),
+			slog.Bool("key_rotation", cfg.EnableKeyRotation),
+			slog.Uint64("max_bytes_per_key", cfg.MaxBytesPerKey),
+			slog.Bool("zero_buffer", cfg.UseZeroBuffer),
+		)
 	}
 
 	// Return a new reader that wraps the initialized pool. This is safe for concurrent use.
@@ -252,11 +361,148 @@ func NewReader(opts ...Option) (Interface, error) {
 
 // Config returns a copy of the PRNG's configuration settings.
 //
-// The returned configuration describes the PRNG’s static parameters as set during initialization.
-// No secret values, seeds, or internal state are included. The returned Config is a safe copy
-// for inspection, logging, or diagnostics and cannot be used to alter the PRNG’s behavior.
+// The returned configuration describes the PRNG’s parameters as set during initialization and
+// by any subsequent call to Update. No secret values, seeds, or internal state are included.
+// The returned Config is a safe copy for inspection, logging, or diagnostics and cannot be used
+// to alter the PRNG’s behavior.
 func (r *reader) Config() Config {
-	return *r.config
+	return *r.config.Load()
+}
+
+// Update applies the given options to a copy of the reader's current configuration, validates
+// the result, and atomically replaces the active configuration. Pools and existing PRNG
+// instances are kept; no cipher is rebuilt.
+//
+// Changes take effect as follows:
+//   - MaxBytesPerKey, EnableKeyRotation, FailClosed, UseZeroBuffer: on the next Read.
+//   - HealthTests: on the next reseed.
+//   - MaxRekeyAttempts, RekeyBackoff, MaxRekeyBackoff: on the next rekey that starts; a rekey
+//     already in progress finishes with the settings it started with.
+//   - DefaultBufferSize, MaxInitRetries: for PRNG instances created by the pools afterwards.
+//   - Shards: cannot be changed, because the pools are fixed at construction. Any change
+//     returns ErrShardsImmutable; construct a new reader instead.
+//
+// On error the active configuration is left unchanged. Update is safe for concurrent use with
+// Read and with other calls to Update.
+//
+// Example:
+//
+//	r, _ := prng.NewReader()
+//	u := r.(prng.Updater)
+//	if err := u.Update(prng.WithEnableKeyRotation(true), prng.WithMaxBytesPerKey(1<<20)); err != nil {
+//	    // handle error
+//	}
+func (r *reader) Update(opts ...Option) error {
+	r.updateMu.Lock()
+	defer r.updateMu.Unlock()
+
+	current := r.config.Load()
+	cfg := *current
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if err := validateConfig(&cfg); err != nil {
+		return err
+	}
+	if cfg.Shards != current.Shards {
+		return ErrShardsImmutable
+	}
+
+	r.config.Store(&cfg)
+	return nil
+}
+
+// newInstance creates a prng for the given shard, retrying up to MaxInitRetries times.
+//
+// The configuration is loaded on every call so that instances created after an Update
+// observe the new settings. The configured Observer, if any, is notified of the outcome.
+// If every attempt fails (or no attempt is allowed), the returned error wraps the last
+// underlying failure.
+func (r *reader) newInstance(shard int) (*prng, error) {
+	var (
+		p   *prng
+		err error
+	)
+	cfg := r.config.Load()
+	for attempt := 0; attempt < cfg.MaxInitRetries; attempt++ {
+		if p, err = newPRNG(r, shard); err == nil {
+			if cfg.Observer != nil {
+				cfg.Observer.OnInstanceCreated(shard)
+			}
+			if cfg.Logger != nil {
+				cfg.Logger.LogAttrs(context.Background(), slog.LevelDebug, "prng: instance created",
+					slog.Int("shard", shard),
+					slog.Int("attempt", attempt+1),
+				)
+			}
+			return p, nil
+		}
+
+		if cfg.Logger != nil {
+			cfg.Logger.LogAttrs(context.Background(), slog.LevelWarn, "prng: instance initialization attempt failed",
+				slog.Int("shard", shard),
+				slog.Int("attempt", attempt+1),
+				slog.Int("max_attempts", cfg.MaxInitRetries),
+				slog.Any("error", err),
+			)
+		}
+	}
+
+	if err == nil {
+		err = fmt.Errorf("prng pool init failed after %d retries", cfg.MaxInitRetries)
+	} else {
+		err = fmt.Errorf("prng pool init failed after %d retries: %w", cfg.MaxInitRetries, err)
+	}
+	if cfg.Observer != nil {
+		cfg.Observer.OnInitFailed(shard, err)
+	}
+	if cfg.Logger != nil {
+		cfg.Logger.LogAttrs(context.Background(), slog.LevelError, "prng: instance initialization failed",
+			slog.Int("shard", shard),
+			slog.Int("max_attempts", cfg.MaxInitRetries),
+			slog.Any("error", err),
+		)
+	}
+	return nil, err
+}
+
+// recordPoolMiss counts a PRNG instance created by the pool of the given shard because
+// the pool was empty. It is only recorded while latency tracking is enabled.
+func (r *reader) recordPoolMiss(shard int) {
+	if r.config.Load().LatencySampleInterval > 0 {
+		r.stats[shard].poolMisses.Add(1)
+	}
+}
+
+// validateConfig checks cfg for invalid or inconsistent values and returns the
+// corresponding error, or nil if the configuration can be used.
+func validateConfig(cfg *Config) error {
+	if cfg.MaxBytesPerKey == 0 {
+		return ErrMaxBytesPerKeyZero
+	}
+	if cfg.MaxInitRetries < 0 {
+		return ErrMaxInitRetriesNegative
+	}
+	if cfg.MaxRekeyAttempts < 0 {
+		return ErrMaxRekeyAttemptsNegative
+	}
+	if cfg.DefaultBufferSize < 0 {
+		return ErrDefaultBufferSizeNegative
+	}
+	if cfg.RekeyBackoff < 0 {
+		return ErrRekeyBackoffNegative
+	}
+	if cfg.MaxRekeyBackoff < 0 {
+		return ErrMaxRekeyBackoffNegative
+	}
+	if cfg.MaxRekeyBackoff > 0 && cfg.MaxRekeyBackoff < cfg.RekeyBackoff {
+		return ErrMaxRekeyBackoffTooSmall
+	}
+	if cfg.LatencySampleInterval < 0 {
+		return ErrLatencySampleIntervalNegative
+	}
+	return nil
 }
 
 // shardIndex selects a pseudo-random shard index in the range [0, n) using
@@ -294,6 +540,13 @@ func (r *reader) Read(buf []byte) (int, error) {
 		return 0, nil
 	}
 
+	// The global Reader runs the power-on self-test on first use; every Read fails if it did.
+	if r.selfTest != nil {
+		if err := r.selfTest(); err != nil {
+			return 0, err
+		}
+	}
+
 	// Determine the shard index based on the number of pools available.
 	n := len(r.pools)
 	shard := 0
@@ -301,6 +554,18 @@ func (r *reader) Read(buf []byte) (int, error) {
 		shard = shardIndex(n)
 	}
 
+	// When latency tracking is enabled, count the pool access and time a sample of reads.
+	var (
+		start time.Time
+		timed bool
+	)
+	if interval := r.config.Load().LatencySampleInterval; interval > 0 {
+		r.stats[shard].poolGets.Add(1)
+		if timed = sampled(interval); timed {
+			start = time.Now()
+		}
+	}
+
 	// Acquire a PRNG instance from the pool for exclusive use by this call.
 	// This provides thread safety and isolation of cryptographic state.
 	p := r.pools[shard].Get().(*prng)
@@ -310,9 +575,12 @@ func (r *reader) Read(buf []byte) (int, error) {
 	defer r.pools[shard].Put(p)
 
 	// Delegate the actual generation of random bytes to the PRNG instance's Read method.
+	// A fail-closed instance may return a partial result with its error; those bytes were
+	// still generated.
 	n, err := p.Read(buf)
-	if err == nil {
-		r.bytesGenerated.Add(uint64(n))
+	r.stats[shard].bytesGenerated.Add(uint64(n))
+	if err == nil && timed {
+		r.latency.read[readSizeClass(n)].observe(time.Since(start))
 	}
 
 	return n, err
@@ -325,17 +593,24 @@ func (r *reader) Read(buf []byte) (int, error) {
 // scratch buffer for encryption, and internal counters to enforce a
 // “forward secrecy” rekey after a configurable output threshold.
 type prng struct {
-	// config holds a pointer to this PRNG instance’s configuration parameters.
-	// It provides tunable settings such as MaxBytesPerKey (keystream rotation threshold)
-	// and MaxInitRetries (how many times to retry initialization).
-	config *Config
+	// owner is the reader whose pools created this instance. It provides the
+	// active configuration (loaded atomically on each use so that Update takes
+	// effect without rebuilding instances) and the reader-level counters.
+	owner *reader
 
-	rotationCounter *atomic.Uint64
+	// shard is the index of the pool this instance belongs to. It is reported
+	// to the Observer and never changes after construction.
+	shard int
 
 	// cipher holds the active *chacha20.Cipher. We use atomic.Value so that
 	// loads and stores of the cipher pointer are safe and nonblocking.
 	cipher atomic.Value
 
+	// pending holds a freshly keyed cipher prepared by asyncRekey. The goroutine
+	// that owns this instance installs it at the start of its next Read, so the
+	// old cipher is only ever wiped by the goroutine that was using it.
+	pending atomic.Pointer[chacha20.Cipher]
+
 	// zero is a one‐off buffer of zeros used as plaintext for XORKeyStream.
 	// We grow it as needed; since each prng is single‐goroutine‐owned from the pool,
 	// no synchronization around this slice is required.
@@ -348,6 +623,7 @@ type prng struct {
 
 	// rekeying is a 0/1 flag (set via atomic CAS) to ensure only one
 	// background goroutine at a time performs the expensive rekey operation.
+	// It stays set until the prepared cipher is installed or the rekey gives up.
 	rekeying uint32
 }
 
@@ -358,30 +634,29 @@ func (p *prng) Read(buf []byte) (int, error) {
 		return 0, nil
 	}
 
-	// Atomically retrieve the active cipher stream.
-	stream := p.cipher.Load().(*chacha20.Cipher)
-
-	// Generate random output based on configuration.
-	if p.config.UseZeroBuffer {
-		// Ensure internal zero buffer is at least n bytes.
-		if cap(p.zero) < n {
-			p.zero = make([]byte, n)
-		} else {
-			p.zero = p.zero[:n]
+	// Install a cipher prepared by a completed asynchronous rekey, if any. A rekey is
+	// rarely pending, so check with a load before paying for the exchange.
+	if p.pending.Load() != nil {
+		if next := p.pending.Swap(nil); next != nil {
+			p.install(next)
 		}
-		// XOR the zero buffer into b, producing random bytes.
-		stream.XORKeyStream(buf, p.zero)
-	} else {
-		// XOR the buffer into itself (in-place), producing random bytes.
-		stream.XORKeyStream(buf, buf)
 	}
 
+	// Atomically retrieve the active configuration.
+	cfg := p.owner.config.Load()
+	if cfg.EnableKeyRotation && cfg.FailClosed {
+		return p.readFailClosed(buf, cfg)
+	}
+
+	// Generate random output based on configuration.
+	p.generate(buf, cfg)
+
 	// Optionally, track key usage and trigger rekeying.
-	if p.config.EnableKeyRotation {
+	if cfg.EnableKeyRotation {
 		// Atomically increment usage counter by n bytes.
 		atomic.AddUint64(&p.usage, uint64(n))
 		// If usage exceeds threshold, attempt async rekey.
-		if atomic.LoadUint64(&p.usage) > p.config.MaxBytesPerKey {
+		if atomic.LoadUint64(&p.usage) > cfg.MaxBytesPerKey {
 			if atomic.CompareAndSwapUint32(&p.rekeying, 0, 1) {
 				go p.asyncRekey()
 			}
@@ -391,13 +666,123 @@ func (p *prng) Read(buf []byte) (int, error) {
 	return n, nil
 }
 
+// readFailClosed fills buf without ever emitting more than MaxBytesPerKey bytes under
+// one key. When the current key is exhausted it installs a pending cipher or rekeys
+// synchronously; if every rekey attempt fails it returns the bytes generated so far and
+// an error wrapping ErrRekeyFailed, and the next Read tries again.
+func (p *prng) readFailClosed(buf []byte, cfg *Config) (int, error) {
+	n := 0
+	for n < len(buf) {
+		usage := atomic.LoadUint64(&p.usage)
+		if usage >= cfg.MaxBytesPerKey {
+			// Always make at least one attempt, so Read never fails without trying.
+			next, err := p.rekey(cfg, max(cfg.MaxRekeyAttempts, 1))
+			if err != nil {
+				if cfg.Logger != nil {
+					cfg.Logger.LogAttrs(context.Background(), slog.LevelError, "prng: rekey failed; refusing output",
+						slog.Int("shard", p.shard),
+						slog.Int("attempts", max(cfg.MaxRekeyAttempts, 1)),
+						slog.Uint64("bytes_since_last_key", usage),
+						slog.Any("error", err),
+					)
+				}
+				return n, fmt.Errorf("%w: %w", ErrRekeyFailed, err)
+			}
+			p.install(next)
+			usage = 0
+		}
+
+		chunk := buf[n:]
+		if remaining := cfg.MaxBytesPerKey - usage; uint64(len(chunk)) > remaining {
+			chunk = chunk[:remaining]
+		}
+		p.generate(chunk, cfg)
+		atomic.AddUint64(&p.usage, uint64(len(chunk)))
+		n += len(chunk)
+	}
+	return n, nil
+}
+
+// generate fills buf with keystream from the active cipher.
+func (p *prng) generate(buf []byte, cfg *Config) {
+	stream := p.cipher.Load().(*chacha20.Cipher)
+	if cfg.UseZeroBuffer {
+		// Ensure internal zero buffer is at least len(buf) bytes.
+		if cap(p.zero) < len(buf) {
+			p.zero = make([]byte, len(buf))
+		} else {
+			p.zero = p.zero[:len(buf)]
+		}
+		// XOR the zero buffer into buf, producing random bytes.
+		stream.XORKeyStream(buf, p.zero)
+	} else {
+		// XOR the buffer into itself (in-place), producing random bytes.
+		stream.XORKeyStream(buf, buf)
+	}
+}
+
+// install replaces the active cipher with next, resets the per-key usage counter,
+// wipes the previous cipher state, and clears the rekeying flag.
+//
+// It must only be called by the goroutine that currently owns the instance, which
+// guarantees no concurrent XORKeyStream is running on the old cipher when it is wiped.
+//
+// The rotation is counted and reported here rather than when the cipher is prepared, so
+// that a prepared cipher discarded with its instance (for example when sync.Pool drops
+// it) is never reported as a rotation.
+func (p *prng) install(next *chacha20.Cipher) {
+	old := p.cipher.Load().(*chacha20.Cipher)
+	p.cipher.Store(next)
+
+	// Reset usage count for new key/nonce.
+	usage := atomic.SwapUint64(&p.usage, 0)
+
+	// Wipe the memory of the old cipher (zero out struct fields).
+	*old = chacha20.Cipher{}
+
+	// Increment the shard's rotation counter and notify.
+	p.owner.stats[p.shard].keyRotations.Add(1)
+	cfg := p.owner.config.Load()
+	if cfg.Observer != nil {
+		cfg.Observer.OnKeyRotated(p.shard)
+	}
+	if cfg.Logger != nil {
+		cfg.Logger.LogAttrs(context.Background(), slog.LevelDebug, "prng: key rotated",
+			slog.Int("shard", p.shard),
+			slog.Uint64("bytes_since_last_key", usage),
+		)
+	}
+
+	// Allow the next rekey to be scheduled.
+	atomic.StoreUint32(&p.rekeying, 0)
+}
+
 // Stats returns runtime statistics about this PRNG instance, including
-// total bytes generated and key rotations performed.
+// total bytes generated, key rotations performed and failed rekey attempts,
+// both in total and per shard.
+//
+// Counters are read individually, so a snapshot taken during concurrent reads
+// may mix values from slightly different instants; each counter is monotonic.
 func (r *reader) Stats() Stats {
-	return Stats{
-		BytesGenerated: r.bytesGenerated.Load(),
-		KeyRotations:   r.keyRotations.Load(),
+	s := Stats{
+		Shards:  make([]ShardStats, len(r.stats)),
+		Latency: r.latency.snapshot(),
+	}
+	for i := range r.stats {
+		s.Latency.PoolGets += r.stats[i].poolGets.Load()
+		s.Latency.PoolMisses += r.stats[i].poolMisses.Load()
+
+		shard := ShardStats{
+			BytesGenerated: r.stats[i].bytesGenerated.Load(),
+			KeyRotations:   r.stats[i].keyRotations.Load(),
+			RekeyFailures:  r.stats[i].rekeyFailures.Load(),
+		}
+		s.Shards[i] = shard
+		s.BytesGenerated += shard.BytesGenerated
+		s.KeyRotations += shard.KeyRotations
+		s.RekeyFailures += shard.RekeyFailures
 	}
+	return s
 }
 
 // newPRNG creates and returns a fully initialized prng instance.
@@ -408,14 +793,18 @@ func (r *reader) Stats() Stats {
 // Returns an error if cipher setup fails.
 //
 // Parameters:
-//   - config: Pointer to the PRNG configuration. Must not be nil.
+//   - owner: The reader that owns the instance and holds its configuration. Must not be nil
+//     and must have a configuration stored.
+//   - shard: The index of the pool the instance belongs to.
 //
 // Returns:
 //   - *prng: A new PRNG instance ready for random output.
 //   - error: A non-nil error if cipher construction fails.
-func newPRNG(config *Config, rotationCounter *atomic.Uint64) (*prng, error) {
+func newPRNG(owner *reader, shard int) (*prng, error) {
+	config := owner.config.Load()
+
 	// Generate a fresh a new cipher seeded with a secure random key and nonce.
-	stream, err := newCipher()
+	stream, err := newCipher(owner.entropySource())
 	if err != nil {
 		// If cipher construction fails, propagate the error to caller.
 		return nil, err
@@ -432,9 +821,9 @@ func newPRNG(config *Config, rotationCounter *atomic.Uint64) (*prng, error) {
 
 	// Initialize the PRNG instance with the selected configuration and zero buffer.
 	p := &prng{
-		zero:            zero,
-		config:          config,
-		rotationCounter: rotationCounter,
+		zero:  zero,
+		owner: owner,
+		shard: shard,
 	}
 
 	// Store the cipher stream atomically for lock-free, concurrent access in Read().
@@ -444,29 +833,43 @@ func newPRNG(config *Config, rotationCounter *atomic.Uint64) (*prng, error) {
 	return p, nil
 }
 
+// entropySource returns the reader used to seed new ciphers: crypto/rand.Reader unless the
+// reader was constructed with an alternative source (used by tests to simulate failures),
+// passed through the continuous health tests when HealthTests is enabled.
+func (r *reader) entropySource() io.Reader {
+	var src io.Reader = rand.Reader
+	if r.entropy != nil {
+		src = r.entropy
+	}
+	if r.config.Load().HealthTests {
+		return &healthReader{src: src, tests: &r.health}
+	}
+	return src
+}
+
 // newCipher generates and returns a new *chacha20.Cipher seeded with a cryptographically secure
-// random key and nonce.
+// random key and nonce read from src.
 //
 // The function performs the following steps:
 //  1. Allocates fresh buffers for the key and nonce of the correct size.
-//  2. Fills both buffers with cryptographically secure random bytes from crypto/rand.Reader.
+//  2. Fills both buffers with cryptographically secure random bytes from src.
 //  3. Constructs a new stream cipher instance using the generated key and nonce.
 //  4. Immediately overwrites (zeroes) the key and nonce buffers in memory to prevent any
 //     sensitive seed material from lingering in process memory.
 //  5. If any step fails (entropy acquisition or cipher construction), returns an error with context.
 //     On success, returns the initialized cipher stream.
-func newCipher() (*chacha20.Cipher, error) {
+func newCipher(src io.Reader) (*chacha20.Cipher, error) {
 	// Step 1: Allocate key and nonce buffers according to ChaCha20 specification.
 	key := make([]byte, chacha20.KeySize)
 	nonce := make([]byte, chacha20.NonceSizeX)
 
 	// Step 2: Fill the key buffer with cryptographically secure random bytes.
-	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+	if _, err := io.ReadFull(src, key); err != nil {
 		return nil, fmt.Errorf("newCipher: failed to read key: %w", err)
 	}
 
 	// Step 3: Fill the nonce buffer with cryptographically secure random bytes.
-	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+	if _, err := io.ReadFull(src, nonce); err != nil {
 		return nil, fmt.Errorf("newCipher: failed to read nonce: %w", err)
 	}
 
@@ -494,63 +897,127 @@ func newCipher() (*chacha20.Cipher, error) {
 // goroutine, and attempts to rekey the PRNG up to Config.MaxRekeyAttempts times, doubling the
 // backoff after each failure (jittered by a random value for each attempt).
 //
-// On each attempt, the function captures the current cipher pointer so that it can zero out the
-// old cipher (removing key/counter material) after a successful rekey. If all attempts fail,
-// the function leaves the existing cipher in place and simply exits. The rekeying flag is always
-// cleared before returning to allow future rekey attempts.
+// On success, the new cipher is handed to the owning goroutine through the pending slot rather
+// than swapped in directly: the owner may be in the middle of a Read on the old cipher, so only
+// the owner can safely install the new cipher and zero out the old one (see install). If all
+// attempts fail, the function leaves the existing cipher in place and clears the rekeying flag
+// to allow future rekey attempts.
 func (p *prng) asyncRekey() {
-	// Always clear the rekeying flag when this goroutine exits, so rekey can be attempted again.
-	defer atomic.StoreUint32(&p.rekeying, 0)
+	// Snapshot the configuration so a concurrent Update cannot change the
+	// retry policy midway through this rekey.
+	cfg := p.owner.config.Load()
+
+	stream, err := p.rekey(cfg, cfg.MaxRekeyAttempts)
+	if stream != nil {
+		// Hand the new cipher to the owner; it is installed, counted and
+		// reported on the next Read.
+		p.pending.Store(stream)
+		return
+	}
 
-	// Start with the configured base backoff duration.
-	base := p.config.RekeyBackoff
+	// All attempts to rekey failed; keep the existing cipher in place and
+	// clear the rekeying flag so rekey can be attempted again.
+	if cfg.Logger != nil {
+		cfg.Logger.LogAttrs(context.Background(), slog.LevelError, "prng: rekey failed; keeping current key",
+			slog.Int("shard", p.shard),
+			slog.Int("attempts", cfg.MaxRekeyAttempts),
+			slog.Uint64("bytes_since_last_key", atomic.LoadUint64(&p.usage)),
+			slog.Any("error", err),
+		)
+	}
+	atomic.StoreUint32(&p.rekeying, 0)
+}
 
-	// Track the previous cipher pointer for secure wiping after rotation.
-	var old *chacha20.Cipher
+// rekey creates a new cipher, making up to attempts attempts and doubling the backoff
+// after each failure (jittered by a random value for each attempt). Each failure is
+// counted and reported to the Observer. It returns a nil cipher and the error of the last
+// attempt if none succeeds.
+func (p *prng) rekey(cfg *Config, attempts int) (*chacha20.Cipher, error) {
+	// Start with the configured base backoff duration (with fallback to default).
+	base := cfg.RekeyBackoff
+	if base <= 0 {
+		base = rekeyBackoff // Use library default if unset.
+	}
 
 	// Determine the maximum allowed backoff (with fallback to default).
-	maxBackoff := p.config.MaxRekeyBackoff
+	maxBackoff := cfg.MaxRekeyBackoff
 	if maxBackoff == 0 {
 		maxBackoff = maxRekeyBackoff // Use library default if unset.
 	}
 
-	for i := 0; i < p.config.MaxRekeyAttempts; i++ {
-		// Capture the currently active cipher pointer for later zeroization.
-		old = p.cipher.Load().(*chacha20.Cipher)
+	// Bytes emitted under the current key when the rekey started; reported in diagnostics.
+	usage := atomic.LoadUint64(&p.usage)
+
+	// Time the rekey when latency tracking is enabled.
+	tracked := cfg.LatencySampleInterval > 0
+	var start time.Time
+	if tracked {
+		start = time.Now()
+	}
+	if cfg.Logger != nil {
+		cfg.Logger.LogAttrs(context.Background(), slog.LevelDebug, "prng: rekey started",
+			slog.Int("shard", p.shard),
+			slog.Uint64("bytes_since_last_key", usage),
+			slog.Uint64("max_bytes_per_key", cfg.MaxBytesPerKey),
+		)
+	}
 
+	var lastErr error
+	for i := 0; i < attempts; i++ {
 		// Attempt to create a new ChaCha20 cipher (with a new key and nonce).
-		stream, err := newCipher()
+		stream, err := newCipher(p.owner.entropySource())
 		if err == nil {
-			// Store the new cipher atomically.
-			p.cipher.Store(stream)
-
-			// Reset usage count for new key/nonce.
-			atomic.StoreUint64(&p.usage, 0)
-
-			// Increment reader-level counter
-			if p.rotationCounter != nil {
-				p.rotationCounter.Add(1)
+			if tracked {
+				p.owner.latency.rekey.observe(time.Since(start))
 			}
+			if cfg.Logger != nil {
+				cfg.Logger.LogAttrs(context.Background(), slog.LevelDebug, "prng: rekey prepared",
+					slog.Int("shard", p.shard),
+					slog.Int("attempt", i+1),
+				)
+			}
+			return stream, nil
+		}
+		lastErr = err
+		p.owner.stats[p.shard].rekeyFailures.Add(1)
 
-			// Wipe the memory of the old cipher (zero out struct fields).
-			*old = chacha20.Cipher{}
-
-			// Rekey successful; exit the function.
-			return
+		if cfg.Observer != nil {
+			cfg.Observer.OnRekeyFailed(p.shard, err, i+1)
 		}
 
 		// If cipher initialization failed, jitter the retry delay by a random amount.
-		var b [8]byte
-		if _, err = rand.Read(b[:]); err == nil {
-			// Interpret b as a big-endian uint64 for jitter.
-			rnd := binary.BigEndian.Uint64(b[:])
-
-			// Calculate delay: base + (rnd mod base) for randomness.
-			delay := base + time.Duration(rnd%uint64(base))
-			time.Sleep(delay)
-		} else {
-			// If reading random bytes fails, fall back to fixed backoff.
-			time.Sleep(base)
+		// If reading random bytes fails, fall back to fixed backoff. No retry follows
+		// the last attempt, so it has no delay; a fail-closed Read returns at once.
+		last := i == attempts-1
+		var delay time.Duration
+		if !last {
+			delay = base
+			var b [8]byte
+			if _, jerr := rand.Read(b[:]); jerr == nil {
+				// Interpret b as a big-endian uint64 for jitter.
+				rnd := binary.BigEndian.Uint64(b[:])
+
+				// Calculate delay: base + (rnd mod base) for randomness.
+				delay = base + time.Duration(rnd%uint64(base))
+			}
+		}
+
+		if cfg.Logger != nil {
+			cfg.Logger.LogAttrs(context.Background(), slog.LevelWarn, "prng: rekey attempt failed",
+				slog.Int("shard", p.shard),
+				slog.Int("attempt", i+1),
+				slog.Int("max_attempts", attempts),
+				slog.Duration("backoff", delay),
+				slog.Uint64("bytes_since_last_key", usage),
+				slog.Any("error", err),
+			)
+		}
+		if last {
+			break
+		}
+		time.Sleep(delay)
+		if tracked {
+			p.owner.latency.rekeyBackoff.Add(uint64(delay))
 		}
 
 		// Exponentially backoff for the next retry, up to the maximum allowed.
@@ -560,5 +1027,5 @@ func (p *prng) asyncRekey() {
 		}
 	}
 
-	// All attempts to rekey failed; function exits with existing cipher in place.
+	return nil, lastErr
 }
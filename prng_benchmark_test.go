@@ -202,3 +202,68 @@ func BenchmarkPRNG_ReadExtremeSizes(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkPRNG_Profiles(b *testing.B) {
+	profiles := []struct {
+		name string
+		opt  Option
+	}{
+		{"Default", func(*Config) {}},
+		{"Throughput", ProfileThroughput()},
+		{"LowMemory", ProfileLowMemory()},
+		{"Paranoid", ProfileParanoid()},
+	}
+	bufferSizes := []int{32, 4096}
+	goroutineCounts := []int{1, 16}
+	for _, p := range profiles {
+		for _, size := range bufferSizes {
+			for _, gc := range goroutineCounts {
+				p, size, gc := p, size, gc
+				b.Run(fmt.Sprintf("%s_%dBytes_%dGoroutines", p.name, size, gc), func(b *testing.B) {
+					rdr, err := NewReader(p.opt)
+					if err != nil {
+						b.Fatalf("NewReader failed: %v", err)
+					}
+					b.SetParallelism(gc)
+					b.ReportAllocs()
+					b.ResetTimer()
+					b.RunParallel(func(pb *testing.PB) {
+						buffer := make([]byte, size)
+						for pb.Next() {
+							if _, err := rdr.Read(buffer); err != nil {
+								b.Fatalf("Read failed: %v", err)
+							}
+						}
+					})
+				})
+			}
+		}
+	}
+}
+
+func BenchmarkPRNG_LatencyTracking(b *testing.B) {
+	intervals := []int{0, 1, 64}
+	goroutineCounts := []int{1, 16}
+	for _, interval := range intervals {
+		for _, gc := range goroutineCounts {
+			interval, gc := interval, gc
+			b.Run(fmt.Sprintf("Interval_%d_%dGoroutines", interval, gc), func(b *testing.B) {
+				rdr, err := NewReader(WithLatencyTracking(interval))
+				if err != nil {
+					b.Fatalf("NewReader failed: %v", err)
+				}
+				b.SetParallelism(gc)
+				b.ReportAllocs()
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					buffer := make([]byte, 32)
+					for pb.Next() {
+						if _, err := rdr.Read(buffer); err != nil {
+							b.Fatalf("Read failed: %v", err)
+						}
+					}
+				})
+			})
+		}
+	}
+}
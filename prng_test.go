@@ -7,6 +7,7 @@ package prng
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"sync"
@@ -300,7 +301,8 @@ func Test_PRNG_RekeyMechanism(t *testing.T) {
 	cfg.EnableKeyRotation = true
 	cfg.MaxRekeyAttempts = 3
 
-	p, err := newPRNG(&cfg, &atomic.Uint64{})
+	r := newReader(&cfg)
+	p, err := newPRNG(r, 0)
 	is.NoError(err)
 
 	// Exceed threshold to trigger rekey flag
@@ -313,6 +315,241 @@ func Test_PRNG_RekeyMechanism(t *testing.T) {
 	is.Equal(uint64(128), atomic.LoadUint64(&p.usage))
 }
 
+// Test_PRNG_RekeyInstall verifies that a cipher prepared by asyncRekey is installed by the
+// owning goroutine on its next Read, resetting the usage counter and clearing the rekey flag.
+func Test_PRNG_RekeyInstall(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	cfg := DefaultConfig()
+	cfg.MaxBytesPerKey = 64
+	cfg.EnableKeyRotation = true
+
+	r := newReader(&cfg)
+	p, err := newPRNG(r, 0)
+	is.NoError(err)
+
+	old := p.cipher.Load()
+
+	// Run the rekey synchronously to make the handoff deterministic.
+	atomic.StoreUint32(&p.rekeying, 1)
+	atomic.StoreUint64(&p.usage, 128)
+	p.asyncRekey()
+
+	is.NotNil(p.pending.Load(), "rekey should prepare a pending cipher")
+	is.Same(old, p.cipher.Load(), "rekey must not swap the active cipher itself")
+	is.Equal(uint32(1), atomic.LoadUint32(&p.rekeying), "flag should stay set until install")
+	is.Zero(r.Stats().KeyRotations, "a prepared cipher is not a rotation until installed")
+
+	buf := make([]byte, 16)
+	_, err = p.Read(buf)
+	is.NoError(err)
+
+	is.Nil(p.pending.Load(), "Read should consume the pending cipher")
+	is.NotSame(old, p.cipher.Load(), "Read should install the pending cipher")
+	is.Equal(uint32(0), atomic.LoadUint32(&p.rekeying), "install should clear the rekey flag")
+	is.Equal(uint64(16), atomic.LoadUint64(&p.usage), "usage should restart under the new key")
+	is.Equal(uint64(1), r.Stats().KeyRotations, "install should count the rotation")
+}
+
+// Test_PRNG_RekeyDiscarded verifies that a prepared cipher whose instance is dropped
+// before its next Read is neither counted nor reported as a rotation.
+func Test_PRNG_RekeyDiscarded(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	obs := &recordingObserver{}
+	cfg := DefaultConfig()
+	cfg.EnableKeyRotation = true
+	cfg.Observer = obs
+	r := newReader(&cfg)
+	p, err := newPRNG(r, 0)
+	is.NoError(err)
+
+	atomic.StoreUint32(&p.rekeying, 1)
+	p.asyncRekey()
+	is.NotNil(p.pending.Load())
+
+	// The instance is never read again, as when sync.Pool drops it.
+	is.Zero(r.Stats().KeyRotations)
+	obs.mu.Lock()
+	defer obs.mu.Unlock()
+	is.Empty(obs.rotated)
+}
+
+// Test_PRNG_RekeyConcurrentReads is a regression test for a data race in which an
+// asynchronous rekey wiped the active cipher while its owner was still generating output
+// with it. Run with -race: every rotation must be installed by the goroutine that owns
+// the instance.
+func Test_PRNG_RekeyConcurrentReads(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	r, err := NewReader(
+		WithShards(1),
+		WithEnableKeyRotation(true),
+		WithMaxBytesPerKey(64),
+		WithRekeyBackoff(time.Millisecond),
+		WithMaxRekeyBackoff(2*time.Millisecond),
+	)
+	is.NoError(err)
+
+	const (
+		numGoroutines   = 8
+		readsPerRoutine = 500
+	)
+	var wg sync.WaitGroup
+	wg.Add(numGoroutines)
+	for i := 0; i < numGoroutines; i++ {
+		go func() {
+			defer wg.Done()
+			buf := make([]byte, 4096)
+			for j := 0; j < readsPerRoutine; j++ {
+				if _, err := r.Read(buf); err != nil {
+					t.Errorf("Read failed: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	is.Greater(r.(*reader).Stats().KeyRotations, uint64(0), "reads should have rotated keys")
+}
+
+// Test_PRNG_RekeyZeroBackoff is a regression test for a failed rekey with a zero
+// RekeyBackoff, which divided by zero when jittering the retry delay. A zero backoff
+// must fall back to the documented 100 millisecond default.
+func Test_PRNG_RekeyZeroBackoff(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	cfg := DefaultConfig()
+	cfg.EnableKeyRotation = true
+	cfg.MaxRekeyAttempts = 2
+	cfg.RekeyBackoff = 0
+	cfg.LatencySampleInterval = 1
+	is.NoError(validateConfig(&cfg), "a zero RekeyBackoff is a valid configuration")
+
+	r := newReader(&cfg)
+	p, err := newPRNG(r, 0)
+	is.NoError(err)
+	r.entropy = failingSource{}
+
+	atomic.StoreUint32(&p.rekeying, 1)
+	is.NotPanics(p.asyncRekey)
+
+	is.Equal(uint64(2), r.Stats().RekeyFailures)
+	is.GreaterOrEqual(r.Stats().Latency.RekeyBackoff, rekeyBackoff, "the default backoff should apply")
+	is.Equal(uint32(0), atomic.LoadUint32(&p.rekeying))
+}
+
+// Test_PRNG_FailClosed verifies that a fail-closed instance never emits more than
+// MaxBytesPerKey under one key: with a failing entropy source Read stops at the key's
+// budget with ErrRekeyFailed and keeps failing, and once entropy recovers it rotates
+// synchronously and resumes.
+func Test_PRNG_FailClosed(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	obs := &recordingObserver{}
+	cfg := DefaultConfig()
+	ProfileParanoid()(&cfg)
+	cfg.Shards = 1
+	cfg.MaxBytesPerKey = 1024
+	cfg.MaxRekeyAttempts = 2
+	cfg.RekeyBackoff = time.Millisecond
+	cfg.MaxRekeyBackoff = time.Millisecond
+	cfg.Observer = obs
+	is.NoError(validateConfig(&cfg))
+
+	r := newReader(&cfg)
+	p, err := newPRNG(r, 0)
+	is.NoError(err)
+	r.pools[0] = &sync.Pool{New: func() any { return p }}
+	r.entropy = failingSource{}
+
+	buf := make([]byte, 4096)
+	n, err := r.Read(buf)
+	is.Equal(1024, n, "output should stop at the key's budget")
+	is.ErrorIs(err, ErrRekeyFailed)
+	is.ErrorIs(err, errEntropy)
+	is.False(bytes.Contains(buf[:n], make([]byte, 64)), "the budget should be filled with output")
+
+	n, err = r.Read(buf)
+	is.Zero(n, "an exhausted key should produce no further output")
+	is.ErrorIs(err, ErrRekeyFailed)
+
+	stats := r.Stats()
+	is.Equal(uint64(1024), stats.BytesGenerated, "the partial read should be counted")
+	is.Equal(uint64(4), stats.RekeyFailures)
+	is.Zero(stats.KeyRotations)
+	obs.mu.Lock()
+	is.Len(obs.rekeyFailed, 4)
+	obs.mu.Unlock()
+
+	// With entropy restored, each exhausted key is replaced before more output.
+	r.entropy = nil
+	n, err = r.Read(buf)
+	is.NoError(err)
+	is.Equal(len(buf), n)
+	is.Equal(uint64(4), r.Stats().KeyRotations, "4096 bytes should need four fresh keys")
+	is.Equal(uint64(0), atomic.LoadUint64(&p.usage)%1024)
+}
+
+// Test_PRNG_FailClosedNoTrailingBackoff verifies that a fail-closed Read returns as soon
+// as its last rekey attempt fails, without waiting out a backoff that precedes no retry.
+func Test_PRNG_FailClosedNoTrailingBackoff(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	cfg := DefaultConfig()
+	cfg.EnableKeyRotation = true
+	cfg.FailClosed = true
+	cfg.MaxBytesPerKey = 1024
+	cfg.MaxRekeyAttempts = 1
+	cfg.RekeyBackoff = time.Second
+	cfg.MaxRekeyBackoff = time.Second
+	cfg.LatencySampleInterval = 1
+
+	r := newReader(&cfg)
+	p, err := newPRNG(r, 0)
+	is.NoError(err)
+	r.entropy = failingSource{}
+
+	start := time.Now()
+	n, err := p.Read(make([]byte, 2048))
+	elapsed := time.Since(start)
+	is.Equal(1024, n)
+	is.ErrorIs(err, ErrRekeyFailed)
+	is.Less(elapsed, 500*time.Millisecond, "Read should not sleep after its last rekey attempt")
+	is.Zero(r.Stats().Latency.RekeyBackoff)
+}
+
+// Test_PRNG_FailClosedDisabled verifies that without FailClosed a failing rekey keeps
+// the exhausted key in use instead of failing Read.
+func Test_PRNG_FailClosedDisabled(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	cfg := DefaultConfig()
+	cfg.EnableKeyRotation = true
+	cfg.MaxBytesPerKey = 1024
+	cfg.MaxRekeyAttempts = 1
+	cfg.RekeyBackoff = time.Millisecond
+	cfg.MaxRekeyBackoff = time.Millisecond
+
+	r := newReader(&cfg)
+	p, err := newPRNG(r, 0)
+	is.NoError(err)
+	r.entropy = failingSource{}
+
+	n, err := p.Read(make([]byte, 4096))
+	is.NoError(err)
+	is.Equal(4096, n)
+	is.False(errors.Is(err, ErrRekeyFailed))
+}
+
 // Test_PRNG_Read_Shards verifies that a single call to Read only accesses
 // one shard pool out of many, regardless of the pool count. It does not
 // assert *which* shard is selected, as shardIndex is intentionally random.
@@ -342,6 +579,10 @@ func Test_PRNG_Read_Shards(t *testing.T) {
 			// hit[i] will be set true if pool[i] is accessed
 			hit := make([]bool, tc.shardCount)
 
+			cfg := DefaultConfig()
+			cfg.Shards = tc.shardCount
+			r := newReader(&cfg)
+
 			// Create sync.Pool array, each tracking access via hit[i]
 			pools := make([]*sync.Pool, tc.shardCount)
 			for i := 0; i < tc.shardCount; i++ {
@@ -350,16 +591,12 @@ func Test_PRNG_Read_Shards(t *testing.T) {
 					New: func() any {
 						// Record that this shard was used.
 						hit[id] = true
-						cfg := DefaultConfig()
-						d, _ := newPRNG(&cfg, &atomic.Uint64{})
+						d, _ := newPRNG(r, id)
 						return d
 					},
 				}
 			}
-
-			r := &reader{
-				pools: pools,
-			}
+			r.pools = pools
 
 			buf := make([]byte, 32)
 			_, err := r.Read(buf)
@@ -483,6 +720,11 @@ func Test_PRNG_NewReader_ValidationErrors(t *testing.T) {
 				WithMaxRekeyBackoff(2 * time.Second),
 			},
 			wantErr: ErrMaxRekeyBackoffTooSmall,
+		},
+		{
+			name:    "NegativeLatencySampleInterval",
+			opts:    []Option{WithLatencyTracking(-1)},
+			wantErr: ErrLatencySampleIntervalNegative,
 		}}
 
 	for _, tc := range testCases {
@@ -538,12 +780,15 @@ func Test_PRNG_KeyRotationTracking(t *testing.T) {
 		is.NoError(err, "Read %d should succeed", i)
 	}
 
-	// Wait for async rekeys to complete (generous timeout)
-	time.Sleep(500 * time.Millisecond)
-
-	// Verify that key rotations occurred
+	// Wait for async rekeys to complete (generous timeout); prepared keys are
+	// installed, and counted, by the next Read on each instance.
+	is.Eventually(func() bool {
+		if _, err := r.Read(buf); err != nil {
+			return false
+		}
+		return r.(*reader).Stats().KeyRotations > 0
+	}, 2*time.Second, 5*time.Millisecond, "KeyRotations should be > 0 after heavy usage")
 	stats = r.(*reader).Stats()
-	is.Greater(stats.KeyRotations, uint64(0), "KeyRotations should be > 0 after heavy usage")
 
 	// Verify bytes generated counter is also tracking correctly
 	expectedMinBytes := uint64(numReads * readSize)
@@ -599,3 +844,244 @@ func Test_PRNG_Stats_Concurrent(t *testing.T) {
 	is.Equal(expectedBytes, stats.BytesGenerated, "BytesGenerated should match total reads")
 	is.Equal(uint64(0), stats.KeyRotations, "KeyRotations should be 0 when disabled")
 }
+
+// Test_PRNG_Update_AppliesChanges verifies that Update swaps in the new configuration
+// and that enabling key rotation on a live reader triggers rotations on subsequent reads.
+func Test_PRNG_Update_AppliesChanges(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	r, err := NewReader(WithShards(1))
+	is.NoError(err)
+	is.False(r.Config().EnableKeyRotation)
+
+	err = r.(Updater).Update(
+		WithEnableKeyRotation(true),
+		WithMaxBytesPerKey(32),
+		WithRekeyBackoff(5*time.Millisecond),
+		WithMaxRekeyBackoff(10*time.Millisecond),
+		WithZeroBuffer(true),
+	)
+	is.NoError(err, "Update should accept a valid configuration")
+
+	cfg := r.Config()
+	is.True(cfg.EnableKeyRotation)
+	is.Equal(uint64(32), cfg.MaxBytesPerKey)
+	is.Equal(5*time.Millisecond, cfg.RekeyBackoff)
+	is.Equal(10*time.Millisecond, cfg.MaxRekeyBackoff)
+	is.True(cfg.UseZeroBuffer)
+	is.Equal(1, cfg.Shards, "Update should leave unrelated fields unchanged")
+
+	buf := make([]byte, 64)
+	is.Eventually(func() bool {
+		if _, err := r.Read(buf); err != nil {
+			return false
+		}
+		return r.(*reader).Stats().KeyRotations > 0
+	}, 2*time.Second, 5*time.Millisecond, "rotation should occur after enabling it via Update")
+}
+
+// Test_PRNG_OptionalInterfaces verifies that readers returned by NewReader and the
+// package-level Reader implement Updater and StatsReporter, and that an Interface
+// implementation need not.
+func Test_PRNG_OptionalInterfaces(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	r, err := NewReader()
+	is.NoError(err)
+	for _, src := range []io.Reader{r, Reader} {
+		_, ok := src.(Updater)
+		is.True(ok, "readers should implement Updater")
+		_, ok = src.(StatsReporter)
+		is.True(ok, "readers should implement StatsReporter")
+	}
+
+	var src Interface = staticSource{}
+	_, ok := src.(Updater)
+	is.False(ok)
+	_, ok = src.(StatsReporter)
+	is.False(ok)
+}
+
+// staticSource is a minimal Interface implementation, as written against the original
+// two-method Interface.
+type staticSource struct{}
+
+func (staticSource) Read(b []byte) (int, error) { return len(b), nil }
+func (staticSource) Config() Config             { return DefaultConfig() }
+
+// Test_PRNG_Update_ValidationErrors verifies that Update rejects invalid configurations
+// and keeps the previously active configuration.
+func Test_PRNG_Update_ValidationErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		opts    []Option
+		wantErr error
+	}{
+		{
+			name:    "ZeroMaxBytesPerKey",
+			opts:    []Option{WithMaxBytesPerKey(0)},
+			wantErr: ErrMaxBytesPerKeyZero,
+		},
+		{
+			name:    "NegativeMaxRekeyAttempts",
+			opts:    []Option{WithMaxRekeyAttempts(-1)},
+			wantErr: ErrMaxRekeyAttemptsNegative,
+		},
+		{
+			name: "MaxRekeyBackoffLessThanRekeyBackoff",
+			opts: []Option{
+				WithRekeyBackoff(5 * time.Second),
+				WithMaxRekeyBackoff(2 * time.Second),
+			},
+			wantErr: ErrMaxRekeyBackoffTooSmall,
+		},
+		{
+			name:    "ShardsChanged",
+			opts:    []Option{WithShards(3)},
+			wantErr: ErrShardsImmutable,
+		},
+		{
+			name:    "ValidChangeWithShardsChanged",
+			opts:    []Option{WithMaxBytesPerKey(64), WithShards(5)},
+			wantErr: ErrShardsImmutable,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			is := assert.New(t)
+
+			r, err := NewReader(WithShards(2))
+			is.NoError(err)
+			before := r.Config()
+
+			err = r.(Updater).Update(tc.opts...)
+			is.ErrorIs(err, tc.wantErr, "Expected specific error for invalid update")
+			is.Equal(before, r.Config(), "Config should be unchanged after a failed Update")
+		})
+	}
+}
+
+// Test_PRNG_Update_SameShards verifies that passing the current shard count to Update is accepted.
+func Test_PRNG_Update_SameShards(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	r, err := NewReader(WithShards(4))
+	is.NoError(err)
+	is.NoError(r.(Updater).Update(WithShards(4), WithDefaultBufferSize(256)))
+	is.Equal(256, r.Config().DefaultBufferSize)
+}
+
+// Test_PRNG_Update_ConcurrentReads exercises Update while many goroutines read from the
+// same reader. Run with -race to verify the configuration swap is free of data races.
+func Test_PRNG_Update_ConcurrentReads(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	r, err := NewReader(
+		WithShards(4),
+		WithRekeyBackoff(time.Millisecond),
+		WithMaxRekeyBackoff(2*time.Millisecond),
+	)
+	is.NoError(err)
+
+	const (
+		numReaders      = 16
+		readsPerRoutine = 200
+		numUpdates      = 100
+	)
+
+	var (
+		wg      sync.WaitGroup
+		readErr atomic.Value
+	)
+	wg.Add(numReaders)
+	for i := 0; i < numReaders; i++ {
+		go func() {
+			defer wg.Done()
+			buf := make([]byte, 48)
+			for j := 0; j < readsPerRoutine; j++ {
+				if n, err := r.Read(buf); err != nil || n != len(buf) {
+					readErr.Store(fmt.Errorf("read %d returned n=%d err=%v", j, n, err))
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < numUpdates; i++ {
+			on := i%2 == 0
+			_ = r.(Updater).Update(
+				WithEnableKeyRotation(on),
+				WithZeroBuffer(on),
+				WithMaxBytesPerKey(uint64(64+i)),
+				WithDefaultBufferSize(32+i),
+			)
+		}
+	}()
+
+	wg.Wait()
+	if v := readErr.Load(); v != nil {
+		is.NoError(v.(error))
+	}
+
+	cfg := r.Config()
+	is.Equal(uint64(64+numUpdates-1), cfg.MaxBytesPerKey, "last Update should win")
+	is.Equal(4, cfg.Shards)
+}
+
+// Test_PRNG_Stats_PerShard verifies that per-shard counters sum to the reported totals
+// and that failed rekey attempts are counted against the shard that attempted them.
+func Test_PRNG_Stats_PerShard(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	r, err := NewReader(WithShards(4))
+	is.NoError(err)
+
+	buf := make([]byte, 100)
+	for i := 0; i < 64; i++ {
+		_, err := r.Read(buf)
+		is.NoError(err)
+	}
+
+	stats := r.(StatsReporter).Stats()
+	is.Len(stats.Shards, 4)
+	is.Equal(uint64(64*100), stats.BytesGenerated)
+
+	var sum uint64
+	for _, s := range stats.Shards {
+		sum += s.BytesGenerated
+	}
+	is.Equal(stats.BytesGenerated, sum, "per-shard bytes should sum to the total")
+
+	// Force rekey failures on shard 2 of a separate reader.
+	cfg := DefaultConfig()
+	cfg.Shards = 4
+	cfg.MaxRekeyAttempts = 2
+	cfg.RekeyBackoff = time.Millisecond
+	cfg.MaxRekeyBackoff = time.Millisecond
+	failing := newReader(&cfg)
+	p, err := newPRNG(failing, 2)
+	is.NoError(err)
+	failing.entropy = failingSource{}
+
+	atomic.StoreUint32(&p.rekeying, 1)
+	p.asyncRekey()
+
+	fstats := failing.Stats()
+	is.Equal(uint64(2), fstats.RekeyFailures)
+	is.Equal(uint64(2), fstats.Shards[2].RekeyFailures)
+	is.Equal(uint64(0), fstats.Shards[0].RekeyFailures)
+	is.Equal(uint64(0), fstats.KeyRotations)
+}
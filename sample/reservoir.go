@@ -0,0 +1,150 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package sample
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+
+	"github.com/sixafter/prng-chacha/internal/randutil"
+)
+
+var (
+	ErrReservoirSizeInvalid = fmt.Errorf("sample: reservoir size must be greater than zero")
+)
+
+// Reservoir keeps a uniform random sample of up to k items from a stream of unknown
+// length: after n items have been added, every k-element subset of them is equally
+// likely to be held (or all n items, while n <= k).
+//
+// It uses Kim-Hung Li's Algorithm L, "Reservoir-Sampling Algorithms of Time Complexity
+// O(n(1 + log(N/n)))", ACM TOMS 20(4), 1994: rather than drawing a random number per
+// item, it draws how many items to skip before the next replacement, so a stream of N
+// items costs about k·(1 + ln(N/k)) random draws in total.
+//
+// A Reservoir is safe for concurrent use.
+type Reservoir[T any] struct {
+	mu    sync.Mutex
+	k     int
+	items []T
+	r     *randutil.Reader
+
+	// seen is the number of items added.
+	seen uint64
+	// next is the 0-based position in the stream of the next item to be kept.
+	next uint64
+	// w is Algorithm L's running W: the largest of k uniform keys among kept items.
+	w float64
+}
+
+// NewReservoir returns an empty Reservoir holding up to k items.
+//
+// It returns ErrReservoirSizeInvalid if k is not positive and ErrNilReader if the
+// configured reader is nil.
+func NewReservoir[T any](k int, opts ...Option) (*Reservoir[T], error) {
+	cfg, err := newConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	if k <= 0 {
+		return nil, ErrReservoirSizeInvalid
+	}
+	return &Reservoir[T]{
+		k:     k,
+		items: make([]T, 0, k),
+		r:     randutil.NewReader(cfg.Reader),
+	}, nil
+}
+
+// Add offers item to the reservoir. Most items are skipped without any random draw.
+//
+// It returns an error only if the underlying reader fails, in which case item may not
+// have been considered and the reservoir should be Reset.
+func (s *Reservoir[T]) Add(item T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pos := s.seen
+	s.seen++
+
+	if pos < uint64(s.k) {
+		s.items = append(s.items, item)
+		if len(s.items) == s.k {
+			s.w = 1
+			if err := s.advanceW(); err != nil {
+				return err
+			}
+			return s.skip(pos)
+		}
+		return nil
+	}
+	if pos != s.next {
+		return nil
+	}
+
+	j, err := s.r.Intn(s.k)
+	if err != nil {
+		return err
+	}
+	s.items[j] = item
+	if err := s.advanceW(); err != nil {
+		return err
+	}
+	return s.skip(pos)
+}
+
+// advanceW multiplies W by U^(1/k) for a fresh uniform U.
+func (s *Reservoir[T]) advanceW() error {
+	u, err := s.r.OpenFloat64()
+	if err != nil {
+		return err
+	}
+	s.w *= math.Exp(math.Log(u) / float64(s.k))
+	return nil
+}
+
+// skip sets next to the position of the next item to keep after pos: the number of items
+// skipped is geometrically distributed with success probability W. Once W is so small
+// that the gap exceeds the uint64 range, no further items are kept.
+func (s *Reservoir[T]) skip(pos uint64) error {
+	u, err := s.r.OpenFloat64()
+	if err != nil {
+		return err
+	}
+	gap := math.Floor(math.Log(u) / math.Log1p(-s.w))
+	if !(gap < float64(math.MaxUint64-pos-1)) {
+		s.next = math.MaxUint64
+		return nil
+	}
+	s.next = pos + 1 + uint64(gap)
+	return nil
+}
+
+// Sample returns a copy of the items currently held, in no particular order.
+func (s *Reservoir[T]) Sample() []T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.items)
+}
+
+// Count returns the number of items added since creation or the last Reset.
+func (s *Reservoir[T]) Count() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.seen
+}
+
+// Reset empties the reservoir so that it can sample a new stream.
+func (s *Reservoir[T]) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.items)
+	s.items = s.items[:0]
+	s.seen, s.next, s.w = 0, 0, 0
+	s.r.Wipe()
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package sample selects items at random using randomness from the prng package.
+//
+// WeightedSampler picks among many options with given weights in constant time per
+// sample, using Vose's alias method. Integer weights are sampled exactly: each item is
+// returned with probability exactly its weight divided by the total, with no
+// floating-point rounding.
+//
+// SampleK chooses k distinct elements of a slice and SampleIndexes chooses k distinct
+// indexes with Floyd's algorithm, both without replacement. Reservoir keeps a uniform
+// sample of a stream of unknown length, such as audit log records, using Algorithm L,
+// which draws random numbers only for the items it keeps.
+//
+// Randomness is read through small buffers, so a draw costs a fraction of a Read on the
+// underlying source. Samplers and reservoirs are safe for concurrent use.
+//
+// Example:
+//
+//	s, err := sample.NewWeightedSamplerInt([]string{"stable", "canary"}, []uint64{95, 5})
+//	if err != nil {
+//	    // handle error
+//	}
+//	backend, err := s.Sample()
+package sample
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/sixafter/prng-chacha"
+	"github.com/sixafter/prng-chacha/internal/randutil"
+)
+
+var (
+	ErrNilReader = fmt.Errorf("sample: reader must not be nil")
+)
+
+// Config defines the randomness source of a sampler.
+type Config struct {
+	// Reader is the source of randomness. Defaults to prng.Reader.
+	Reader io.Reader
+}
+
+// Option defines a functional option for customizing a sampler's Config.
+type Option func(*Config)
+
+// WithReader returns an Option that sets the source of randomness, typically a
+// prng.Interface returned by prng.NewReader.
+func WithReader(r io.Reader) Option {
+	return func(cfg *Config) {
+		cfg.Reader = r
+	}
+}
+
+// newConfig applies opts to the default Config and validates the result.
+func newConfig(opts []Option) (Config, error) {
+	cfg := Config{
+		Reader: prng.Reader,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.Reader == nil {
+		return cfg, ErrNilReader
+	}
+	return cfg, nil
+}
+
+// readerPool pools buffered readers over one source, so that concurrent callers each
+// draw from their own buffer.
+type readerPool struct {
+	pool sync.Pool
+}
+
+// newReaderPool returns a readerPool over src.
+func newReaderPool(src io.Reader) *readerPool {
+	p := &readerPool{}
+	p.pool.New = func() any {
+		return randutil.NewReader(src)
+	}
+	return p
+}
+
+// get returns a buffered reader for exclusive use until it is passed to put.
+func (p *readerPool) get() *randutil.Reader {
+	return p.pool.Get().(*randutil.Reader)
+}
+
+// put returns a buffered reader to the pool.
+func (p *readerPool) put(r *randutil.Reader) {
+	p.pool.Put(r)
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package sample
+
+import (
+	"strconv"
+	"testing"
+)
+
+// BenchmarkSample_Weighted measures alias-method sampling across table sizes; the cost
+// does not depend on the number of items.
+func BenchmarkSample_Weighted(b *testing.B) {
+	for _, n := range []int{2, 100, 10000} {
+		items := make([]int, n)
+		weights := make([]uint64, n)
+		for i := range items {
+			items[i] = i
+			weights[i] = uint64(i%7 + 1)
+		}
+		b.Run("Items_"+strconv.Itoa(n), func(b *testing.B) {
+			s, err := NewWeightedSamplerInt(items, weights)
+			if err != nil {
+				b.Fatalf("NewWeightedSamplerInt failed: %v", err)
+			}
+			b.ReportAllocs()
+			for b.Loop() {
+				_, _ = s.Sample()
+			}
+		})
+	}
+}
+
+// BenchmarkSample_Weighted_Parallel measures concurrent sampling with RunParallel.
+func BenchmarkSample_Weighted_Parallel(b *testing.B) {
+	s, err := NewWeightedSampler([]string{"stable", "canary", "shadow"}, []float64{0.9, 0.09, 0.01})
+	if err != nil {
+		b.Fatalf("NewWeightedSampler failed: %v", err)
+	}
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = s.Sample()
+		}
+	})
+}
+
+// BenchmarkSample_SampleK measures choosing 10 elements from slices of growing size; the
+// dense path copies the slice, the sparse path does not.
+func BenchmarkSample_SampleK(b *testing.B) {
+	for _, n := range []int{20, 1000, 100000} {
+		items := make([]int, n)
+		b.Run("N_"+strconv.Itoa(n), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				_, _ = SampleK(items, 10)
+			}
+		})
+	}
+}
+
+// BenchmarkSample_SampleIndexes measures Floyd's algorithm, whose cost depends only on k.
+func BenchmarkSample_SampleIndexes(b *testing.B) {
+	for _, k := range []int{10, 1000} {
+		b.Run("K_"+strconv.Itoa(k), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				_, _ = SampleIndexes(1<<30, k)
+			}
+		})
+	}
+}
+
+// BenchmarkSample_Reservoir measures Add over a long stream, where most items are
+// skipped without a random draw.
+func BenchmarkSample_Reservoir(b *testing.B) {
+	r, err := NewReservoir[int](100)
+	if err != nil {
+		b.Fatalf("NewReservoir failed: %v", err)
+	}
+	b.ReportAllocs()
+	i := 0
+	for b.Loop() {
+		_ = r.Add(i)
+		i++
+	}
+}
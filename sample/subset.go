@@ -0,0 +1,130 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package sample
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/sixafter/prng-chacha"
+	"github.com/sixafter/prng-chacha/internal/randutil"
+)
+
+var (
+	ErrSampleSizeInvalid = fmt.Errorf("sample: sample size must be between zero and the population size")
+)
+
+// denseFactor selects the strategy of SampleK: when k·denseFactor >= n, copying the
+// slice and partially shuffling it is cheaper than tracking chosen indexes in a map.
+const denseFactor = 4
+
+// defaultReaders serves the package-level functions when no reader option is given.
+var defaultReaders = newReaderPool(prng.Reader)
+
+// acquire returns a buffered reader for cfg and a function that releases it.
+func acquire(cfg Config) (*randutil.Reader, func()) {
+	if cfg.Reader == prng.Reader {
+		r := defaultReaders.get()
+		return r, func() { defaultReaders.put(r) }
+	}
+	r := randutil.NewReader(cfg.Reader)
+	return r, r.Wipe
+}
+
+// SampleK returns k distinct elements of items, chosen uniformly at random without
+// replacement, in random order. Elements are distinct by position; equal values at
+// different positions may both be chosen. items is not modified.
+//
+// Every k-element subset is equally likely, and so is every ordering of it. The cost is
+// O(k) random draws; for k much smaller than len(items), only O(k) memory is used.
+//
+// It returns ErrSampleSizeInvalid unless 0 <= k <= len(items), ErrNilReader if the
+// configured reader is nil, and the reader's error if it fails.
+func SampleK[T any](items []T, k int, opts ...Option) ([]T, error) {
+	cfg, err := newConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	n := len(items)
+	if k < 0 || k > n {
+		return nil, ErrSampleSizeInvalid
+	}
+	r, release := acquire(cfg)
+	defer release()
+
+	if k*denseFactor >= n {
+		// Partial Fisher-Yates: the first k positions of a shuffled copy.
+		out := slices.Clone(items)
+		for i := 0; i < k; i++ {
+			j, err := r.Intn(n - i)
+			if err != nil {
+				return nil, err
+			}
+			out[i], out[i+j] = out[i+j], out[i]
+		}
+		return out[:k:k], nil
+	}
+
+	idx, err := floyd(r, n, k)
+	if err != nil {
+		return nil, err
+	}
+	// Floyd's algorithm fixes the subset but not a uniform order, so shuffle it.
+	if err := r.Shuffle(len(idx), func(i, j int) { idx[i], idx[j] = idx[j], idx[i] }); err != nil {
+		return nil, err
+	}
+	out := make([]T, k)
+	for i, j := range idx {
+		out[i] = items[j]
+	}
+	return out, nil
+}
+
+// SampleIndexes returns k distinct indexes from [0, n), chosen uniformly at random
+// without replacement, in ascending order, using Robert Floyd's algorithm: exactly k
+// random draws and O(k) memory regardless of n. Ascending order suits selecting records
+// from a file or log in a single forward pass.
+//
+// It returns ErrSampleSizeInvalid unless 0 <= k <= n, ErrNilReader if the configured
+// reader is nil, and the reader's error if it fails.
+func SampleIndexes(n, k int, opts ...Option) ([]int, error) {
+	cfg, err := newConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	if k < 0 || k > n {
+		return nil, ErrSampleSizeInvalid
+	}
+	r, release := acquire(cfg)
+	defer release()
+
+	idx, err := floyd(r, n, k)
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(idx)
+	return idx, nil
+}
+
+// floyd returns k distinct indexes from [0, n) with Floyd's algorithm. For each j from
+// n-k to n-1 it draws t from [0, j] and adds t, or j if t was already chosen; every
+// k-subset results with equal probability.
+func floyd(r *randutil.Reader, n, k int) ([]int, error) {
+	chosen := make(map[int]struct{}, k)
+	idx := make([]int, 0, k)
+	for j := n - k; j < n; j++ {
+		t, err := r.Intn(j + 1)
+		if err != nil {
+			return nil, err
+		}
+		if _, dup := chosen[t]; dup {
+			t = j
+		}
+		chosen[t] = struct{}{}
+		idx = append(idx, t)
+	}
+	return idx, nil
+}
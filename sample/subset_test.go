@@ -0,0 +1,245 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package sample
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/sixafter/prng-chacha"
+	"github.com/stretchr/testify/assert"
+)
+
+// uniformChi2 returns the chi-squared statistic of counts against equal probabilities
+// over cells outcomes.
+func uniformChi2[K comparable](counts map[K]int, cells, draws int) float64 {
+	exp := float64(draws) / float64(cells)
+	var chi2 float64
+	for _, c := range counts {
+		d := float64(c) - exp
+		chi2 += d * d / exp
+	}
+	// Cells never observed contribute exp each.
+	chi2 += float64(cells-len(counts)) * exp
+	return chi2
+}
+
+// TestSampleK_Uniform verifies that every ordered selection of 2 out of 5 elements is
+// equally likely on the dense path, and every subset of 3 out of 40 on the sparse path.
+func TestSampleK_Uniform(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	// Dense: 5·4 = 20 ordered pairs.
+	items := []string{"a", "b", "c", "d", "e"}
+	counts := make(map[string]int)
+	const draws = 40000
+	for i := 0; i < draws; i++ {
+		got, err := SampleK(items, 2)
+		is.NoError(err)
+		is.Len(got, 2)
+		is.NotEqual(got[0], got[1])
+		counts[got[0]+got[1]]++
+	}
+	is.Len(counts, 20)
+	// 19 degrees of freedom; critical value at p = 0.0001 is about 50.8.
+	is.Less(uniformChi2(counts, 20, draws), 50.8)
+	is.Equal([]string{"a", "b", "c", "d", "e"}, items, "input must not be modified")
+
+	// Sparse: each index of 40 appears in a sample of 3 with probability 3/40, and each
+	// position is equally likely to hold it.
+	pop := make([]int, 40)
+	for i := range pop {
+		pop[i] = i
+	}
+	var first [40]int
+	inclusion := make(map[int]int)
+	for i := 0; i < draws; i++ {
+		got, err := SampleK(pop, 3)
+		is.NoError(err)
+		is.Len(got, 3)
+		is.Len(distinct(got), 3)
+		first[got[0]]++
+		for _, v := range got {
+			inclusion[v]++
+		}
+	}
+	// 39 degrees of freedom; critical value at p = 0.0001 is about 80.1.
+	is.Less(uniformChi2(inclusion, 40, 3*draws), 80.1)
+	firstCounts := make(map[int]int)
+	for v, c := range first {
+		firstCounts[v] = c
+	}
+	is.Less(uniformChi2(firstCounts, 40, draws), 80.1)
+}
+
+// distinct returns the distinct values of s.
+func distinct(s []int) []int {
+	c := slices.Clone(s)
+	slices.Sort(c)
+	return slices.Compact(c)
+}
+
+// TestSampleIndexes_Uniform verifies that every 3-subset of [0, 6) is equally likely
+// and that indexes are returned in ascending order.
+func TestSampleIndexes_Uniform(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	counts := make(map[string]int)
+	const draws = 40000
+	for i := 0; i < draws; i++ {
+		idx, err := SampleIndexes(6, 3)
+		is.NoError(err)
+		is.True(slices.IsSorted(idx))
+		is.Len(slices.Compact(slices.Clone(idx)), 3)
+		counts[fmt.Sprint(idx)]++
+	}
+	// C(6, 3) = 20 subsets, 19 degrees of freedom.
+	is.Len(counts, 20)
+	is.Less(uniformChi2(counts, 20, draws), 50.8)
+
+	// A large population costs only k draws.
+	idx, err := SampleIndexes(1<<40, 5)
+	is.NoError(err)
+	is.Len(idx, 5)
+	for _, v := range idx {
+		is.Less(v, 1<<40)
+	}
+}
+
+// TestSampleK_Edges verifies empty and full samples and argument validation.
+func TestSampleK_Edges(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	got, err := SampleK([]int{1, 2, 3}, 0)
+	is.NoError(err)
+	is.Empty(got)
+
+	got, err = SampleK([]int{1, 2, 3}, 3)
+	is.NoError(err)
+	slices.Sort(got)
+	is.Equal([]int{1, 2, 3}, got)
+
+	idx, err := SampleIndexes(0, 0)
+	is.NoError(err)
+	is.Empty(idx)
+
+	_, err = SampleK([]int{1, 2, 3}, 4)
+	is.ErrorIs(err, ErrSampleSizeInvalid)
+	_, err = SampleK([]int{1, 2, 3}, -1)
+	is.ErrorIs(err, ErrSampleSizeInvalid)
+	_, err = SampleIndexes(3, 4)
+	is.ErrorIs(err, ErrSampleSizeInvalid)
+	_, err = SampleK([]int{1}, 1, WithReader(nil))
+	is.ErrorIs(err, ErrNilReader)
+	_, err = SampleIndexes(10, 2, WithReader(errReader{}))
+	is.ErrorIs(err, errRead)
+	_, err = SampleK(make([]int, 100), 2, WithReader(errReader{}))
+	is.ErrorIs(err, errRead)
+
+	// A custom reader is used directly rather than through the shared pool.
+	rdr, err := prng.NewReader()
+	is.NoError(err)
+	got, err = SampleK([]int{1, 2, 3, 4}, 2, WithReader(rdr))
+	is.NoError(err)
+	is.Len(got, 2)
+	is.NotZero(rdr.(prng.StatsReporter).Stats().BytesGenerated)
+}
+
+// TestReservoir_Uniform verifies that after a stream of 6 items, each of the 15
+// two-element subsets is equally likely, and that inclusion probabilities are k/n on a
+// longer stream that exercises skipping.
+func TestReservoir_Uniform(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	counts := make(map[string]int)
+	const draws = 30000
+	for i := 0; i < draws; i++ {
+		r, err := NewReservoir[int](2)
+		is.NoError(err)
+		for v := 0; v < 6; v++ {
+			is.NoError(r.Add(v))
+		}
+		s := r.Sample()
+		slices.Sort(s)
+		counts[fmt.Sprint(s)]++
+	}
+	is.Len(counts, 15)
+	// 14 degrees of freedom; critical value at p = 0.0001 is about 41.3.
+	is.Less(uniformChi2(counts, 15, draws), 41.3)
+
+	inclusion := make(map[int]int)
+	const n, k, trials = 200, 10, 4000
+	r, err := NewReservoir[int](k)
+	is.NoError(err)
+	for i := 0; i < trials; i++ {
+		r.Reset()
+		for v := 0; v < n; v++ {
+			is.NoError(r.Add(v))
+		}
+		is.Equal(uint64(n), r.Count())
+		s := r.Sample()
+		is.Len(s, k)
+		is.Len(distinct(s), k)
+		for _, v := range s {
+			inclusion[v]++
+		}
+	}
+	// 199 degrees of freedom; critical value at p = 0.0001 is about 280.
+	is.Less(uniformChi2(inclusion, n, k*trials), 280.0)
+}
+
+// TestReservoir_ShortStream verifies that a stream shorter than k is kept entirely.
+func TestReservoir_ShortStream(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	r, err := NewReservoir[string](5)
+	is.NoError(err)
+	for _, v := range []string{"x", "y", "z"} {
+		is.NoError(r.Add(v))
+	}
+	is.ElementsMatch([]string{"x", "y", "z"}, r.Sample())
+
+	_, err = NewReservoir[int](0)
+	is.ErrorIs(err, ErrReservoirSizeInvalid)
+	_, err = NewReservoir[int](1, WithReader(nil))
+	is.ErrorIs(err, ErrNilReader)
+
+	f, err := NewReservoir[int](1, WithReader(errReader{}))
+	is.NoError(err)
+	is.ErrorIs(f.Add(1), errRead)
+}
+
+// TestReservoir_Concurrent adds from many goroutines; run with -race.
+func TestReservoir_Concurrent(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	r, err := NewReservoir[int](50)
+	is.NoError(err)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 10000; i++ {
+				if err := r.Add(g*10000 + i); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	is.Equal(uint64(80000), r.Count())
+	is.Len(r.Sample(), 50)
+}
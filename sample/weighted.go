@@ -0,0 +1,247 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package sample
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+var (
+	ErrNoItems         = fmt.Errorf("sample: at least one item is required")
+	ErrWeightsLength   = fmt.Errorf("sample: the number of weights must equal the number of items")
+	ErrWeightInvalid   = fmt.Errorf("sample: weights must be finite and non-negative")
+	ErrWeightsZero     = fmt.Errorf("sample: at least one weight must be greater than zero")
+	ErrWeightsOverflow = fmt.Errorf("sample: the sum of the weights times the number of items must fit in a uint64")
+)
+
+// WeightedSampler selects items with probability proportional to their weights in
+// constant time, using Vose's alias method: the weights are spread over one column per
+// item, each column holding part of its own item and at most one other item, its alias.
+// A sample picks a column uniformly and then chooses between the column's item and its
+// alias.
+//
+// A WeightedSampler is immutable after construction and safe for concurrent use.
+type WeightedSampler[T any] struct {
+	items []T
+
+	// prob[i] is the share of column i that selects item i, out of total. The rest of
+	// the column selects alias[i].
+	prob  []uint64
+	alias []int
+
+	// total is the height of every column. Zero stands for 2^64, used for float weights.
+	total uint64
+
+	readers *readerPool
+}
+
+// NewWeightedSampler returns a WeightedSampler over items with float64 weights. The
+// items and weights slices are copied and must have equal, non-zero length.
+//
+// Each column's split is rounded to 64 bits of precision, so selection probabilities
+// can differ from the exact weight ratios by about 2^-64 per item, in addition to any
+// rounding in the weights themselves. Use NewWeightedSamplerInt when the probabilities
+// must be exact.
+//
+// It returns ErrNoItems, ErrWeightsLength, ErrWeightInvalid or ErrWeightsZero for
+// unusable inputs and ErrNilReader if the configured reader is nil.
+func NewWeightedSampler[T any](items []T, weights []float64, opts ...Option) (*WeightedSampler[T], error) {
+	cfg, err := newConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, ErrNoItems
+	}
+	if len(weights) != len(items) {
+		return nil, ErrWeightsLength
+	}
+	var sum float64
+	for _, w := range weights {
+		if !(w >= 0) || math.IsInf(w, 0) {
+			return nil, ErrWeightInvalid
+		}
+		sum += w
+	}
+	if sum == 0 {
+		return nil, ErrWeightsZero
+	}
+	if math.IsInf(sum, 0) {
+		return nil, ErrWeightInvalid
+	}
+
+	n := len(items)
+	scaled := make([]float64, n)
+	for i, w := range weights {
+		scaled[i] = w / sum * float64(n)
+	}
+
+	s := newWeightedSampler(items, cfg)
+	small, large := partition(n, func(i int) bool { return scaled[i] < 1 })
+	for len(small) > 0 && len(large) > 0 {
+		l, g := small[len(small)-1], large[len(large)-1]
+		small, large = small[:len(small)-1], large[:len(large)-1]
+
+		s.prob[l] = toFixed(scaled[l])
+		s.alias[l] = g
+		scaled[g] = (scaled[g] + scaled[l]) - 1
+		if scaled[g] < 1 {
+			small = append(small, g)
+		} else {
+			large = append(large, g)
+		}
+	}
+	// Whatever remains is a full column up to rounding error, so it selects only itself.
+	for _, i := range append(small, large...) {
+		s.prob[i] = math.MaxUint64
+		s.alias[i] = i
+	}
+	return s, nil
+}
+
+// NewWeightedSamplerInt returns a WeightedSampler over items with integer weights, which
+// selects item i with probability exactly weights[i] divided by the sum of the weights.
+// The items and weights slices are copied and must have equal, non-zero length.
+//
+// It returns ErrNoItems, ErrWeightsLength or ErrWeightsZero for unusable inputs,
+// ErrWeightsOverflow if the sum of the weights times the number of items exceeds the
+// uint64 range, and ErrNilReader if the configured reader is nil.
+func NewWeightedSamplerInt[T any](items []T, weights []uint64, opts ...Option) (*WeightedSampler[T], error) {
+	cfg, err := newConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, ErrNoItems
+	}
+	if len(weights) != len(items) {
+		return nil, ErrWeightsLength
+	}
+	var sum uint64
+	for _, w := range weights {
+		var carry uint64
+		sum, carry = bits.Add64(sum, w, 0)
+		if carry != 0 {
+			return nil, ErrWeightsOverflow
+		}
+	}
+	if sum == 0 {
+		return nil, ErrWeightsZero
+	}
+	n := uint64(len(items))
+	if hi, _ := bits.Mul64(sum, n); hi != 0 {
+		return nil, ErrWeightsOverflow
+	}
+
+	// Scale every weight by n so that each column holds exactly sum.
+	scaled := make([]uint64, n)
+	for i, w := range weights {
+		scaled[i] = w * n
+	}
+
+	s := newWeightedSampler(items, cfg)
+	s.total = sum
+	small, large := partition(len(items), func(i int) bool { return scaled[i] < sum })
+	for len(small) > 0 && len(large) > 0 {
+		l, g := small[len(small)-1], large[len(large)-1]
+		small, large = small[:len(small)-1], large[:len(large)-1]
+
+		s.prob[l] = scaled[l]
+		s.alias[l] = g
+		// The large item fills the rest of column l.
+		scaled[g] -= sum - scaled[l]
+		if scaled[g] < sum {
+			small = append(small, g)
+		} else {
+			large = append(large, g)
+		}
+	}
+	// With exact arithmetic, the remaining columns are exactly full.
+	for _, i := range append(small, large...) {
+		s.prob[i] = sum
+		s.alias[i] = i
+	}
+	return s, nil
+}
+
+// newWeightedSampler allocates a sampler with copies of items and empty tables.
+func newWeightedSampler[T any](items []T, cfg Config) *WeightedSampler[T] {
+	return &WeightedSampler[T]{
+		items:   append([]T(nil), items...),
+		prob:    make([]uint64, len(items)),
+		alias:   make([]int, len(items)),
+		readers: newReaderPool(cfg.Reader),
+	}
+}
+
+// partition splits the indexes [0, n) into those for which isSmall reports true and
+// the rest.
+func partition(n int, isSmall func(int) bool) (small, large []int) {
+	small = make([]int, 0, n)
+	large = make([]int, 0, n)
+	for i := 0; i < n; i++ {
+		if isSmall(i) {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+	return small, large
+}
+
+// toFixed converts a probability in [0, 1) to a 64-bit fixed-point fraction.
+func toFixed(p float64) uint64 {
+	f := math.Ldexp(p, 64)
+	if f >= math.Ldexp(1, 64) {
+		return math.MaxUint64
+	}
+	return uint64(f)
+}
+
+// Len returns the number of items.
+func (s *WeightedSampler[T]) Len() int {
+	return len(s.items)
+}
+
+// SampleIndex returns the index of a randomly selected item.
+//
+// It returns an error only if the underlying reader fails.
+func (s *WeightedSampler[T]) SampleIndex() (int, error) {
+	r := s.readers.get()
+	defer s.readers.put(r)
+
+	col, err := r.Uint64n(uint64(len(s.items)))
+	if err != nil {
+		return 0, err
+	}
+	var x uint64
+	if s.total == 0 {
+		x, err = r.Uint64()
+	} else {
+		x, err = r.Uint64n(s.total)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if x < s.prob[col] {
+		return int(col), nil
+	}
+	return s.alias[col], nil
+}
+
+// Sample returns a randomly selected item.
+//
+// It returns an error only if the underlying reader fails.
+func (s *WeightedSampler[T]) Sample() (T, error) {
+	i, err := s.SampleIndex()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return s.items[i], nil
+}
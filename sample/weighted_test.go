@@ -0,0 +1,236 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package sample
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errRead = errors.New("read failed")
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) { return 0, errRead }
+
+// impliedWeights reconstructs, from the alias tables, the exact share of all outcomes
+// that selects each item, in units of 1/(n·total).
+func impliedWeights[T any](s *WeightedSampler[T]) []*big.Int {
+	total := new(big.Int).SetUint64(s.total)
+	if s.total == 0 {
+		total.Lsh(big.NewInt(1), 64)
+	}
+	out := make([]*big.Int, len(s.items))
+	for i := range out {
+		out[i] = new(big.Int)
+	}
+	for col := range s.items {
+		own := new(big.Int).SetUint64(s.prob[col])
+		if s.alias[col] == col {
+			own.Set(total)
+		}
+		out[col].Add(out[col], own)
+		out[s.alias[col]].Add(out[s.alias[col]], new(big.Int).Sub(total, own))
+	}
+	return out
+}
+
+// chiSquared returns the chi-squared statistic of counts against probabilities.
+func chiSquared(counts []int, probs []float64, n int) float64 {
+	var chi2 float64
+	for i, c := range counts {
+		exp := probs[i] * float64(n)
+		if exp == 0 {
+			continue
+		}
+		d := float64(c) - exp
+		chi2 += d * d / exp
+	}
+	return chi2
+}
+
+// TestWeightedSamplerInt_Exact verifies that the alias tables encode the integer weights
+// exactly: the outcomes selecting item i number exactly weights[i]·n.
+func TestWeightedSamplerInt_Exact(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	tests := [][]uint64{
+		{1},
+		{1, 1},
+		{95, 5},
+		{1, 2, 3, 4, 5, 6, 7},
+		{0, 10, 0, 1, 0},
+		{1 << 40, 1, 3, 1 << 20},
+		{math.MaxUint64 / 8, 1, 2, 3},
+	}
+	for _, weights := range tests {
+		items := make([]int, len(weights))
+		for i := range items {
+			items[i] = i
+		}
+		s, err := NewWeightedSamplerInt(items, weights)
+		is.NoError(err)
+		is.Equal(len(weights), s.Len())
+
+		n := big.NewInt(int64(len(weights)))
+		for i, got := range impliedWeights(s) {
+			want := new(big.Int).Mul(new(big.Int).SetUint64(weights[i]), n)
+			is.Zero(want.Cmp(got), "weights %v item %d: want %v, got %v", weights, i, want, got)
+		}
+	}
+}
+
+// TestWeightedSampler_FloatTables verifies that float weights are encoded to within
+// rounding error.
+func TestWeightedSampler_FloatTables(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	weights := []float64{0.1, 0.25, 3, 0, 1e-9, 7.5, 0.15}
+	s, err := NewWeightedSampler([]string{"a", "b", "c", "d", "e", "f", "g"}, weights)
+	is.NoError(err)
+
+	var sum float64
+	for _, w := range weights {
+		sum += w
+	}
+	unit := new(big.Float).SetInt(new(big.Int).Lsh(big.NewInt(int64(len(weights))), 64))
+	for i, got := range impliedWeights(s) {
+		p, _ := new(big.Float).Quo(new(big.Float).SetInt(got), unit).Float64()
+		is.InDelta(weights[i]/sum, p, 1e-12, "item %d", i)
+	}
+}
+
+// TestWeightedSampler_Distribution applies a chi-squared test to samples from both
+// constructors and checks that zero-weight items never occur.
+func TestWeightedSampler_Distribution(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	items := []string{"a", "b", "c", "d", "e"}
+	weights := []uint64{50, 25, 0, 20, 5}
+	probs := []float64{0.5, 0.25, 0, 0.2, 0.05}
+	fw := []float64{50, 25, 0, 20, 5}
+
+	si, err := NewWeightedSamplerInt(items, weights)
+	is.NoError(err)
+	sf, err := NewWeightedSampler(items, fw)
+	is.NoError(err)
+
+	const draws = 100000
+	for _, s := range []*WeightedSampler[string]{si, sf} {
+		counts := make([]int, len(items))
+		for i := 0; i < draws; i++ {
+			v, err := s.Sample()
+			is.NoError(err)
+			for j, it := range items {
+				if it == v {
+					counts[j]++
+				}
+			}
+		}
+		is.Zero(counts[2], "zero-weight item must never be sampled")
+		// 3 degrees of freedom; the critical value at p = 0.0001 is about 21.1.
+		is.Less(chiSquared(counts, probs, draws), 21.1)
+	}
+}
+
+// TestWeightedSampler_Concurrent samples from many goroutines; run with -race.
+func TestWeightedSampler_Concurrent(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	s, err := NewWeightedSamplerInt([]int{0, 1, 2}, []uint64{1, 2, 3})
+	is.NoError(err)
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		counts [3]int
+	)
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var local [3]int
+			for i := 0; i < 5000; i++ {
+				v, err := s.Sample()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				local[v]++
+			}
+			mu.Lock()
+			for i := range counts {
+				counts[i] += local[i]
+			}
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	is.Less(chiSquared(counts[:], []float64{1.0 / 6, 2.0 / 6, 3.0 / 6}, 80000), 18.4)
+}
+
+// TestWeightedSampler_Validation verifies that invalid inputs are rejected.
+func TestWeightedSampler_Validation(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	_, err := NewWeightedSampler([]int{}, []float64{})
+	is.ErrorIs(err, ErrNoItems)
+	_, err = NewWeightedSampler([]int{1, 2}, []float64{1})
+	is.ErrorIs(err, ErrWeightsLength)
+	_, err = NewWeightedSampler([]int{1, 2}, []float64{1, -1})
+	is.ErrorIs(err, ErrWeightInvalid)
+	_, err = NewWeightedSampler([]int{1, 2}, []float64{1, math.NaN()})
+	is.ErrorIs(err, ErrWeightInvalid)
+	_, err = NewWeightedSampler([]int{1, 2}, []float64{math.MaxFloat64, math.MaxFloat64})
+	is.ErrorIs(err, ErrWeightInvalid)
+	_, err = NewWeightedSampler([]int{1, 2}, []float64{0, 0})
+	is.ErrorIs(err, ErrWeightsZero)
+	_, err = NewWeightedSampler([]int{1}, []float64{1}, WithReader(nil))
+	is.ErrorIs(err, ErrNilReader)
+
+	_, err = NewWeightedSamplerInt([]int{}, []uint64{})
+	is.ErrorIs(err, ErrNoItems)
+	_, err = NewWeightedSamplerInt([]int{1}, []uint64{1, 2})
+	is.ErrorIs(err, ErrWeightsLength)
+	_, err = NewWeightedSamplerInt([]int{1, 2}, []uint64{0, 0})
+	is.ErrorIs(err, ErrWeightsZero)
+	_, err = NewWeightedSamplerInt([]int{1, 2}, []uint64{math.MaxUint64, 1})
+	is.ErrorIs(err, ErrWeightsOverflow)
+	_, err = NewWeightedSamplerInt([]int{1, 2, 3}, []uint64{math.MaxUint64 / 2, 1, 1})
+	is.ErrorIs(err, ErrWeightsOverflow)
+	_, err = NewWeightedSamplerInt([]int{1}, []uint64{1}, WithReader(nil))
+	is.ErrorIs(err, ErrNilReader)
+
+	s, err := NewWeightedSamplerInt([]int{1, 2}, []uint64{1, 1}, WithReader(errReader{}))
+	is.NoError(err)
+	_, err = s.Sample()
+	is.ErrorIs(err, errRead)
+}
+
+// TestWeightedSampler_CopiesInputs verifies that later changes to the caller's slices
+// do not affect the sampler.
+func TestWeightedSampler_CopiesInputs(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	items := []string{"only"}
+	s, err := NewWeightedSamplerInt(items, []uint64{1})
+	is.NoError(err)
+	items[0] = "changed"
+	v, err := s.Sample()
+	is.NoError(err)
+	is.Equal("only", v)
+}
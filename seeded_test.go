@@ -0,0 +1,113 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package prng
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/chacha20"
+)
+
+// Test_PRNG_SeededReader_KnownAnswer checks the output against the first ChaCha20 block
+// function test vector of RFC 8439, Appendix A.1: all-zero key, nonce and counter.
+func Test_PRNG_SeededReader_KnownAnswer(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	want, err := hex.DecodeString(
+		"76b8e0ada0f13d90405d6ae55386bd28bdd219b8a08ded1aa836efcc8b770dc7" +
+			"da41597c5157488d7724e03fb8d84a376a43b8f41518a11cc387b669b2ee6586")
+	is.NoError(err)
+
+	r, err := NewSeededReader(make([]byte, SeedSize))
+	is.NoError(err)
+
+	// Read in uneven pieces to exercise partial blocks.
+	got := make([]byte, len(want))
+	for _, span := range [][2]int{{0, 5}, {5, 37}, {37, 64}} {
+		n, err := r.Read(got[span[0]:span[1]])
+		is.NoError(err)
+		is.Equal(span[1]-span[0], n)
+	}
+	is.Equal(want, got)
+}
+
+// Test_PRNG_SeededReader_Deterministic verifies that equal seeds give equal streams,
+// different seeds differ, and the seed is copied.
+func Test_PRNG_SeededReader_Deterministic(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	seed := make([]byte, SeedSize)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	a, err := NewSeededReader(seed)
+	is.NoError(err)
+	b, err := NewSeededReader(seed)
+	is.NoError(err)
+	seed[0] ^= 1
+	c, err := NewSeededReader(seed)
+	is.NoError(err)
+
+	bufA, bufB, bufC := make([]byte, 1000), make([]byte, 1000), make([]byte, 1000)
+	_, _ = a.Read(bufA)
+	_, _ = b.Read(bufB)
+	_, _ = c.Read(bufC)
+	is.Equal(bufA, bufB)
+	is.NotEqual(bufA, bufC)
+
+	// Output does not depend on the previous contents of the buffer.
+	for i := range bufB {
+		bufB[i] = 0xaa
+	}
+	_, _ = a.Read(bufA)
+	_, _ = b.Read(bufB)
+	is.Equal(bufA, bufB)
+
+	for _, n := range []int{0, 16, SeedSize + 1} {
+		r, err := NewSeededReader(make([]byte, n))
+		is.ErrorIs(err, ErrSeedLength)
+		is.Nil(r)
+	}
+}
+
+// Test_PRNG_SeededReader_NonceRollover verifies that the stream continues with the next
+// nonce, instead of panicking, when the block counter is exhausted.
+func Test_PRNG_SeededReader_NonceRollover(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	seed := make([]byte, SeedSize)
+	seed[31] = 7
+	r, err := NewSeededReader(seed)
+	is.NoError(err)
+
+	// Jump to the last block under the first nonce.
+	r.cipher.SetCounter(1<<32 - 1)
+	r.pos = seededNonceSpan - 64
+
+	got := make([]byte, 128)
+	n, err := r.Read(got)
+	is.NoError(err)
+	is.Equal(128, n)
+
+	nonce := make([]byte, chacha20.NonceSize)
+	last, err := chacha20.NewUnauthenticatedCipher(seed, nonce)
+	is.NoError(err)
+	last.SetCounter(1<<32 - 1)
+	want := make([]byte, 128)
+	last.XORKeyStream(want[:64], want[:64])
+
+	nonce[4] = 1
+	next, err := chacha20.NewUnauthenticatedCipher(seed, nonce)
+	is.NoError(err)
+	next.XORKeyStream(want[64:], want[64:])
+
+	is.Equal(want, got)
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package prng
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	"golang.org/x/crypto/chacha20"
+)
+
+// ErrSelfTestFailed is wrapped by every error returned by SelfTest.
+var ErrSelfTestFailed = fmt.Errorf("prng: self-test failed")
+
+// Known-answer test vectors.
+var (
+	// RFC 8439 section 2.3.2: ChaCha20 block function with key 00..1f, the nonce below and
+	// block counter 1.
+	katChaChaNonce  = mustHex("000000090000004a00000000")
+	katChaChaOutput = mustHex("10f1e7e4d13b5915500fdd1fa32071c4c7d1f4c733c068030422aa9ac3d46c4e" +
+		"d2826446079faa0914c2d705d98b02a2b5129cd1de164eb9cbd083e8a2503c4e")
+
+	// draft-irtf-cfrg-xchacha-03 section 2.2.1: HChaCha20 with key 00..1f and the nonce
+	// below, which derives the XChaCha20 subkey.
+	katHChaChaNonce  = mustHex("000000090000004a0000000031415927")
+	katHChaChaOutput = mustHex("82413b4227b27bfed30e42508a877d73a0f9e4d58a74a853c12ec41326d3ecdc")
+
+	// draft-irtf-cfrg-xchacha-03 section A.3.2: XChaCha20 keystream with key 80..9f, the
+	// nonce below and block counter 1.
+	katXChaChaNonce  = mustHex("404142434445464748494a4b4c4d4e4f5051525354555658")
+	katXChaChaOutput = mustHex("29624b4b1b140ace53740e405b2168540fd7d630c1f536fecd722fc3cddba7f4" +
+		"cca98cf9e47e5e64d115450f9b125b54449ff76141ca620a1f9cfcab2a1a8a25")
+)
+
+// selfTestReadSizes are the Read lengths used to compare the zero-buffer and in-place
+// output paths. They straddle the 64-byte ChaCha20 block and the default zero buffer
+// size, so partial blocks and buffer growth are both exercised.
+var selfTestReadSizes = []int{1, 63, 64, 65, 7, 1000, 129}
+
+// mustHex decodes a hex constant, panicking on malformed input.
+func mustHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// sequentialKey returns a 32-byte key whose bytes count up from first.
+func sequentialKey(first byte) []byte {
+	key := make([]byte, chacha20.KeySize)
+	for i := range key {
+		key[i] = first + byte(i)
+	}
+	return key
+}
+
+// SelfTest runs the power-on self-test: known-answer tests of the ChaCha20 block
+// function (RFC 8439), HChaCha20 and the XChaCha20 keystream (draft-irtf-cfrg-xchacha),
+// followed by a check that the zero-buffer and in-place Read paths produce the same
+// output as the cipher for a fixed key and nonce.
+//
+// It returns nil on success and otherwise an error wrapping ErrSelfTestFailed that
+// names the failing check. NewReader runs it when WithSelfTest is enabled and the global
+// Reader runs it once, on its first Read; it can also be called directly, for example as
+// a periodic conditional test.
+func SelfTest() error {
+	c, err := chacha20.NewUnauthenticatedCipher(sequentialKey(0), katChaChaNonce)
+	if err != nil {
+		return fmt.Errorf("%w: ChaCha20: %w", ErrSelfTestFailed, err)
+	}
+	c.SetCounter(1)
+	if !keystreamEqual(c, katChaChaOutput) {
+		return fmt.Errorf("%w: ChaCha20 known answer mismatch", ErrSelfTestFailed)
+	}
+
+	subkey, err := chacha20.HChaCha20(sequentialKey(0), katHChaChaNonce)
+	if err != nil {
+		return fmt.Errorf("%w: HChaCha20: %w", ErrSelfTestFailed, err)
+	}
+	if !bytes.Equal(subkey, katHChaChaOutput) {
+		return fmt.Errorf("%w: HChaCha20 known answer mismatch", ErrSelfTestFailed)
+	}
+
+	c, err = chacha20.NewUnauthenticatedCipher(sequentialKey(0x80), katXChaChaNonce)
+	if err != nil {
+		return fmt.Errorf("%w: XChaCha20: %w", ErrSelfTestFailed, err)
+	}
+	c.SetCounter(1)
+	if !keystreamEqual(c, katXChaChaOutput) {
+		return fmt.Errorf("%w: XChaCha20 known answer mismatch", ErrSelfTestFailed)
+	}
+
+	return selfTestReadPaths()
+}
+
+// keystreamEqual reports whether the next len(want) keystream bytes of c equal want.
+func keystreamEqual(c *chacha20.Cipher, want []byte) bool {
+	got := make([]byte, len(want))
+	c.XORKeyStream(got, got)
+	return bytes.Equal(got, want)
+}
+
+// selfTestReadPaths seeds one instance with the zero buffer enabled and one with in-place
+// XOR from the same fixed key and nonce, and verifies that both produce the keystream of
+// a cipher constructed directly from that key and nonce, across reads of varied sizes.
+func selfTestReadPaths() error {
+	seed := append(sequentialKey(0x80), katXChaChaNonce...)
+	ref, err := chacha20.NewUnauthenticatedCipher(seed[:chacha20.KeySize], seed[chacha20.KeySize:])
+	if err != nil {
+		return fmt.Errorf("%w: read paths: %w", ErrSelfTestFailed, err)
+	}
+
+	zeroBuf, err := selfTestInstance(seed, true)
+	if err != nil {
+		return err
+	}
+	inPlace, err := selfTestInstance(seed, false)
+	if err != nil {
+		return err
+	}
+
+	for _, n := range selfTestReadSizes {
+		want := make([]byte, n)
+		ref.XORKeyStream(want, want)
+
+		got := make([]byte, n)
+		if _, err := zeroBuf.Read(got); err != nil || !bytes.Equal(got, want) {
+			return fmt.Errorf("%w: zero-buffer output differs from the keystream", ErrSelfTestFailed)
+		}
+		clear(got)
+		if _, err := inPlace.Read(got); err != nil || !bytes.Equal(got, want) {
+			return fmt.Errorf("%w: in-place output differs from the keystream", ErrSelfTestFailed)
+		}
+	}
+	return nil
+}
+
+// selfTestInstance returns a prng seeded from seed (key then nonce) through the same
+// construction path as pool instances, owned by a private single-shard reader.
+func selfTestInstance(seed []byte, zeroBuffer bool) (*prng, error) {
+	cfg := DefaultConfig()
+	cfg.Shards = 1
+	cfg.UseZeroBuffer = zeroBuffer
+	owner := newReader(&cfg)
+	owner.entropy = bytes.NewReader(seed)
+
+	p, err := newPRNG(owner, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: read paths: %w", ErrSelfTestFailed, err)
+	}
+	return p, nil
+}
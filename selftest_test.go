@@ -0,0 +1,117 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package prng
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_SelfTest_Pass verifies that the self-test passes and that NewReader runs it when
+// enabled.
+func Test_SelfTest_Pass(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	is.NoError(SelfTest())
+
+	rdr, err := NewReader(WithSelfTest(true))
+	is.NoError(err)
+	is.True(rdr.Config().SelfTest)
+	buf := make([]byte, 32)
+	_, err = rdr.Read(buf)
+	is.NoError(err)
+
+	// The global Reader has run its deferred self-test by now.
+	_, err = Reader.Read(buf)
+	is.NoError(err)
+	is.NoError(Reader.(*reader).selfTest())
+}
+
+// Test_SelfTest_KnownAnswerMismatch corrupts each known answer in turn and verifies that
+// SelfTest and NewReader report the failing check. It mutates package state, so it does
+// not run in parallel.
+func Test_SelfTest_KnownAnswerMismatch(t *testing.T) {
+	is := assert.New(t)
+
+	for name, vector := range map[string][]byte{
+		"ChaCha20":  katChaChaOutput,
+		"HChaCha20": katHChaChaOutput,
+		"XChaCha20": katXChaChaOutput,
+	} {
+		vector[len(vector)-1] ^= 1
+		err := SelfTest()
+		is.ErrorIs(err, ErrSelfTestFailed, name)
+		is.ErrorContains(err, name+" known answer mismatch")
+
+		rdr, nerr := NewReader(WithSelfTest(true))
+		is.ErrorIs(nerr, ErrSelfTestFailed, name)
+		is.Nil(rdr)
+
+		// Without the option, construction does not run the self-test.
+		_, nerr = NewReader()
+		is.NoError(nerr, name)
+		vector[len(vector)-1] ^= 1
+	}
+	is.NoError(SelfTest())
+}
+
+// Test_SelfTest_ReadPaths verifies that instances built for the output path check
+// produce identical output for the zero-buffer and in-place paths.
+func Test_SelfTest_ReadPaths(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	seed := append(sequentialKey(0x80), katXChaChaNonce...)
+	zeroBuf, err := selfTestInstance(seed, true)
+	is.NoError(err)
+	inPlace, err := selfTestInstance(seed, false)
+	is.NoError(err)
+
+	a := make([]byte, 4096)
+	b := make([]byte, 4096)
+	_, err = zeroBuf.Read(a)
+	is.NoError(err)
+	_, err = inPlace.Read(b)
+	is.NoError(err)
+	is.Equal(a, b)
+	is.NotEqual(make([]byte, 4096), a)
+
+	// A seed too short for a key and nonce fails as a self-test error.
+	_, err = selfTestInstance(seed[:10], true)
+	is.ErrorIs(err, ErrSelfTestFailed)
+	is.ErrorIs(err, io.ErrUnexpectedEOF)
+}
+
+// Test_SelfTest_Deferred verifies that a reader with a deferred self-test refuses to
+// produce output, through Read and held-instance helpers alike, once the test fails.
+func Test_SelfTest_Deferred(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	rdr, err := NewReader(WithShards(1))
+	is.NoError(err)
+	r := rdr.(*reader)
+	calls := 0
+	r.selfTest = func() error {
+		calls++
+		return fmt.Errorf("%w: injected", ErrSelfTestFailed)
+	}
+
+	buf := make([]byte, 16)
+	n, err := r.Read(buf)
+	is.Zero(n)
+	is.ErrorIs(err, ErrSelfTestFailed)
+	is.Equal(make([]byte, 16), buf)
+
+	err = withHeld(r, func(io.Reader) error { return nil })
+	is.ErrorIs(err, ErrSelfTestFailed)
+	is.Equal(2, calls)
+	is.Zero(r.Stats().BytesGenerated)
+}
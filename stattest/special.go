@@ -0,0 +1,174 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package stattest
+
+import (
+	"math"
+	"math/bits"
+	"math/cmplx"
+)
+
+const (
+	// gammaEpsilon is the relative tolerance of the incomplete gamma evaluation.
+	gammaEpsilon = 1e-15
+
+	// gammaMaxIterations bounds the series and continued fraction; both converge in
+	// O(sqrt(a)) steps near x = a, far fewer than this for the parameters used here.
+	gammaMaxIterations = 1 << 20
+)
+
+// normal returns the standard normal cumulative distribution function at x.
+func normal(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+// igamc returns the regularized upper incomplete gamma function Q(a, x), the survival
+// function of a chi-squared statistic 2x with 2a degrees of freedom. It follows
+// Numerical Recipes: a series for x < a+1 and a continued fraction otherwise.
+func igamc(a, x float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	if math.IsInf(x, 1) {
+		return 0
+	}
+	lg, _ := math.Lgamma(a)
+	scale := math.Exp(-x + a*math.Log(x) - lg)
+	if x < a+1 {
+		return 1 - scale*gammaSeries(a, x)
+	}
+	return scale * gammaFraction(a, x)
+}
+
+// gammaSeries returns the series Σ x^n / (a(a+1)...(a+n)) used for P(a, x).
+func gammaSeries(a, x float64) float64 {
+	ap := a
+	del := 1 / a
+	sum := del
+	for i := 0; i < gammaMaxIterations; i++ {
+		ap++
+		del *= x / ap
+		sum += del
+		if math.Abs(del) < math.Abs(sum)*gammaEpsilon {
+			break
+		}
+	}
+	return sum
+}
+
+// gammaFraction evaluates the continued fraction for Q(a, x) with the modified Lentz
+// method.
+func gammaFraction(a, x float64) float64 {
+	const tiny = 1e-300
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for i := 1; i < gammaMaxIterations; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		del := d * c
+		h *= del
+		if math.Abs(del-1) < gammaEpsilon {
+			break
+		}
+	}
+	return h
+}
+
+// dft returns the discrete Fourier transform of x, X_k = Σ x_j·e^(-2πi·jk/n), for any
+// length: powers of two use a radix-2 FFT directly, other lengths Bluestein's algorithm
+// on top of it.
+func dft(x []complex128) []complex128 {
+	n := len(x)
+	if n&(n-1) == 0 {
+		out := make([]complex128, n)
+		copy(out, x)
+		fft(out, false)
+		return out
+	}
+
+	// Bluestein: jk = (j² + k² - (k-j)²)/2 turns the transform into a convolution with
+	// the chirp w_j = e^(-πi·j²/n), computed with power-of-two FFTs of length m >= 2n-1.
+	m := 1 << bits.Len(uint(2*n-1))
+	chirp := make([]complex128, n)
+	for j := range chirp {
+		// j² mod 2n keeps the angle small and exact.
+		jj := (uint64(j) * uint64(j)) % uint64(2*n)
+		sin, cos := math.Sincos(-math.Pi * float64(jj) / float64(n))
+		chirp[j] = complex(cos, sin)
+	}
+
+	a := make([]complex128, m)
+	b := make([]complex128, m)
+	for j := 0; j < n; j++ {
+		a[j] = x[j] * chirp[j]
+		b[j] = cmplx.Conj(chirp[j])
+		if j > 0 {
+			b[m-j] = b[j]
+		}
+	}
+	fft(a, false)
+	fft(b, false)
+	for i := range a {
+		a[i] *= b[i]
+	}
+	fft(a, true)
+
+	out := make([]complex128, n)
+	scale := complex(1/float64(m), 0)
+	for k := range out {
+		out[k] = a[k] * scale * chirp[k]
+	}
+	return out
+}
+
+// fft transforms x in place with an iterative radix-2 Cooley-Tukey FFT; len(x) must be a
+// power of two. The inverse transform is unscaled.
+func fft(x []complex128, inverse bool) {
+	n := len(x)
+	if n <= 1 {
+		return
+	}
+
+	shift := bits.UintSize - bits.Len(uint(n-1))
+	for i := range x {
+		j := int(bits.Reverse(uint(i)) >> shift)
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+
+	sign := -1.0
+	if inverse {
+		sign = 1
+	}
+	// Twiddle factors for the largest stage; smaller stages use every (n/size)-th one.
+	twiddle := make([]complex128, n/2)
+	for k := range twiddle {
+		sin, cos := math.Sincos(sign * 2 * math.Pi * float64(k) / float64(n))
+		twiddle[k] = complex(cos, sin)
+	}
+	for size := 2; size <= n; size <<= 1 {
+		half, step := size/2, n/size
+		for start := 0; start < n; start += size {
+			for k := 0; k < half; k++ {
+				t := twiddle[k*step] * x[start+k+half]
+				x[start+k+half] = x[start+k] - t
+				x[start+k] += t
+			}
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package stattest implements statistical tests for random bit sequences from NIST
+// SP 800-22 Rev. 1a, "A Statistical Test Suite for Random and Pseudorandom Number
+// Generators for Cryptographic Applications".
+//
+// The following tests are provided, each returning one or two p-values:
+//
+//   - Frequency (monobit), section 2.1
+//   - BlockFrequency (frequency within a block), section 2.2
+//   - Runs, section 2.3
+//   - LongestRun (longest run of ones in a block), section 2.4
+//   - Spectral (discrete Fourier transform), section 2.6
+//   - Serial, section 2.11
+//   - ApproximateEntropy, section 2.12
+//   - CumulativeSums, section 2.13
+//
+// A sequence passes a test at significance level alpha when its p-value is at least
+// alpha. At alpha = 0.01, about one good sequence in a hundred fails any given test, so
+// a single failure says little. Run draws one sequence from a reader and runs every test;
+// Summarize applies the suite's section 4.2 criteria to many such reports: the
+// proportion of sequences passing each test, and the uniformity of its p-values.
+//
+// Example:
+//
+//	var reports []*stattest.Report
+//	for i := 0; i < 100; i++ {
+//	    report, err := stattest.Run(stattest.WithReader(prng.Reader))
+//	    if err != nil {
+//	        // handle error
+//	    }
+//	    reports = append(reports, report)
+//	}
+//	summaries, err := stattest.Summarize(reports)
+package stattest
+
+import (
+	"fmt"
+	"io"
+)
+
+var (
+	ErrSequenceEmpty        = fmt.Errorf("stattest: sequence must not be empty")
+	ErrSequenceTooShort     = fmt.Errorf("stattest: sequence is too short for the test")
+	ErrInvalidBit           = fmt.Errorf("stattest: sequence must contain only the bits 0 and 1")
+	ErrBlockSizeInvalid     = fmt.Errorf("stattest: block size must be greater than zero")
+	ErrPatternLengthInvalid = fmt.Errorf("stattest: pattern length is out of range")
+)
+
+// Sequence is a bit sequence under test, one bit per element, each 0 or 1.
+type Sequence []byte
+
+// ReadSequence reads n bits from r, most significant bit of each byte first, the
+// order used by the SP 800-22 reference implementation for binary input.
+func ReadSequence(r io.Reader, n int) (Sequence, error) {
+	if n <= 0 {
+		return nil, ErrSequenceEmpty
+	}
+
+	buf := make([]byte, (n+7)/8)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	s := make(Sequence, n)
+	for i := range s {
+		s[i] = buf[i/8] >> (7 - uint(i%8)) & 1
+	}
+	return s, nil
+}
+
+// ParseSequence parses a string of '0' and '1' characters, such as the examples in
+// SP 800-22.
+func ParseSequence(bits string) (Sequence, error) {
+	if len(bits) == 0 {
+		return nil, ErrSequenceEmpty
+	}
+
+	s := make(Sequence, len(bits))
+	for i := 0; i < len(bits); i++ {
+		switch bits[i] {
+		case '0':
+		case '1':
+			s[i] = 1
+		default:
+			return nil, ErrInvalidBit
+		}
+	}
+	return s, nil
+}
+
+// ones returns the number of one bits in s.
+func (s Sequence) ones() int {
+	n := 0
+	for _, b := range s {
+		n += int(b)
+	}
+	return n
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package stattest
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/sixafter/prng-chacha"
+)
+
+// BenchmarkStattest_Spectral measures the DFT test on a power-of-two length, which uses
+// the FFT directly, and on the default length, which goes through Bluestein's algorithm.
+func BenchmarkStattest_Spectral(b *testing.B) {
+	for _, n := range []int{1 << 20, 1_000_000} {
+		s, err := ReadSequence(prng.Reader, n)
+		if err != nil {
+			b.Fatalf("ReadSequence failed: %v", err)
+		}
+		b.Run("Bits_"+strconv.Itoa(n), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				_, _ = Spectral(s)
+			}
+		})
+	}
+}
+
+// BenchmarkStattest_Run measures the full suite on a 100,000-bit sequence.
+func BenchmarkStattest_Run(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		_, _ = Run(WithBits(100_000))
+	}
+}
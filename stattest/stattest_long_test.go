@@ -0,0 +1,60 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+//go:build stattest
+
+package stattest
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/sixafter/prng-chacha"
+	"github.com/stretchr/testify/assert"
+)
+
+// sequences is the number of 1,000,000-bit sequences assessed, enough for the section
+// 4.2.2 uniformity check (which needs at least 55).
+const sequences = 100
+
+// TestReader_SP80022 assesses prng.Reader as SP 800-22 section 4.2 prescribes: every
+// test must pass on an acceptable proportion of sequences, with uniformly distributed
+// p-values. It reads 12.5 MB and takes about two minutes per
+// core; run it with
+//
+//	go test -tags stattest -run TestReader_SP80022 -timeout 30m ./stattest
+//
+// or make test-stattest.
+func TestReader_SP80022(t *testing.T) {
+	is := assert.New(t)
+
+	reports := make([]*Report, sequences)
+	errs := make([]error, sequences)
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, runtime.GOMAXPROCS(0))
+	for i := range reports {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			reports[i], errs[i] = Run(WithReader(prng.Reader))
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if !is.NoError(err) {
+			return
+		}
+	}
+
+	summaries, err := Summarize(reports)
+	is.NoError(err)
+	for _, s := range summaries {
+		t.Logf("%-22s %3d/%d passed (min %.4f), uniformity p = %.6f", s.Name, s.Passed, s.Sequences, s.MinProportion, s.Uniformity)
+		is.True(s.Acceptable, "%s: proportion %.4f, uniformity %.6f", s.Name, s.Proportion, s.Uniformity)
+	}
+}
@@ -0,0 +1,263 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package stattest
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"github.com/sixafter/prng-chacha"
+	"github.com/stretchr/testify/assert"
+)
+
+// piBits is the 100-bit example sequence, the binary expansion of π, used throughout
+// SP 800-22 section 2.
+const piBits = "1100100100001111110110101010001000100001011010001100001000110100110001001100011001100010100010111000"
+
+// tolerance matches the six significant digits printed in SP 800-22.
+const tolerance = 1e-6
+
+var errRead = errors.New("read failed")
+
+// errReader fails every Read.
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) { return 0, errRead }
+
+// mustParse parses a bit string or fails the test.
+func mustParse(t *testing.T, bits string) Sequence {
+	t.Helper()
+	s, err := ParseSequence(bits)
+	if err != nil {
+		t.Fatalf("ParseSequence failed: %v", err)
+	}
+	return s
+}
+
+// TestKnownAnswers checks every test against the worked examples in SP 800-22 Rev. 1a
+// section 2.
+func TestKnownAnswers(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+	pi := mustParse(t, piBits)
+
+	p, err := Frequency(mustParse(t, "1011010101"))
+	is.NoError(err)
+	is.InDelta(0.527089, p, tolerance)
+	p, err = Frequency(pi)
+	is.NoError(err)
+	is.InDelta(0.109599, p, tolerance)
+
+	p, err = BlockFrequency(mustParse(t, "0110011010"), 3)
+	is.NoError(err)
+	is.InDelta(0.801252, p, tolerance)
+	p, err = BlockFrequency(pi, 10)
+	is.NoError(err)
+	is.InDelta(0.706438, p, tolerance)
+
+	p, err = Runs(mustParse(t, "1001101011"))
+	is.NoError(err)
+	is.InDelta(0.147232, p, tolerance)
+	p, err = Runs(pi)
+	is.NoError(err)
+	is.InDelta(0.500798, p, tolerance)
+
+	p, err = LongestRun(mustParse(t, "11001100000101010110110001001100111000000000001001001101010100010001001111010110100000001101011111001100111001101101100010110010"))
+	is.NoError(err)
+	// The example uses unrounded category probabilities; the four-digit table of section
+	// 3.4, shared with the reference implementation, moves the result by about 1e-5.
+	is.InDelta(0.180609, p, 2e-5)
+
+	// The p-values printed in section 2.6.8 (0.029523 and 0.168669) do not follow from the
+	// steps of section 2.6.4, which count the moduli of the first n/2 DFT terms below
+	// T = sqrt(ln(20)·n). Following those steps by hand: for "1001010011" the moduli are
+	// 0, 2, √20, 2 and √20, all below T ≈ 5.47, so N1 = 5, N0 = 4.75 and d ≈ 0.7255; for
+	// the π sequence only terms 21 and 26 reach T ≈ 17.31, so N1 = 48, N0 = 47.5 and
+	// d ≈ 0.4588.
+	p, err = Spectral(mustParse(t, "1001010011"))
+	is.NoError(err)
+	is.InDelta(math.Erfc(0.25/math.Sqrt(10*0.95*0.05/4)/math.Sqrt2), p, 1e-12)
+	is.InDelta(0.468160, p, tolerance)
+	p, err = Spectral(pi)
+	is.NoError(err)
+	is.InDelta(math.Erfc(0.5/math.Sqrt(100*0.95*0.05/4)/math.Sqrt2), p, 1e-12)
+	is.InDelta(0.646355, p, tolerance)
+
+	p1, p2, err := Serial(mustParse(t, "0011011101"), 3)
+	is.NoError(err)
+	is.InDelta(0.808792, p1, tolerance)
+	is.InDelta(0.670320, p2, tolerance)
+
+	p, err = ApproximateEntropy(mustParse(t, "0100110101"), 3)
+	is.NoError(err)
+	is.InDelta(0.261961, p, tolerance)
+	p, err = ApproximateEntropy(pi, 2)
+	is.NoError(err)
+	is.InDelta(0.235301, p, tolerance)
+
+	fwd, _, err := CumulativeSums(mustParse(t, "1011010111"))
+	is.NoError(err)
+	is.InDelta(0.411658, fwd, tolerance)
+	fwd, rev, err := CumulativeSums(pi)
+	is.NoError(err)
+	is.InDelta(0.219194, fwd, tolerance)
+	is.InDelta(0.114866, rev, tolerance)
+}
+
+// TestDFT compares the FFT and Bluestein paths with a direct O(n²) transform.
+func TestDFT(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	for _, n := range []int{1, 2, 8, 10, 37, 64, 100} {
+		x := make([]complex128, n)
+		for i := range x {
+			x[i] = complex(float64(i%3)-1, 0)
+		}
+		got := dft(x)
+		for k := 0; k < n; k++ {
+			var want complex128
+			for j := 0; j < n; j++ {
+				want += x[j] * cmplx.Exp(complex(0, -2*math.Pi*float64(j*k)/float64(n)))
+			}
+			is.InDelta(0, cmplx.Abs(got[k]-want), 1e-9, "n=%d k=%d", n, k)
+		}
+	}
+}
+
+// TestIgamc checks the incomplete gamma function against closed forms: Q(1, x) = e^-x
+// and Q(1/2, x) = erfc(sqrt(x)), including large shape parameters where the chi-squared
+// mean gives Q close to one half.
+func TestIgamc(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	for _, x := range []float64{0.01, 0.5, 1, 2, 10, 50} {
+		is.InDelta(math.Exp(-x), igamc(1, x), 1e-12)
+		is.InDelta(math.Erfc(math.Sqrt(x)), igamc(0.5, x), 1e-12)
+	}
+	is.Equal(1.0, igamc(3, 0))
+	is.InDelta(0.5, igamc(16384, 16384), 0.01)
+}
+
+// TestSequence verifies bit order and parsing errors.
+func TestSequence(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	s, err := ReadSequence(bytes.NewReader([]byte{0xa5, 0x80}), 10)
+	is.NoError(err)
+	is.Equal(Sequence{1, 0, 1, 0, 0, 1, 0, 1, 1, 0}, s)
+
+	_, err = ReadSequence(bytes.NewReader([]byte{0xff}), 9)
+	is.Error(err)
+	_, err = ReadSequence(bytes.NewReader(nil), 0)
+	is.ErrorIs(err, ErrSequenceEmpty)
+	_, err = ParseSequence("0102")
+	is.ErrorIs(err, ErrInvalidBit)
+	_, err = ParseSequence("")
+	is.ErrorIs(err, ErrSequenceEmpty)
+}
+
+// TestArguments verifies argument validation of the individual tests.
+func TestArguments(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+	pi := mustParse(t, piBits)
+
+	_, err := Frequency(nil)
+	is.ErrorIs(err, ErrSequenceEmpty)
+	_, err = BlockFrequency(pi, 0)
+	is.ErrorIs(err, ErrBlockSizeInvalid)
+	_, err = BlockFrequency(pi, 101)
+	is.ErrorIs(err, ErrSequenceTooShort)
+	_, err = LongestRun(pi)
+	is.ErrorIs(err, ErrSequenceTooShort)
+	_, _, err = Serial(pi, 1)
+	is.ErrorIs(err, ErrPatternLengthInvalid)
+	_, err = ApproximateEntropy(pi, MaxPatternLength)
+	is.ErrorIs(err, ErrPatternLengthInvalid)
+	_, _, err = CumulativeSums(nil)
+	is.ErrorIs(err, ErrSequenceEmpty)
+	_, err = Spectral(nil)
+	is.ErrorIs(err, ErrSequenceEmpty)
+	_, err = Runs(nil)
+	is.ErrorIs(err, ErrSequenceEmpty)
+}
+
+// TestRun verifies the report produced for a short sequence from the default reader,
+// that a constant source fails, and configuration errors.
+func TestRun(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	rdr, err := prng.NewReader()
+	is.NoError(err)
+	report, err := Run(WithReader(rdr), WithBits(1<<14), WithSerialLength(8), WithEntropyLength(5))
+	is.NoError(err)
+	is.Equal(1<<14, report.Bits)
+	is.Len(report.Results, 10)
+	for _, res := range report.Results {
+		is.True(res.PValue >= 0 && res.PValue <= 1, "%s: %v", res.Name, res.PValue)
+		is.Equal(res.PValue >= 0.01, res.Passed)
+	}
+	is.Equal(uint64(1<<14/8), rdr.(prng.StatsReporter).Stats().BytesGenerated)
+
+	stuck, err := Run(WithReader(bytes.NewReader(make([]byte, 1<<11))), WithBits(1<<14), WithSerialLength(8), WithEntropyLength(5))
+	is.NoError(err)
+	is.False(stuck.Passed())
+
+	_, err = Run(WithReader(nil))
+	is.ErrorIs(err, ErrNilReader)
+	_, err = Run(WithBits(100))
+	is.ErrorIs(err, ErrBitsInvalid)
+	_, err = Run(WithAlpha(1))
+	is.ErrorIs(err, ErrAlphaInvalid)
+	_, err = Run(WithReader(errReader{}))
+	is.ErrorIs(err, errRead)
+	_, err = Run(WithBits(1<<10), WithSerialLength(MaxPatternLength+1))
+	is.ErrorIs(err, ErrPatternLengthInvalid)
+}
+
+// TestSummarize verifies the proportion and uniformity criteria on synthetic reports.
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	// 100 p-values spread evenly: all pass at α = 0.01 and are perfectly uniform.
+	var reports []*Report
+	for i := 0; i < 100; i++ {
+		p := (float64(i) + 0.5) / 100
+		reports = append(reports, &Report{Bits: 128, Alpha: 0.01, Results: []Result{
+			{Name: NameFrequency, PValue: p, Passed: p >= 0.01},
+			{Name: NameRuns, PValue: p / 100, Passed: p/100 >= 0.01},
+		}})
+	}
+	summaries, err := Summarize(reports)
+	is.NoError(err)
+	is.Len(summaries, 2)
+
+	is.Equal(NameFrequency, summaries[0].Name)
+	is.Equal(100, summaries[0].Sequences)
+	is.Equal(99, summaries[0].Passed)
+	is.InDelta(0.99-3*math.Sqrt(0.01*0.99/100), summaries[0].MinProportion, 1e-12)
+	is.InDelta(1, summaries[0].Uniformity, 1e-9)
+	is.True(summaries[0].Acceptable)
+
+	// Every p-value in the first bin fails both criteria.
+	is.Equal(NameRuns, summaries[1].Name)
+	is.Less(summaries[1].Uniformity, 0.0001)
+	is.False(summaries[1].Acceptable)
+
+	_, err = Summarize(nil)
+	is.ErrorIs(err, ErrNoReports)
+	reports[1] = &Report{Alpha: 0.05, Results: reports[0].Results}
+	_, err = Summarize(reports)
+	is.ErrorIs(err, ErrReportMismatch)
+}
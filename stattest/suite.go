@@ -0,0 +1,316 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package stattest
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/sixafter/prng-chacha"
+)
+
+var (
+	ErrBitsInvalid    = fmt.Errorf("stattest: sequence length must be at least 128 bits")
+	ErrAlphaInvalid   = fmt.Errorf("stattest: significance level must be between 0 and 1")
+	ErrNoReports      = fmt.Errorf("stattest: no reports to summarize")
+	ErrReportMismatch = fmt.Errorf("stattest: reports must come from the same configuration")
+	ErrNilReader      = fmt.Errorf("stattest: reader must not be nil")
+)
+
+// Test names used in Result.Name.
+const (
+	NameFrequency             = "Frequency"
+	NameBlockFrequency        = "BlockFrequency"
+	NameRuns                  = "Runs"
+	NameLongestRun            = "LongestRun"
+	NameSpectral              = "Spectral"
+	NameSerial1               = "Serial1"
+	NameSerial2               = "Serial2"
+	NameApproximateEntropy    = "ApproximateEntropy"
+	NameCumulativeSumsForward = "CumulativeSumsForward"
+	NameCumulativeSumsReverse = "CumulativeSumsReverse"
+)
+
+// Config defines the parameters of a Run. The defaults follow the SP 800-22 reference
+// implementation.
+type Config struct {
+	// Reader is the source under test. Defaults to prng.Reader.
+	Reader io.Reader
+
+	// Bits is the length of the sequence read for each Run. Defaults to 1,000,000.
+	Bits int
+
+	// BlockSize is the BlockFrequency block length in bits. Defaults to 128.
+	BlockSize int
+
+	// SerialLength is the Serial pattern length. Defaults to 16.
+	SerialLength int
+
+	// EntropyLength is the ApproximateEntropy pattern length. Defaults to 10.
+	EntropyLength int
+
+	// Alpha is the significance level: a p-value below it fails. Defaults to 0.01.
+	Alpha float64
+}
+
+// Option defines a functional option for customizing a Run's Config.
+type Option func(*Config)
+
+// WithReader returns an Option that sets the source under test.
+func WithReader(r io.Reader) Option {
+	return func(cfg *Config) {
+		cfg.Reader = r
+	}
+}
+
+// WithBits returns an Option that sets the sequence length in bits.
+func WithBits(n int) Option {
+	return func(cfg *Config) {
+		cfg.Bits = n
+	}
+}
+
+// WithBlockSize returns an Option that sets the BlockFrequency block length.
+func WithBlockSize(m int) Option {
+	return func(cfg *Config) {
+		cfg.BlockSize = m
+	}
+}
+
+// WithSerialLength returns an Option that sets the Serial pattern length.
+func WithSerialLength(m int) Option {
+	return func(cfg *Config) {
+		cfg.SerialLength = m
+	}
+}
+
+// WithEntropyLength returns an Option that sets the ApproximateEntropy pattern length.
+func WithEntropyLength(m int) Option {
+	return func(cfg *Config) {
+		cfg.EntropyLength = m
+	}
+}
+
+// WithAlpha returns an Option that sets the significance level.
+func WithAlpha(alpha float64) Option {
+	return func(cfg *Config) {
+		cfg.Alpha = alpha
+	}
+}
+
+// newConfig applies opts to the default Config and validates the result. Pattern and
+// block lengths are validated by the tests themselves.
+func newConfig(opts []Option) (Config, error) {
+	cfg := Config{
+		Reader:        prng.Reader,
+		Bits:          1_000_000,
+		BlockSize:     128,
+		SerialLength:  16,
+		EntropyLength: 10,
+		Alpha:         0.01,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.Reader == nil {
+		return cfg, ErrNilReader
+	}
+	if cfg.Bits < 128 {
+		return cfg, ErrBitsInvalid
+	}
+	if !(cfg.Alpha > 0 && cfg.Alpha < 1) {
+		return cfg, ErrAlphaInvalid
+	}
+	return cfg, nil
+}
+
+// Result is the outcome of one test on one sequence.
+type Result struct {
+	// Name identifies the test; tests with two p-values produce two Results.
+	Name string
+
+	// PValue is the probability that a random sequence would look at least as
+	// non-random as this one.
+	PValue float64
+
+	// Passed reports whether PValue is at least the significance level.
+	Passed bool
+}
+
+// Report holds the results of every test on one sequence.
+type Report struct {
+	// Bits is the length of the sequence tested.
+	Bits int
+
+	// Alpha is the significance level applied.
+	Alpha float64
+
+	// Results lists one entry per p-value, in a fixed order.
+	Results []Result
+}
+
+// Passed reports whether every test passed.
+func (r *Report) Passed() bool {
+	for _, res := range r.Results {
+		if !res.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Run reads one sequence from the configured reader and runs every test on it.
+//
+// It returns ErrNilReader, ErrBitsInvalid or ErrAlphaInvalid for an invalid
+// configuration, the reader's error if it fails, and the error of any test whose
+// parameters do not suit the sequence length.
+func Run(opts ...Option) (*Report, error) {
+	cfg, err := newConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	s, err := ReadSequence(cfg.Reader, cfg.Bits)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		Bits:    cfg.Bits,
+		Alpha:   cfg.Alpha,
+		Results: make([]Result, 0, 10),
+	}
+	add := func(name string, p float64) {
+		report.Results = append(report.Results, Result{Name: name, PValue: p, Passed: p >= cfg.Alpha})
+	}
+
+	p, err := Frequency(s)
+	if err != nil {
+		return nil, err
+	}
+	add(NameFrequency, p)
+
+	if p, err = BlockFrequency(s, cfg.BlockSize); err != nil {
+		return nil, err
+	}
+	add(NameBlockFrequency, p)
+
+	if p, err = Runs(s); err != nil {
+		return nil, err
+	}
+	add(NameRuns, p)
+
+	if p, err = LongestRun(s); err != nil {
+		return nil, err
+	}
+	add(NameLongestRun, p)
+
+	if p, err = Spectral(s); err != nil {
+		return nil, err
+	}
+	add(NameSpectral, p)
+
+	p1, p2, err := Serial(s, cfg.SerialLength)
+	if err != nil {
+		return nil, err
+	}
+	add(NameSerial1, p1)
+	add(NameSerial2, p2)
+
+	if p, err = ApproximateEntropy(s, cfg.EntropyLength); err != nil {
+		return nil, err
+	}
+	add(NameApproximateEntropy, p)
+
+	fwd, rev, err := CumulativeSums(s)
+	if err != nil {
+		return nil, err
+	}
+	add(NameCumulativeSumsForward, fwd)
+	add(NameCumulativeSumsReverse, rev)
+
+	return report, nil
+}
+
+// Summary applies the SP 800-22 section 4.2 criteria to one test across many sequences.
+type Summary struct {
+	// Name identifies the test.
+	Name string
+
+	// Sequences is the number of sequences tested and Passed the number that passed.
+	Sequences, Passed int
+
+	// Proportion is Passed / Sequences, and MinProportion the lower bound of its
+	// acceptable range: (1 - α) - 3·sqrt(α(1 - α)/Sequences).
+	Proportion, MinProportion float64
+
+	// Uniformity is the p-value of a chi-squared test that the p-values are uniform over
+	// ten equal bins. It is only meaningful for 55 or more sequences.
+	Uniformity float64
+
+	// Acceptable reports whether Proportion >= MinProportion and Uniformity >= 0.0001.
+	Acceptable bool
+}
+
+// Summarize aggregates reports from Run, which must share a configuration, into one
+// Summary per test in report order.
+//
+// It returns ErrNoReports if reports is empty and ErrReportMismatch if the reports list
+// different tests or significance levels.
+func Summarize(reports []*Report) ([]Summary, error) {
+	if len(reports) == 0 {
+		return nil, ErrNoReports
+	}
+	first := reports[0]
+	for _, r := range reports[1:] {
+		if r.Alpha != first.Alpha || len(r.Results) != len(first.Results) {
+			return nil, ErrReportMismatch
+		}
+		for i := range r.Results {
+			if r.Results[i].Name != first.Results[i].Name {
+				return nil, ErrReportMismatch
+			}
+		}
+	}
+
+	alpha := first.Alpha
+	count := float64(len(reports))
+	minProportion := (1 - alpha) - 3*math.Sqrt(alpha*(1-alpha)/count)
+
+	summaries := make([]Summary, len(first.Results))
+	for i := range summaries {
+		var bins [10]int
+		passed := 0
+		for _, r := range reports {
+			res := r.Results[i]
+			if res.Passed {
+				passed++
+			}
+			bins[min(int(res.PValue*10), 9)]++
+		}
+
+		expected := count / 10
+		var chi2 float64
+		for _, c := range bins {
+			d := float64(c) - expected
+			chi2 += d * d / expected
+		}
+		uniformity := igamc(9.0/2, chi2/2)
+		proportion := float64(passed) / count
+
+		summaries[i] = Summary{
+			Name:          first.Results[i].Name,
+			Sequences:     len(reports),
+			Passed:        passed,
+			Proportion:    proportion,
+			MinProportion: minProportion,
+			Uniformity:    uniformity,
+			Acceptable:    proportion >= minProportion && uniformity >= 0.0001,
+		}
+	}
+	return summaries, nil
+}
@@ -0,0 +1,283 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package stattest
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+const (
+	// MaxPatternLength is the largest pattern length accepted by Serial and
+	// ApproximateEntropy; counting patterns takes 2^m counters.
+	MaxPatternLength = 24
+)
+
+// Frequency returns the p-value of the frequency (monobit) test, section 2.1: whether
+// the proportion of ones is close to one half.
+func Frequency(s Sequence) (float64, error) {
+	n := len(s)
+	if n == 0 {
+		return 0, ErrSequenceEmpty
+	}
+
+	sum := 2*s.ones() - n
+	obs := math.Abs(float64(sum)) / math.Sqrt(float64(n))
+	return math.Erfc(obs / math.Sqrt2), nil
+}
+
+// BlockFrequency returns the p-value of the frequency within a block test, section 2.2:
+// whether the proportion of ones in each non-overlapping block of m bits is close to one
+// half. Bits after the last full block are ignored. SP 800-22 recommends m >= 20,
+// m > n/100 and fewer than 100 blocks.
+func BlockFrequency(s Sequence, m int) (float64, error) {
+	if len(s) == 0 {
+		return 0, ErrSequenceEmpty
+	}
+	if m <= 0 {
+		return 0, ErrBlockSizeInvalid
+	}
+	blocks := len(s) / m
+	if blocks == 0 {
+		return 0, ErrSequenceTooShort
+	}
+
+	var chi2 float64
+	for i := 0; i < blocks; i++ {
+		pi := float64(s[i*m:(i+1)*m].ones())/float64(m) - 0.5
+		chi2 += pi * pi
+	}
+	chi2 *= 4 * float64(m)
+	return igamc(float64(blocks)/2, chi2/2), nil
+}
+
+// Runs returns the p-value of the runs test, section 2.3: whether the number of runs of
+// identical bits is as expected. As in the reference implementation, a sequence that
+// fails the frequency prerequisite gets a p-value of 0.
+func Runs(s Sequence) (float64, error) {
+	n := len(s)
+	if n == 0 {
+		return 0, ErrSequenceEmpty
+	}
+
+	fn := float64(n)
+	pi := float64(s.ones()) / fn
+	if math.Abs(pi-0.5) >= 2/math.Sqrt(fn) {
+		return 0, nil
+	}
+	runs := 1
+	for i := 1; i < n; i++ {
+		if s[i] != s[i-1] {
+			runs++
+		}
+	}
+	num := math.Abs(float64(runs) - 2*fn*pi*(1-pi))
+	den := 2 * math.Sqrt(2*fn) * pi * (1 - pi)
+	return math.Erfc(num / den), nil
+}
+
+// longestRunParams holds the block size, category bounds and category probabilities
+// of the longest run test for one range of sequence lengths (SP 800-22 section 3.4).
+type longestRunParams struct {
+	minLength int
+	m         int
+	lo, hi    int
+	pi        []float64
+}
+
+// longestRunTable lists the parameter sets from the largest minimum length down.
+var longestRunTable = []longestRunParams{
+	{750000, 10000, 10, 16, []float64{0.0882, 0.2092, 0.2483, 0.1933, 0.1208, 0.0675, 0.0727}},
+	{6272, 128, 4, 9, []float64{0.1174, 0.2430, 0.2493, 0.1752, 0.1027, 0.1124}},
+	{128, 8, 1, 4, []float64{0.2148, 0.3672, 0.2305, 0.1875}},
+}
+
+// LongestRun returns the p-value of the longest run of ones in a block test, section
+// 2.4. The block size is 8, 128 or 10000 bits depending on the length of s, which must
+// be at least 128 bits.
+func LongestRun(s Sequence) (float64, error) {
+	n := len(s)
+	if n == 0 {
+		return 0, ErrSequenceEmpty
+	}
+	var p *longestRunParams
+	for i := range longestRunTable {
+		if n >= longestRunTable[i].minLength {
+			p = &longestRunTable[i]
+			break
+		}
+	}
+	if p == nil {
+		return 0, ErrSequenceTooShort
+	}
+
+	nu := make([]int, len(p.pi))
+	blocks := n / p.m
+	for i := 0; i < blocks; i++ {
+		longest, run := 0, 0
+		for _, b := range s[i*p.m : (i+1)*p.m] {
+			if b == 1 {
+				run++
+				longest = max(longest, run)
+			} else {
+				run = 0
+			}
+		}
+		nu[min(max(longest, p.lo), p.hi)-p.lo]++
+	}
+
+	var chi2 float64
+	for i, pi := range p.pi {
+		exp := float64(blocks) * pi
+		d := float64(nu[i]) - exp
+		chi2 += d * d / exp
+	}
+	return igamc(float64(len(p.pi)-1)/2, chi2/2), nil
+}
+
+// Spectral returns the p-value of the discrete Fourier transform test, section 2.6:
+// whether the number of peaks in the spectrum exceeding the 95% threshold is as expected,
+// which detects periodic features.
+func Spectral(s Sequence) (float64, error) {
+	n := len(s)
+	if n == 0 {
+		return 0, ErrSequenceEmpty
+	}
+
+	x := make([]complex128, n)
+	for i, b := range s {
+		x[i] = complex(float64(2*int(b)-1), 0)
+	}
+	spectrum := dft(x)
+
+	fn := float64(n)
+	threshold := math.Sqrt(math.Log(1/0.05) * fn)
+	below := 0
+	for k := 0; k < n/2; k++ {
+		if cmplx.Abs(spectrum[k]) < threshold {
+			below++
+		}
+	}
+	expected := 0.95 * fn / 2
+	d := (float64(below) - expected) / math.Sqrt(fn*0.95*0.05/4)
+	return math.Erfc(math.Abs(d) / math.Sqrt2), nil
+}
+
+// Serial returns the two p-values of the serial test, section 2.11: whether every
+// overlapping m-bit pattern occurs about equally often. SP 800-22 recommends
+// m < floor(log2(n)) - 2.
+func Serial(s Sequence, m int) (p1, p2 float64, err error) {
+	if len(s) == 0 {
+		return 0, 0, ErrSequenceEmpty
+	}
+	if m < 2 || m > MaxPatternLength {
+		return 0, 0, ErrPatternLengthInvalid
+	}
+
+	psi0, psi1, psi2 := psiSquared(s, m), psiSquared(s, m-1), psiSquared(s, m-2)
+	del1 := psi0 - psi1
+	del2 := psi0 - 2*psi1 + psi2
+	p1 = igamc(math.Ldexp(1, m-2), del1/2)
+	p2 = igamc(math.Ldexp(1, m-3), del2/2)
+	return p1, p2, nil
+}
+
+// psiSquared returns the serial test's ψ²_m statistic, defined as zero for m <= 0.
+func psiSquared(s Sequence, m int) float64 {
+	if m <= 0 {
+		return 0
+	}
+	var sum float64
+	for _, c := range patternCounts(s, m) {
+		sum += float64(c) * float64(c)
+	}
+	n := float64(len(s))
+	return math.Ldexp(sum, m)/n - n
+}
+
+// ApproximateEntropy returns the p-value of the approximate entropy test, section 2.12:
+// whether the frequencies of overlapping m-bit and (m+1)-bit patterns are as expected.
+// SP 800-22 recommends m < floor(log2(n)) - 5.
+func ApproximateEntropy(s Sequence, m int) (float64, error) {
+	if len(s) == 0 {
+		return 0, ErrSequenceEmpty
+	}
+	if m < 1 || m >= MaxPatternLength {
+		return 0, ErrPatternLengthInvalid
+	}
+
+	n := float64(len(s))
+	apen := phi(s, m) - phi(s, m+1)
+	chi2 := 2 * n * (math.Ln2 - apen)
+	return igamc(math.Ldexp(1, m-1), chi2/2), nil
+}
+
+// phi returns the approximate entropy test's φ^(m) statistic.
+func phi(s Sequence, m int) float64 {
+	n := float64(len(s))
+	var sum float64
+	for _, c := range patternCounts(s, m) {
+		if c > 0 {
+			p := float64(c) / n
+			sum += p * math.Log(p)
+		}
+	}
+	return sum
+}
+
+// patternCounts counts the occurrences of each overlapping m-bit pattern in s, with
+// the first m-1 bits appended to the end so that every position starts a pattern.
+func patternCounts(s Sequence, m int) []int {
+	n := len(s)
+	counts := make([]int, 1<<m)
+	mask := 1<<m - 1
+	v := 0
+	for i := 0; i < m-1; i++ {
+		v = v<<1 | int(s[i%n])
+	}
+	for i := 0; i < n; i++ {
+		v = (v<<1 | int(s[(i+m-1)%n])) & mask
+		counts[v]++
+	}
+	return counts
+}
+
+// CumulativeSums returns the p-values of the cumulative sums test, section 2.13, in
+// forward and reverse mode: whether the maximal excursion of the random walk formed by
+// the ±1 values of the bits is as expected.
+func CumulativeSums(s Sequence) (forward, reverse float64, err error) {
+	if len(s) == 0 {
+		return 0, 0, ErrSequenceEmpty
+	}
+
+	var sum, zf, zr int
+	for _, b := range s {
+		sum += 2*int(b) - 1
+		zf = max(zf, sum, -sum)
+	}
+	sum = 0
+	for i := len(s) - 1; i >= 0; i-- {
+		sum += 2*int(s[i]) - 1
+		zr = max(zr, sum, -sum)
+	}
+	return cusumP(len(s), zf), cusumP(len(s), zr), nil
+}
+
+// cusumP returns the cumulative sums p-value for a maximal excursion z over n steps.
+func cusumP(n, z int) float64 {
+	fn, fz := float64(n), float64(z)
+	sqrtN := math.Sqrt(fn)
+
+	// The loop bounds truncate toward zero, as in the reference implementation.
+	var sum1, sum2 float64
+	for k := int((-fn/fz + 1) / 4); float64(k) <= (fn/fz-1)/4; k++ {
+		sum1 += normal(float64(4*k+1)*fz/sqrtN) - normal(float64(4*k-1)*fz/sqrtN)
+	}
+	for k := int((-fn/fz - 3) / 4); float64(k) <= (fn/fz-1)/4; k++ {
+		sum2 += normal(float64(4*k+3)*fz/sqrtN) - normal(float64(4*k+1)*fz/sqrtN)
+	}
+	return 1 - sum1 + sum2
+}
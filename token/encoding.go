@@ -0,0 +1,165 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package token
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"unsafe"
+
+	"github.com/sixafter/prng-chacha"
+)
+
+var (
+	ErrByteCountNegative = fmt.Errorf("token: byte count cannot be negative")
+)
+
+// Encoding alphabets. Base64 and base32 output is unpadded.
+const (
+	hexAlphabet       = "0123456789abcdef"
+	base64URLAlphabet = AlphabetURLSafe
+	base32Alphabet    = "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567"
+	crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+)
+
+// encoding is a radix-2^bits encoding that maps groups of input bytes to characters,
+// most significant bits first, zero-padding the final partial group like the raw
+// (unpadded) encodings of encoding/base64 and encoding/base32.
+type encoding struct {
+	alphabet string
+	bits     uint // bits per character
+	group    int  // input bytes per group; group*8 is a multiple of bits
+}
+
+var (
+	hexEncoding       = encoding{alphabet: hexAlphabet, bits: 4, group: 1}
+	base64URLEncoding = encoding{alphabet: base64URLAlphabet, bits: 6, group: 3}
+	base32Encoding    = encoding{alphabet: base32Alphabet, bits: 5, group: 5}
+	crockfordEncoding = encoding{alphabet: crockfordAlphabet, bits: 5, group: 5}
+)
+
+// encodedLen returns the number of characters needed to encode n bytes.
+func (e *encoding) encodedLen(n int) int {
+	return (n*8 + int(e.bits) - 1) / int(e.bits)
+}
+
+// encodeInPlace encodes the last n bytes of out into all of out, where
+// len(out) == e.encodedLen(n).
+//
+// Encoding proceeds front to back. Each group is loaded before its characters are
+// written, and because every encoding expands its input, the characters of a group
+// never reach the bytes of any later group.
+func (e *encoding) encodeInPlace(out []byte, n int) {
+	off := len(out) - n
+	if e.bits == 4 {
+		// Fast path for hex: one byte per group, two characters per byte.
+		for i, b := range out[off:] {
+			out[2*i] = e.alphabet[b>>4]
+			out[2*i+1] = e.alphabet[b&0x0f]
+		}
+		return
+	}
+	mask := uint64(1)<<e.bits - 1
+	o := 0
+	for i := 0; i < n; i += e.group {
+		m := min(e.group, n-i)
+		var v uint64
+		for _, b := range out[off+i : off+i+m] {
+			v = v<<8 | uint64(b)
+		}
+		inBits := uint(m) * 8
+		chars := (inBits + e.bits - 1) / e.bits
+		v <<= chars*e.bits - inBits
+		for c := chars; c > 0; c-- {
+			out[o] = e.alphabet[v>>((c-1)*e.bits)&mask]
+			o++
+		}
+	}
+}
+
+// appendRandom appends the encoding of n random bytes read from src to dst. On error
+// it returns dst unchanged.
+func (e *encoding) appendRandom(dst []byte, n int, src io.Reader) ([]byte, error) {
+	if n < 0 {
+		return dst, ErrByteCountNegative
+	}
+	size := e.encodedLen(n)
+	start := len(dst)
+	buf := slices.Grow(dst, size)[:start+size]
+	out := buf[start:]
+	if _, err := io.ReadFull(src, out[size-n:]); err != nil {
+		clear(out)
+		return dst, err
+	}
+	e.encodeInPlace(out, n)
+	return buf, nil
+}
+
+// randomString returns the encoding of n random bytes read from src, using a single
+// allocation for the result.
+func (e *encoding) randomString(n int, src io.Reader) (string, error) {
+	if n < 0 {
+		return "", ErrByteCountNegative
+	}
+	if n == 0 {
+		return "", nil
+	}
+	buf, err := e.appendRandom(make([]byte, 0, e.encodedLen(n)), n, src)
+	if err != nil {
+		return "", err
+	}
+	// buf is never modified after this point, so it can back the string directly.
+	return unsafe.String(unsafe.SliceData(buf), len(buf)), nil
+}
+
+// HexString returns n random bytes from prng.Reader encoded as 2n lower-case
+// hexadecimal characters.
+func HexString(n int) (string, error) {
+	return hexEncoding.randomString(n, prng.Reader)
+}
+
+// AppendHex appends n random bytes from prng.Reader, hex encoded, to dst and returns
+// the extended slice. It does not allocate if dst has sufficient capacity.
+func AppendHex(dst []byte, n int) ([]byte, error) {
+	return hexEncoding.appendRandom(dst, n, prng.Reader)
+}
+
+// Base64URLString returns n random bytes from prng.Reader encoded with the unpadded
+// URL-safe base64 alphabet of RFC 4648, as produced by base64.RawURLEncoding.
+func Base64URLString(n int) (string, error) {
+	return base64URLEncoding.randomString(n, prng.Reader)
+}
+
+// AppendBase64URL appends n random bytes from prng.Reader, encoded like Base64URLString,
+// to dst and returns the extended slice. It does not allocate if dst has sufficient capacity.
+func AppendBase64URL(dst []byte, n int) ([]byte, error) {
+	return base64URLEncoding.appendRandom(dst, n, prng.Reader)
+}
+
+// Base32String returns n random bytes from prng.Reader encoded with the unpadded
+// standard base32 alphabet of RFC 4648.
+func Base32String(n int) (string, error) {
+	return base32Encoding.randomString(n, prng.Reader)
+}
+
+// AppendBase32 appends n random bytes from prng.Reader, encoded like Base32String, to
+// dst and returns the extended slice. It does not allocate if dst has sufficient capacity.
+func AppendBase32(dst []byte, n int) ([]byte, error) {
+	return base32Encoding.appendRandom(dst, n, prng.Reader)
+}
+
+// CrockfordString returns n random bytes from prng.Reader encoded with Crockford's
+// base32 alphabet (upper case, without I, L, O and U), unpadded and without check symbol.
+func CrockfordString(n int) (string, error) {
+	return crockfordEncoding.randomString(n, prng.Reader)
+}
+
+// AppendCrockford appends n random bytes from prng.Reader, encoded like CrockfordString,
+// to dst and returns the extended slice. It does not allocate if dst has sufficient capacity.
+func AppendCrockford(dst []byte, n int) ([]byte, error) {
+	return crockfordEncoding.appendRandom(dst, n, prng.Reader)
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package token
+
+import (
+	"bytes"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"testing"
+
+	"github.com/sixafter/prng-chacha"
+	"github.com/sixafter/prng-chacha/internal/race"
+	"github.com/stretchr/testify/assert"
+)
+
+// stdEncodings pairs each encoding with the standard library encoder it must match.
+var stdEncodings = []struct {
+	name string
+	enc  *encoding
+	std  func([]byte) string
+}{
+	{"Hex", &hexEncoding, hex.EncodeToString},
+	{"Base64URL", &base64URLEncoding, base64.RawURLEncoding.EncodeToString},
+	{"Base32", &base32Encoding, base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString},
+	{"Crockford", &crockfordEncoding, base32.NewEncoding(crockfordAlphabet).WithPadding(base32.NoPadding).EncodeToString},
+}
+
+// TestEncoding_MatchesStdlib verifies in-place encoding against encoding/hex, base64 and
+// base32 for every length up to several groups, including all partial groups.
+func TestEncoding_MatchesStdlib(t *testing.T) {
+	t.Parallel()
+
+	src := make([]byte, 64)
+	_, err := prng.Reader.Read(src)
+	assert.NoError(t, err)
+
+	for _, tc := range stdEncodings {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			is := assert.New(t)
+
+			for n := 0; n <= len(src); n++ {
+				s, err := tc.enc.randomString(n, bytes.NewReader(src[:n]))
+				is.NoError(err)
+				is.Equal(tc.std(src[:n]), s, "length %d", n)
+			}
+		})
+	}
+}
+
+// TestEncoding_Append verifies that Append variants extend dst and leave it intact on error.
+func TestEncoding_Append(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	dst := []byte("key_")
+	out, err := AppendHex(dst, 16)
+	is.NoError(err)
+	is.Len(out, 4+32)
+	is.Equal("key_", string(out[:4]))
+	_, err = hex.DecodeString(string(out[4:]))
+	is.NoError(err)
+
+	out, err = base64URLEncoding.appendRandom(dst, 16, errReader{})
+	is.ErrorIs(err, errRead)
+	is.Equal("key_", string(out))
+
+	out, err = AppendCrockford(nil, -1)
+	is.ErrorIs(err, ErrByteCountNegative)
+	is.Nil(out)
+}
+
+// TestEncoding_Helpers verifies the lengths, alphabets and decodability of the exported helpers.
+func TestEncoding_Helpers(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		fn     func(int) (string, error)
+		length int
+		decode func(string) ([]byte, error)
+	}{
+		{"Hex", HexString, 64, hex.DecodeString},
+		{"Base64URL", Base64URLString, 43, base64.RawURLEncoding.DecodeString},
+		{"Base32", Base32String, 52, base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString},
+		{"Crockford", CrockfordString, 52, base32.NewEncoding(crockfordAlphabet).WithPadding(base32.NoPadding).DecodeString},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			is := assert.New(t)
+
+			a, err := tc.fn(32)
+			is.NoError(err)
+			b, err := tc.fn(32)
+			is.NoError(err)
+			is.Len(a, tc.length)
+			is.NotEqual(a, b)
+
+			raw, err := tc.decode(a)
+			is.NoError(err)
+			is.Len(raw, 32)
+
+			empty, err := tc.fn(0)
+			is.NoError(err)
+			is.Empty(empty)
+
+			_, err = tc.fn(-1)
+			is.ErrorIs(err, ErrByteCountNegative)
+		})
+	}
+}
+
+// TestEncoding_ShortRead verifies that a short source is reported and yields no output.
+func TestEncoding_ShortRead(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	s, err := base32Encoding.randomString(10, bytes.NewReader([]byte{1, 2, 3}))
+	is.ErrorIs(err, io.ErrUnexpectedEOF)
+	is.Empty(s)
+}
+
+// TestEncoding_Allocations verifies that String helpers allocate only the result and
+// Append helpers do not allocate when dst has sufficient capacity.
+func TestEncoding_Allocations(t *testing.T) {
+	if race.Enabled {
+		t.Skip("allocation counts are not exact under the race detector")
+	}
+	is := assert.New(t)
+
+	strs := map[string]func(int) (string, error){
+		"Hex": HexString, "Base64URL": Base64URLString, "Base32": Base32String, "Crockford": CrockfordString,
+	}
+	for name, fn := range strs {
+		allocs := testing.AllocsPerRun(100, func() { _, _ = fn(32) })
+		is.Equal(1.0, allocs, "%sString allocations", name)
+	}
+
+	appends := map[string]func([]byte, int) ([]byte, error){
+		"Hex": AppendHex, "Base64URL": AppendBase64URL, "Base32": AppendBase32, "Crockford": AppendCrockford,
+	}
+	buf := make([]byte, 0, 128)
+	for name, fn := range appends {
+		allocs := testing.AllocsPerRun(100, func() { _, _ = fn(buf[:0], 32) })
+		is.Equal(0.0, allocs, "Append%s allocations", name)
+	}
+}
@@ -0,0 +1,319 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package token generates random strings such as API keys, session IDs and invite
+// codes from arbitrary alphabets.
+//
+// Characters are selected without modulo bias: random bytes are consumed as a stream
+// of k-bit values, where k is the smallest width that can index the alphabet, and
+// values outside the alphabet are rejected. Only the bits actually needed are drawn,
+// so a 62-character alphabet costs about 6.1 random bits per character.
+//
+// Alphabets may contain any valid UTF-8 characters, including multi-byte runes.
+// Randomness is drawn from prng.Reader unless another source, such as a reader
+// returned by prng.NewReader, is supplied with WithReader.
+//
+// For identifiers that are simply random bytes in a standard encoding, HexString,
+// Base64URLString, Base32String and CrockfordString (and their Append variants) read
+// from prng.Reader directly into the output buffer and encode in place, so the only
+// allocation is the returned string.
+//
+// Example:
+//
+//	g, err := token.New(token.WithAlphabet(token.AlphabetAlphanumeric), token.WithEntropy(128))
+//	if err != nil {
+//	    // handle error
+//	}
+//	key, err := g.Generate()
+package token
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/bits"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/sixafter/prng-chacha"
+)
+
+var (
+	ErrAlphabetTooShort   = fmt.Errorf("token: alphabet must contain at least 2 characters")
+	ErrAlphabetInvalidUTF = fmt.Errorf("token: alphabet must be valid UTF-8")
+	ErrAlphabetDuplicate  = fmt.Errorf("token: alphabet must not contain duplicate characters")
+	ErrLengthInvalid      = fmt.Errorf("token: length must be greater than zero")
+	ErrEntropyInvalid     = fmt.Errorf("token: entropy bits must be greater than zero")
+	ErrNilReader          = fmt.Errorf("token: reader must not be nil")
+)
+
+// Predefined alphabets.
+const (
+	// AlphabetAlphanumeric contains digits and upper- and lower-case ASCII letters.
+	AlphabetAlphanumeric = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+	// AlphabetLowerAlphanumeric contains digits and lower-case ASCII letters.
+	AlphabetLowerAlphanumeric = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+	// AlphabetNumeric contains the decimal digits.
+	AlphabetNumeric = "0123456789"
+
+	// AlphabetURLSafe contains the 64 characters of the base64url encoding.
+	AlphabetURLSafe = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+
+	// AlphabetUnambiguous contains digits and upper-case letters without the easily
+	// confused characters 0, 1, I, L and O, which suits codes read aloud or typed by hand.
+	AlphabetUnambiguous = "23456789ABCDEFGHJKMNPQRSTUVWXYZ"
+)
+
+// Default configuration values.
+const (
+	// defaultAlphabet is used when no alphabet is configured.
+	defaultAlphabet = AlphabetAlphanumeric
+
+	// defaultLength yields about 131 bits of entropy with the default alphabet.
+	defaultLength = 22
+
+	// minReadSize is the smallest number of bytes requested from the source per read,
+	// which keeps the per-call overhead low for short tokens over large alphabets.
+	minReadSize = 16
+)
+
+// Config defines the alphabet, length and randomness source of a Generator.
+type Config struct {
+	// Alphabet is the set of characters tokens are drawn from. It must be valid UTF-8
+	// and contain at least two distinct characters. Defaults to AlphabetAlphanumeric.
+	Alphabet string
+
+	// Length is the number of characters (not bytes) in each token. Defaults to 22.
+	// It is ignored if EntropyBits is set.
+	Length int
+
+	// EntropyBits, if non-zero, sets Length to the smallest number of characters that
+	// provides at least this many bits of entropy with the configured Alphabet.
+	EntropyBits int
+
+	// Reader is the source of randomness. Defaults to prng.Reader.
+	Reader io.Reader
+}
+
+// Option defines a functional option for customizing a Generator's Config.
+type Option func(*Config)
+
+// WithAlphabet returns an Option that sets the characters tokens are drawn from.
+func WithAlphabet(alphabet string) Option {
+	return func(cfg *Config) {
+		cfg.Alphabet = alphabet
+	}
+}
+
+// WithLength returns an Option that sets the number of characters in each token.
+func WithLength(n int) Option {
+	return func(cfg *Config) {
+		cfg.Length = n
+		cfg.EntropyBits = 0
+	}
+}
+
+// WithEntropy returns an Option that sizes tokens to carry at least bits bits of
+// entropy, e.g. 128 for session identifiers. It overrides any earlier WithLength.
+func WithEntropy(bits int) Option {
+	return func(cfg *Config) {
+		cfg.EntropyBits = bits
+	}
+}
+
+// WithReader returns an Option that sets the source of randomness, typically a
+// prng.Interface returned by prng.NewReader.
+func WithReader(r io.Reader) Option {
+	return func(cfg *Config) {
+		cfg.Reader = r
+	}
+}
+
+// Generator produces random tokens. It is immutable after construction and safe for
+// concurrent use.
+type Generator struct {
+	// ascii holds the alphabet when every character is a single byte; otherwise runes
+	// holds it and tokens are built rune by rune.
+	ascii []byte
+	runes []rune
+
+	alphabet string
+	size     uint32 // number of characters in the alphabet
+	width    uint   // bits drawn per candidate character
+	mask     uint32
+	length   int
+	maxBytes int // maximum encoded length of a token
+	src      io.Reader
+
+	// readSize is the expected number of random bytes per token, with headroom.
+	readSize int
+	bufs     sync.Pool
+}
+
+// New returns a Generator configured by opts.
+//
+// It returns ErrAlphabetTooShort, ErrAlphabetInvalidUTF or ErrAlphabetDuplicate for an
+// unusable alphabet, ErrLengthInvalid or ErrEntropyInvalid for a non-positive size, and
+// ErrNilReader if the configured reader is nil.
+func New(opts ...Option) (*Generator, error) {
+	cfg := Config{
+		Alphabet: defaultAlphabet,
+		Length:   defaultLength,
+		Reader:   prng.Reader,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.Reader == nil {
+		return nil, ErrNilReader
+	}
+	if !utf8.ValidString(cfg.Alphabet) {
+		return nil, ErrAlphabetInvalidUTF
+	}
+
+	runes := []rune(cfg.Alphabet)
+	if len(runes) < 2 {
+		return nil, ErrAlphabetTooShort
+	}
+	seen := make(map[rune]struct{}, len(runes))
+	for _, r := range runes {
+		if _, ok := seen[r]; ok {
+			return nil, ErrAlphabetDuplicate
+		}
+		seen[r] = struct{}{}
+	}
+
+	size := uint32(len(runes))
+	length := cfg.Length
+	if cfg.EntropyBits != 0 {
+		if cfg.EntropyBits < 0 {
+			return nil, ErrEntropyInvalid
+		}
+		length = int(math.Ceil(float64(cfg.EntropyBits) / math.Log2(float64(size))))
+	}
+	if length <= 0 {
+		return nil, ErrLengthInvalid
+	}
+
+	width := uint(bits.Len32(size - 1))
+	g := &Generator{
+		alphabet: cfg.Alphabet,
+		size:     size,
+		width:    width,
+		mask:     1<<width - 1,
+		length:   length,
+		src:      cfg.Reader,
+	}
+	if len(runes) == len(cfg.Alphabet) {
+		g.ascii = []byte(cfg.Alphabet)
+		g.maxBytes = length
+	} else {
+		g.runes = runes
+		maxRune := 0
+		for _, r := range runes {
+			maxRune = max(maxRune, utf8.RuneLen(r))
+		}
+		g.maxBytes = length * maxRune
+	}
+	g.readSize = g.bytesFor(length)
+	g.bufs.New = func() any {
+		b := make([]byte, g.readSize)
+		return &b
+	}
+
+	return g, nil
+}
+
+// Alphabet returns the alphabet tokens are drawn from.
+func (g *Generator) Alphabet() string {
+	return g.alphabet
+}
+
+// Length returns the number of characters in each token.
+func (g *Generator) Length() int {
+	return g.length
+}
+
+// EntropyBits returns the entropy of each token in bits: Length * log2(len(Alphabet)).
+func (g *Generator) EntropyBits() float64 {
+	return float64(g.length) * math.Log2(float64(g.size))
+}
+
+// Generate returns a new random token.
+//
+// It returns an error only if the underlying reader fails.
+func (g *Generator) Generate() (string, error) {
+	bp := g.bufs.Get().(*[]byte)
+	// Clear unused random bytes before the buffer returns to the pool, including after a
+	// failed read.
+	defer func() {
+		clear((*bp)[:cap(*bp)])
+		g.bufs.Put(bp)
+	}()
+
+	bs := bitStream{buf: *bp, pos: len(*bp)}
+	var sb strings.Builder
+	sb.Grow(g.maxBytes)
+
+	for i := 0; i < g.length; {
+		if bs.pos == len(bs.buf) && bs.nbits < g.width {
+			bs.buf = bs.buf[:min(cap(bs.buf), g.bytesFor(g.length-i))]
+			if _, err := io.ReadFull(g.src, bs.buf); err != nil {
+				return "", err
+			}
+			bs.pos = 0
+		}
+		v, ok := bs.next(g.width)
+		if !ok {
+			continue
+		}
+		v &= g.mask
+		if v >= g.size {
+			continue
+		}
+		if g.ascii != nil {
+			sb.WriteByte(g.ascii[v])
+		} else {
+			sb.WriteRune(g.runes[v])
+		}
+		i++
+	}
+	return sb.String(), nil
+}
+
+// bytesFor returns the number of random bytes to request for n more characters: the
+// expected consumption including rejections, plus one eighth as headroom so that a
+// second read is rarely needed.
+func (g *Generator) bytesFor(n int) int {
+	expected := float64(n) * float64(g.width) * float64(uint64(1)<<g.width) / float64(g.size) / 8
+	return max(minReadSize, int(math.Ceil(expected*1.125)))
+}
+
+// bitStream extracts fixed-width values from a buffer of random bytes.
+type bitStream struct {
+	buf   []byte
+	pos   int
+	acc   uint64
+	nbits uint
+}
+
+// next returns the next width bits, or false if the buffer is exhausted first. Bits left
+// over in the accumulator are kept for the next call.
+func (s *bitStream) next(width uint) (uint32, bool) {
+	for s.nbits < width {
+		if s.pos == len(s.buf) {
+			return 0, false
+		}
+		s.acc = s.acc<<8 | uint64(s.buf[s.pos])
+		s.pos++
+		s.nbits += 8
+	}
+	s.nbits -= width
+	return uint32(s.acc >> s.nbits), true
+}
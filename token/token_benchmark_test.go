@@ -0,0 +1,191 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package token
+
+import (
+	"encoding/hex"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sixafter/prng-chacha"
+)
+
+// benchConcurrent runs fn across the given number of goroutines, distributing b.N
+// iterations as evenly as possible.
+func benchConcurrent(b *testing.B, fn func(), goroutines int) {
+	nPerG := b.N / goroutines
+	rem := b.N % goroutines
+	var wg sync.WaitGroup
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < goroutines; i++ {
+		it := nPerG
+		if i < rem {
+			it++
+		}
+		wg.Add(1)
+		go func(it int) {
+			defer wg.Done()
+			for j := 0; j < it; j++ {
+				fn()
+			}
+		}(it)
+	}
+	wg.Wait()
+}
+
+// mustNew returns a Generator or fails the benchmark.
+func mustNew(b *testing.B, opts ...Option) *Generator {
+	g, err := New(opts...)
+	if err != nil {
+		b.Fatalf("New failed: %v", err)
+	}
+	return g
+}
+
+// BenchmarkToken_Alphabets measures serial generation of 128-bit tokens for alphabets
+// with different rejection rates and character widths.
+func BenchmarkToken_Alphabets(b *testing.B) {
+	alphabets := []struct {
+		name     string
+		alphabet string
+	}{
+		{"Numeric", AlphabetNumeric},
+		{"Unambiguous", AlphabetUnambiguous},
+		{"LowerAlphanumeric", AlphabetLowerAlphanumeric},
+		{"Alphanumeric", AlphabetAlphanumeric},
+		{"URLSafe", AlphabetURLSafe},
+		{"Greek", "αβγδεζηθικλμνξοπρστυφχψω"},
+	}
+	for _, a := range alphabets {
+		b.Run(a.name, func(b *testing.B) {
+			g := mustNew(b, WithAlphabet(a.alphabet), WithEntropy(128))
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := g.Generate(); err != nil {
+					b.Fatalf("Generate failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkToken_Lengths measures serial generation across token lengths.
+func BenchmarkToken_Lengths(b *testing.B) {
+	for _, n := range []int{8, 22, 32, 64, 128, 1024} {
+		b.Run("Length_"+strconv.Itoa(n), func(b *testing.B) {
+			g := mustNew(b, WithLength(n))
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := g.Generate(); err != nil {
+					b.Fatalf("Generate failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkToken_Serial measures 128-bit alphanumeric tokens in a serial loop, the
+// token equivalent of BenchmarkUUID_v4_CSPRNG_Serial.
+func BenchmarkToken_Serial(b *testing.B) {
+	g := mustNew(b, WithEntropy(128))
+	b.ReportAllocs()
+	for b.Loop() {
+		_, _ = g.Generate()
+	}
+}
+
+// BenchmarkToken_Parallel measures 128-bit alphanumeric tokens with RunParallel.
+func BenchmarkToken_Parallel(b *testing.B) {
+	g := mustNew(b, WithEntropy(128))
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = g.Generate()
+		}
+	})
+}
+
+// BenchmarkToken_Concurrent measures 128-bit alphanumeric tokens across goroutine counts.
+func BenchmarkToken_Concurrent(b *testing.B) {
+	g := mustNew(b, WithEntropy(128))
+	for _, gr := range []int{2, 4, 8, 16, 32, 64, 128, 256} {
+		b.Run("Goroutines_"+strconv.Itoa(gr), func(b *testing.B) {
+			benchConcurrent(b, func() { _, _ = g.Generate() }, gr)
+		})
+	}
+}
+
+// BenchmarkToken_UUIDString_Serial is the UUID baseline for BenchmarkToken_Serial: a
+// 122-bit random identifier rendered as a string via google/uuid and prng.Reader.
+func BenchmarkToken_UUIDString_Serial(b *testing.B) {
+	uuid.SetRand(prng.Reader)
+	defer uuid.SetRand(nil)
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = uuid.NewString()
+	}
+}
+
+// BenchmarkToken_Encoded measures the encoded token helpers for 32 random bytes. The
+// String variants allocate once for the result; the Append variants do not allocate.
+func BenchmarkToken_Encoded(b *testing.B) {
+	strs := []struct {
+		name string
+		fn   func(int) (string, error)
+	}{
+		{"HexString", HexString},
+		{"Base64URLString", Base64URLString},
+		{"Base32String", Base32String},
+		{"CrockfordString", CrockfordString},
+	}
+	for _, s := range strs {
+		b.Run(s.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := s.fn(32); err != nil {
+					b.Fatalf("%s failed: %v", s.name, err)
+				}
+			}
+		})
+	}
+
+	appends := []struct {
+		name string
+		fn   func([]byte, int) ([]byte, error)
+	}{
+		{"AppendHex", AppendHex},
+		{"AppendBase64URL", AppendBase64URL},
+		{"AppendBase32", AppendBase32},
+		{"AppendCrockford", AppendCrockford},
+	}
+	for _, a := range appends {
+		b.Run(a.name, func(b *testing.B) {
+			buf := make([]byte, 0, 64)
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := a.fn(buf[:0], 32); err != nil {
+					b.Fatalf("%s failed: %v", a.name, err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkToken_Encoded_ReadThenEncode is the baseline for BenchmarkToken_Encoded:
+// Read into a fresh slice, then encode with encoding/hex.
+func BenchmarkToken_Encoded_ReadThenEncode(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		raw := make([]byte, 32)
+		if _, err := prng.Reader.Read(raw); err != nil {
+			b.Fatalf("Read failed: %v", err)
+		}
+		_ = hex.EncodeToString(raw)
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package token
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/sixafter/prng-chacha"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingReader wraps a reader and counts Read calls and bytes.
+type countingReader struct {
+	r     io.Reader
+	mu    sync.Mutex
+	calls int
+	bytes int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.mu.Lock()
+	c.calls++
+	c.bytes += n
+	c.mu.Unlock()
+	return n, err
+}
+
+// errReader always fails.
+type errReader struct{}
+
+var errRead = errors.New("read failed")
+
+func (errReader) Read([]byte) (int, error) { return 0, errRead }
+
+// TestToken_New_Validation verifies that invalid configurations are rejected.
+func TestToken_New_Validation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		opts    []Option
+		wantErr error
+	}{
+		{"EmptyAlphabet", []Option{WithAlphabet("")}, ErrAlphabetTooShort},
+		{"SingleCharacter", []Option{WithAlphabet("a")}, ErrAlphabetTooShort},
+		{"InvalidUTF8", []Option{WithAlphabet("ab\xff")}, ErrAlphabetInvalidUTF},
+		{"Duplicate", []Option{WithAlphabet("abca")}, ErrAlphabetDuplicate},
+		{"DuplicateRune", []Option{WithAlphabet("αβα")}, ErrAlphabetDuplicate},
+		{"ZeroLength", []Option{WithLength(0)}, ErrLengthInvalid},
+		{"NegativeLength", []Option{WithLength(-1)}, ErrLengthInvalid},
+		{"NegativeEntropy", []Option{WithEntropy(-128)}, ErrEntropyInvalid},
+		{"NilReader", []Option{WithReader(nil)}, ErrNilReader},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			is := assert.New(t)
+
+			g, err := New(tc.opts...)
+			is.ErrorIs(err, tc.wantErr)
+			is.Nil(g)
+		})
+	}
+}
+
+// TestToken_Defaults verifies the default alphabet, length and entropy.
+func TestToken_Defaults(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	g, err := New()
+	is.NoError(err)
+	is.Equal(AlphabetAlphanumeric, g.Alphabet())
+	is.Equal(22, g.Length())
+	is.Greater(g.EntropyBits(), 128.0)
+
+	tok, err := g.Generate()
+	is.NoError(err)
+	is.Len(tok, 22)
+	for _, c := range tok {
+		is.True(strings.ContainsRune(AlphabetAlphanumeric, c), "unexpected character %q", c)
+	}
+}
+
+// TestToken_Entropy verifies that WithEntropy picks the smallest sufficient length.
+func TestToken_Entropy(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		alphabet string
+		bits     int
+		want     int
+	}{
+		{AlphabetURLSafe, 128, 22},      // 6 bits per character
+		{"0123456789abcdef", 128, 32},   // exactly 4 bits per character
+		{AlphabetAlphanumeric, 128, 22}, // ~5.95 bits per character
+		{AlphabetNumeric, 20, 7},        // ~3.32 bits per character
+		{"01", 1, 1},
+	}
+
+	for _, tc := range testCases {
+		g, err := New(WithLength(5), WithAlphabet(tc.alphabet), WithEntropy(tc.bits))
+		assert.NoError(t, err)
+		assert.Equal(t, tc.want, g.Length(), "alphabet %q, %d bits", tc.alphabet, tc.bits)
+		assert.GreaterOrEqual(t, g.EntropyBits(), float64(tc.bits))
+	}
+
+	// A later WithLength replaces an earlier WithEntropy.
+	g, err := New(WithEntropy(128), WithLength(5))
+	assert.NoError(t, err)
+	assert.Equal(t, 5, g.Length())
+}
+
+// TestToken_Deterministic verifies bit extraction and rejection with a fixed source.
+func TestToken_Deterministic(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	// Alphabet of 3 uses 2-bit values: 0b00_01_10_11 yields 0, 1, 2 and a rejected 3.
+	src := make([]byte, minReadSize)
+	src[0] = 0x1B
+	g, err := New(WithAlphabet("abc"), WithLength(3), WithReader(bytes.NewReader(src)))
+	is.NoError(err)
+
+	tok, err := g.Generate()
+	is.NoError(err)
+	is.Equal("abc", tok)
+}
+
+// TestToken_MultiByteAlphabet verifies tokens drawn from an alphabet of multi-byte runes.
+func TestToken_MultiByteAlphabet(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	alphabet := "αβγδεζηθ🙂🎲"
+	g, err := New(WithAlphabet(alphabet), WithLength(50))
+	is.NoError(err)
+
+	seen := make(map[rune]int)
+	for i := 0; i < 20; i++ {
+		tok, err := g.Generate()
+		is.NoError(err)
+		is.True(utf8.ValidString(tok))
+		is.Equal(50, utf8.RuneCountInString(tok))
+		for _, r := range tok {
+			is.True(strings.ContainsRune(alphabet, r), "unexpected rune %q", r)
+			seen[r]++
+		}
+	}
+	is.Len(seen, utf8.RuneCountInString(alphabet), "every rune should appear in 1000 draws")
+}
+
+// TestToken_Refill verifies that long tokens spanning several reads are complete and
+// that consumption stays close to the theoretical minimum.
+func TestToken_Refill(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	src := &countingReader{r: prng.Reader}
+	// 33 characters need 6 bits each, with 64/33 expected draws per accepted character.
+	g, err := New(WithAlphabet(AlphabetURLSafe[:33]), WithLength(4096), WithReader(src))
+	is.NoError(err)
+
+	tok, err := g.Generate()
+	is.NoError(err)
+	is.Len(tok, 4096)
+
+	expected := 4096 * 6 * 64 / 33 / 8
+	is.Less(src.bytes, expected*3/2, "consumed %d bytes, expected about %d", src.bytes, expected)
+}
+
+// TestToken_ReadError verifies that reader errors are returned.
+func TestToken_ReadError(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	g, err := New(WithReader(errReader{}))
+	is.NoError(err)
+
+	tok, err := g.Generate()
+	is.ErrorIs(err, errRead)
+	is.Empty(tok)
+
+	// A short source surfaces io.ErrUnexpectedEOF.
+	g, err = New(WithReader(bytes.NewReader([]byte{1, 2, 3})))
+	is.NoError(err)
+	_, err = g.Generate()
+	is.ErrorIs(err, io.ErrUnexpectedEOF)
+}
+
+// TestToken_Uniformity checks the character distribution with a chi-squared test.
+func TestToken_Uniformity(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	// 36 characters: a 6-bit draw rejects 28 of 64 values, exercising rejection heavily.
+	g, err := New(WithAlphabet(AlphabetLowerAlphanumeric), WithLength(1000))
+	is.NoError(err)
+
+	counts := make(map[rune]int)
+	const tokens = 100
+	for i := 0; i < tokens; i++ {
+		tok, err := g.Generate()
+		is.NoError(err)
+		for _, c := range tok {
+			counts[c]++
+		}
+	}
+
+	k := len(AlphabetLowerAlphanumeric)
+	expected := float64(tokens*1000) / float64(k)
+	var chi2 float64
+	for _, c := range AlphabetLowerAlphanumeric {
+		d := float64(counts[c]) - expected
+		chi2 += d * d / expected
+	}
+	// Critical value for 35 degrees of freedom at p = 0.0001 is about 71.
+	is.Less(chi2, 71.0, "chi-squared statistic %.2f suggests bias", chi2)
+}
+
+// TestToken_Concurrent verifies that a Generator can be shared across goroutines.
+func TestToken_Concurrent(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	r, err := prng.NewReader()
+	is.NoError(err)
+	g, err := New(WithReader(r), WithEntropy(128))
+	is.NoError(err)
+
+	const goroutines, perG = 16, 200
+	results := make(chan string, goroutines*perG)
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perG; j++ {
+				tok, err := g.Generate()
+				if err != nil {
+					t.Errorf("Generate failed: %v", err)
+					return
+				}
+				results <- tok
+			}
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	seen := make(map[string]struct{}, goroutines*perG)
+	for tok := range results {
+		_, dup := seen[tok]
+		is.False(dup, "duplicate token %q", tok)
+		seen[tok] = struct{}{}
+	}
+	is.Len(seen, goroutines*perG)
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package ulid
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/sixafter/prng-chacha"
+	"github.com/sixafter/prng-chacha/internal/randutil"
+)
+
+var (
+	ErrMonotonicOverflow = fmt.Errorf("ulid: monotonic entropy overflow within the same millisecond")
+	ErrNilReader         = fmt.Errorf("ulid: reader must not be nil")
+)
+
+const (
+	// entropyLen is the number of random bytes in a ULID.
+	entropyLen = 10
+
+	// batchSize is the number of ULIDs worth of random bytes read at once.
+	batchSize = 32
+)
+
+// Config defines the randomness source and behaviour of a Generator.
+type Config struct {
+	// Reader is the source of randomness. Defaults to prng.Reader.
+	Reader io.Reader
+
+	// Monotonic makes ULIDs from the Generator strictly increasing. Within the same
+	// millisecond, the 80-bit entropy of the previous ULID is incremented by one instead
+	// of being drawn afresh; if it would wrap, ErrMonotonicOverflow is returned until
+	// the clock advances. If the clock moves backwards, the previous timestamp is
+	// reused. Monotonic generation serializes calls.
+	Monotonic bool
+}
+
+// Option defines a functional option for customizing a Generator's Config.
+type Option func(*Config)
+
+// WithReader returns an Option that sets the source of randomness, typically a
+// prng.Interface returned by prng.NewReader.
+func WithReader(r io.Reader) Option {
+	return func(cfg *Config) {
+		cfg.Reader = r
+	}
+}
+
+// WithMonotonic returns an Option that makes generated ULIDs strictly increasing.
+func WithMonotonic(enabled bool) Option {
+	return func(cfg *Config) {
+		cfg.Monotonic = enabled
+	}
+}
+
+// Generator produces ULIDs. It is safe for concurrent use.
+type Generator struct {
+	monotonic bool
+	batches   *randutil.Batches
+
+	// now returns the current time; replaced in tests.
+	now func() time.Time
+
+	mu      sync.Mutex
+	last    ULID
+	hasLast bool
+}
+
+// defaultGenerator backs the package-level New.
+var defaultGenerator = func() *Generator {
+	g, err := NewGenerator()
+	if err != nil {
+		panic(err)
+	}
+	return g
+}()
+
+// NewGenerator returns a Generator configured by opts.
+//
+// It returns ErrNilReader if the configured reader is nil.
+func NewGenerator(opts ...Option) (*Generator, error) {
+	cfg := Config{
+		Reader: prng.Reader,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.Reader == nil {
+		return nil, ErrNilReader
+	}
+
+	return &Generator{
+		monotonic: cfg.Monotonic,
+		batches:   randutil.NewBatches(cfg.Reader, entropyLen, batchSize),
+		now:       time.Now,
+	}, nil
+}
+
+// New returns a ULID for the current time from the default Generator, which is not
+// monotonic.
+func New() (ULID, error) {
+	return defaultGenerator.New()
+}
+
+// New returns a ULID for the current time.
+//
+// In monotonic mode it returns ErrMonotonicOverflow if the previous ULID has the same
+// millisecond and its entropy is already at the maximum, which is astronomically
+// unlikely with fresh random entropy. It returns the reader's error if the reader fails.
+func (g *Generator) New() (ULID, error) {
+	return g.NewAt(g.now())
+}
+
+// NewAt returns a ULID with the timestamp t, which must lie between the Unix epoch and
+// MaxTime. In monotonic mode a t earlier than the previous ULID's timestamp is replaced
+// by that timestamp.
+//
+// It returns ErrTimeInvalid for an unrepresentable t, and otherwise fails like New.
+func (g *Generator) NewAt(t time.Time) (ULID, error) {
+	ms := t.UnixMilli()
+	if ms < 0 || ms > maxMillis {
+		return Zero, ErrTimeInvalid
+	}
+
+	var id ULID
+	if g.monotonic {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+
+		if g.hasLast && uint64(ms) <= g.last.Timestamp() {
+			id = g.last
+			if !increment(id[6:]) {
+				return Zero, ErrMonotonicOverflow
+			}
+			g.last = id
+			return id, nil
+		}
+	}
+
+	if err := g.entropy(id[6:]); err != nil {
+		return Zero, err
+	}
+	id[0] = byte(ms >> 40)
+	id[1] = byte(ms >> 32)
+	id[2] = byte(ms >> 24)
+	id[3] = byte(ms >> 16)
+	id[4] = byte(ms >> 8)
+	id[5] = byte(ms)
+	if g.monotonic {
+		g.last, g.hasLast = id, true
+	}
+	return id, nil
+}
+
+// increment adds one to the big-endian number in b, reporting false, and leaving b
+// unchanged, if it would overflow.
+func increment(b []byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] != 0xff {
+			b[i]++
+			clear(b[i+1:])
+			return true
+		}
+	}
+	return false
+}
+
+// entropy fills dst with entropyLen random bytes from the generator's pooled batches.
+func (g *Generator) entropy(dst []byte) error {
+	return g.batches.Read(dst)
+}
@@ -0,0 +1,186 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package ulid
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// errReader always fails.
+type errReader struct{}
+
+var errRead = errors.New("read failed")
+
+func (errReader) Read([]byte) (int, error) { return 0, errRead }
+
+// compare orders ULIDs bytewise.
+func compare(a, b ULID) int { return bytes.Compare(a[:], b[:]) }
+
+// TestGenerator_Validation verifies that invalid configurations and times are rejected.
+func TestGenerator_Validation(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	g, err := NewGenerator(WithReader(nil))
+	is.ErrorIs(err, ErrNilReader)
+	is.Nil(g)
+
+	g, err = NewGenerator()
+	is.NoError(err)
+	_, err = g.NewAt(time.UnixMilli(-1))
+	is.ErrorIs(err, ErrTimeInvalid)
+	_, err = g.NewAt(MaxTime.Add(time.Millisecond))
+	is.ErrorIs(err, ErrTimeInvalid)
+
+	id, err := g.NewAt(MaxTime)
+	is.NoError(err)
+	is.Equal(MaxTime, id.Time())
+}
+
+// TestGenerator_New verifies timestamps, uniqueness and ordering across milliseconds.
+func TestGenerator_New(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	before := time.Now().Truncate(time.Millisecond)
+	id, err := New()
+	is.NoError(err)
+	is.False(id.Time().Before(before))
+	is.False(id.Time().After(time.Now()))
+
+	g, err := NewGenerator()
+	is.NoError(err)
+	a, err := g.NewAt(time.UnixMilli(1000))
+	is.NoError(err)
+	b, err := g.NewAt(time.UnixMilli(1001))
+	is.NoError(err)
+	is.Equal(-1, compare(a, b))
+	is.Less(a.String(), b.String())
+
+	seen := make(map[ULID]struct{})
+	for i := 0; i < 10000; i++ {
+		id := Must(g.NewAt(time.UnixMilli(1000)))
+		_, dup := seen[id]
+		is.False(dup)
+		seen[id] = struct{}{}
+	}
+}
+
+// TestGenerator_Monotonic verifies increments within a millisecond, clock regression
+// and fresh entropy once the clock advances.
+func TestGenerator_Monotonic(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	g, err := NewGenerator(WithMonotonic(true))
+	is.NoError(err)
+	now := time.UnixMilli(1_700_000_000_000)
+	g.now = func() time.Time { return now }
+
+	first := Must(g.New())
+	prev := first
+	for i := 0; i < 1000; i++ {
+		if i == 500 {
+			now = now.Add(-time.Second)
+		}
+		id := Must(g.New())
+		is.Equal(1, compare(id, prev))
+		is.Equal(first.Timestamp(), id.Timestamp())
+		prev = id
+	}
+	// The entropy advanced by exactly one per ULID.
+	is.Equal(first[15]+232, prev[15]) // 1000 mod 256 = 232
+
+	now = now.Add(2 * time.Second)
+	next := Must(g.New())
+	is.Equal(uint64(now.UnixMilli()), next.Timestamp())
+	is.Equal(1, compare(next, prev))
+}
+
+// TestGenerator_MonotonicOverflow verifies that exhausting the entropy in one
+// millisecond returns ErrMonotonicOverflow until the clock advances.
+func TestGenerator_MonotonicOverflow(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	// The all-ones entropy is followed by zeros for two full refills, because sync.Pool may
+	// drop the batch between calls (it does so at random under the race detector).
+	src := bytes.NewReader(append(bytes.Repeat([]byte{0xff}, entropyLen), make([]byte, 2*batchSize*entropyLen)...))
+	g, err := NewGenerator(WithReader(src), WithMonotonic(true))
+	is.NoError(err)
+	now := time.UnixMilli(5000)
+	g.now = func() time.Time { return now }
+
+	id, err := g.New()
+	is.NoError(err)
+	is.Equal("00000004W8ZZZZZZZZZZZZZZZZ", id.String())
+
+	for i := 0; i < 2; i++ {
+		_, err = g.New()
+		is.ErrorIs(err, ErrMonotonicOverflow)
+	}
+
+	now = now.Add(time.Millisecond)
+	id, err = g.New()
+	is.NoError(err)
+	is.Equal(uint64(5001), id.Timestamp())
+}
+
+// TestGenerator_MonotonicConcurrent verifies uniqueness and per-goroutine ordering.
+func TestGenerator_MonotonicConcurrent(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	g, err := NewGenerator(WithMonotonic(true))
+	is.NoError(err)
+
+	const goroutines, perG = 8, 1000
+	results := make([][]ULID, goroutines)
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < perG; j++ {
+				id, err := g.New()
+				if err != nil {
+					t.Errorf("New failed: %v", err)
+					return
+				}
+				results[i] = append(results[i], id)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	all := make([]ULID, 0, goroutines*perG)
+	for _, r := range results {
+		is.True(slices.IsSortedFunc(r, compare))
+		all = append(all, r...)
+	}
+	slices.SortFunc(all, compare)
+	is.Len(slices.Compact(all), goroutines*perG, "duplicate ULIDs")
+}
+
+// TestGenerator_ReadError verifies that reader errors are returned.
+func TestGenerator_ReadError(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	g, err := NewGenerator(WithReader(errReader{}), WithMonotonic(true))
+	is.NoError(err)
+
+	id, err := g.New()
+	is.ErrorIs(err, errRead)
+	is.Equal(Zero, id)
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package ulid generates Universally Unique Lexicographically Sortable Identifiers
+// (ULIDs) from the prng package.
+//
+// A ULID is a 48-bit Unix millisecond timestamp followed by 80 random bits, encoded as
+// 26 characters of Crockford's base32. ULIDs sort by creation time both as bytes and
+// as strings. In monotonic mode, ULIDs created within the same millisecond increment
+// the random part instead of drawing a new one, so they remain strictly ordered.
+//
+// Example:
+//
+//	id, err := ulid.New()
+//	if err != nil {
+//	    // handle error
+//	}
+//	fmt.Println(id) // 01J2ZK6M1W8Q3V4XH5T7N9B0CD
+package ulid
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidFormat = fmt.Errorf("ulid: invalid format, expected 26 Crockford base32 characters")
+	ErrTimeInvalid   = fmt.Errorf("ulid: time must be between the Unix epoch and MaxTime")
+)
+
+// ULID is a 128-bit identifier: a big-endian 48-bit millisecond timestamp followed by
+// 80 bits of entropy.
+type ULID [16]byte
+
+// Zero is the zero ULID.
+var Zero ULID
+
+// MaxTime is the latest time representable in a ULID, in the year 10889.
+var MaxTime = time.UnixMilli(maxMillis)
+
+const (
+	// maxMillis is the largest 48-bit timestamp.
+	maxMillis = 1<<48 - 1
+
+	// encodedLen is the length of the string form.
+	encodedLen = 26
+
+	// encodeAlphabet is Crockford's base32 alphabet.
+	encodeAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+)
+
+// decodeTable maps both cases of each alphabet character to its value; all other
+// bytes map to 0xff.
+var decodeTable = func() [256]byte {
+	var t [256]byte
+	for i := range t {
+		t[i] = 0xff
+	}
+	for i := 0; i < len(encodeAlphabet); i++ {
+		c := encodeAlphabet[i]
+		t[c] = byte(i)
+		if 'A' <= c && c <= 'Z' {
+			t[c+'a'-'A'] = byte(i)
+		}
+	}
+	return t
+}()
+
+// Time returns the timestamp of id with millisecond precision.
+func (id ULID) Time() time.Time {
+	return time.UnixMilli(int64(id.Timestamp()))
+}
+
+// Timestamp returns the timestamp of id in milliseconds since the Unix epoch.
+func (id ULID) Timestamp() uint64 {
+	return uint64(id[0])<<40 | uint64(id[1])<<32 | uint64(id[2])<<24 | uint64(id[3])<<16 | uint64(id[4])<<8 | uint64(id[5])
+}
+
+// String returns the 26-character upper-case Crockford base32 form of id.
+func (id ULID) String() string {
+	var buf [encodedLen]byte
+	encode(buf[:], id)
+	return string(buf[:])
+}
+
+// AppendText implements encoding.TextAppender. It appends the string form to b.
+func (id ULID) AppendText(b []byte) ([]byte, error) {
+	n := len(b)
+	b = append(b, make([]byte, encodedLen)...)
+	encode(b[n:], id)
+	return b, nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (id ULID) MarshalText() ([]byte, error) {
+	return id.AppendText(make([]byte, 0, encodedLen))
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts either case.
+func (id *ULID) UnmarshalText(text []byte) error {
+	v, err := parse(text)
+	if err != nil {
+		return err
+	}
+	*id = v
+	return nil
+}
+
+// Parse decodes the 26-character string form of a ULID, in either case.
+//
+// It returns ErrInvalidFormat if s has the wrong length, contains characters outside
+// Crockford's base32 alphabet, or encodes a value larger than 128 bits.
+func Parse(s string) (ULID, error) {
+	return parse([]byte(s))
+}
+
+// Must returns id, panicking if err is non-nil.
+func Must(id ULID, err error) ULID {
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// encode writes the string form of id into dst, which must hold encodedLen bytes. The
+// 128 bits are preceded by two zero bits so that they fill exactly 26 characters.
+func encode(dst []byte, id ULID) {
+	hi := binary.BigEndian.Uint64(id[:8])
+	lo := binary.BigEndian.Uint64(id[8:])
+	for i := encodedLen - 1; i >= 0; i-- {
+		dst[i] = encodeAlphabet[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+}
+
+// parse decodes the string form.
+func parse(text []byte) (ULID, error) {
+	if len(text) != encodedLen {
+		return Zero, ErrInvalidFormat
+	}
+	// The first character carries only the top 3 bits.
+	if decodeTable[text[0]] > 7 {
+		return Zero, ErrInvalidFormat
+	}
+
+	var hi, lo uint64
+	for _, c := range text {
+		v := decodeTable[c]
+		if v == 0xff {
+			return Zero, ErrInvalidFormat
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(v)
+	}
+
+	var id ULID
+	binary.BigEndian.PutUint64(id[:8], hi)
+	binary.BigEndian.PutUint64(id[8:], lo)
+	return id, nil
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package ulid
+
+import (
+	"testing"
+)
+
+// BenchmarkULID_Serial measures New in a serial loop.
+func BenchmarkULID_Serial(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		_, _ = New()
+	}
+}
+
+// BenchmarkULID_Parallel measures New with RunParallel.
+func BenchmarkULID_Parallel(b *testing.B) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = New()
+		}
+	})
+}
+
+// BenchmarkULID_Monotonic_Parallel measures monotonic generation with RunParallel,
+// where calls serialize on the generator's state.
+func BenchmarkULID_Monotonic_Parallel(b *testing.B) {
+	g, err := NewGenerator(WithMonotonic(true))
+	if err != nil {
+		b.Fatalf("NewGenerator failed: %v", err)
+	}
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = g.New()
+		}
+	})
+}
+
+// BenchmarkULID_String measures New followed by String.
+func BenchmarkULID_String(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		id, _ := New()
+		_ = id.String()
+	}
+}
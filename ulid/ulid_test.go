@@ -0,0 +1,94 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package ulid
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestULID_Encoding verifies the string form against known values.
+func TestULID_Encoding(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	is.Equal("00000000000000000000000000", Zero.String())
+
+	var max ULID
+	for i := range max {
+		max[i] = 0xff
+	}
+	is.Equal("7ZZZZZZZZZZZZZZZZZZZZZZZZZ", max.String())
+
+	// The timestamp from the ULID specification with entropy 1.
+	id := ULID{0x01, 0x56, 0x3d, 0xf3, 0x64, 0x81, 15: 0x01}
+	is.Equal("01ARYZ6S410000000000000001", id.String())
+	is.Equal(uint64(1469918176385), id.Timestamp())
+	is.Equal(time.UnixMilli(1469918176385), id.Time())
+}
+
+// TestULID_Parse verifies round trips, case insensitivity and invalid input.
+func TestULID_Parse(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	for i := 0; i < 100; i++ {
+		id := Must(New())
+		got, err := Parse(id.String())
+		is.NoError(err)
+		is.Equal(id, got)
+
+		got, err = Parse(strings.ToLower(id.String()))
+		is.NoError(err)
+		is.Equal(id, got)
+	}
+
+	invalid := []string{
+		"",
+		"01ARYZ6S41TSV4RRFFQ69G5FA",   // too short
+		"01ARYZ6S41TSV4RRFFQ69G5FAVX", // too long
+		"01ARYZ6S41TSV4RRFFQ69G5FAU",  // U is not in the alphabet
+		"01ARYZ6S41TSV4RRFFQ69G5FA!",
+		"80000000000000000000000000", // exceeds 128 bits
+	}
+	for _, s := range invalid {
+		_, err := Parse(s)
+		is.ErrorIs(err, ErrInvalidFormat, "input %q", s)
+	}
+}
+
+// TestULID_JSON verifies that ULIDs round-trip through encoding/json as strings.
+func TestULID_JSON(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	type event struct {
+		ID ULID `json:"id"`
+	}
+	in := event{ID: Must(New())}
+	b, err := json.Marshal(in)
+	is.NoError(err)
+	is.Equal(`{"id":"`+in.ID.String()+`"}`, string(b))
+
+	var out event
+	is.NoError(json.Unmarshal(b, &out))
+	is.Equal(in, out)
+
+	buf, err := in.ID.AppendText([]byte("evt_"))
+	is.NoError(err)
+	is.Equal("evt_"+in.ID.String(), string(buf))
+}
+
+// TestULID_Must verifies that Must panics on error.
+func TestULID_Must(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() { Must(Zero, ErrInvalidFormat) })
+}
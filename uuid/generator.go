@@ -0,0 +1,192 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package uuid
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/sixafter/prng-chacha"
+	"github.com/sixafter/prng-chacha/internal/randutil"
+)
+
+var (
+	ErrBatchSizeInvalid = fmt.Errorf("uuid: batch size must be greater than zero")
+	ErrNilReader        = fmt.Errorf("uuid: reader must not be nil")
+)
+
+// defaultBatchSize is the default number of UUIDs worth of random bytes read at once.
+const defaultBatchSize = 32
+
+// Config defines the randomness source and behaviour of a Generator.
+type Config struct {
+	// Reader is the source of randomness. Defaults to prng.Reader.
+	Reader io.Reader
+
+	// BatchSize is the number of UUIDs worth of random bytes (16 each) read from Reader
+	// at once and buffered per pooled batch. Defaults to 32. A size of 1 disables
+	// buffering.
+	BatchSize int
+
+	// Monotonic makes NewV7 strictly increasing within the Generator. The 12-bit rand_a
+	// field carries sub-millisecond clock precision (RFC 9562 section 6.2, method 3) and
+	// is incremented, carrying into the timestamp, whenever the clock has not advanced
+	// since the previous UUID. Monotonic generation serializes NewV7 calls.
+	Monotonic bool
+}
+
+// Option defines a functional option for customizing a Generator's Config.
+type Option func(*Config)
+
+// WithReader returns an Option that sets the source of randomness, typically a
+// prng.Interface returned by prng.NewReader.
+func WithReader(r io.Reader) Option {
+	return func(cfg *Config) {
+		cfg.Reader = r
+	}
+}
+
+// WithBatchSize returns an Option that sets how many UUIDs worth of random bytes are
+// read at once.
+func WithBatchSize(n int) Option {
+	return func(cfg *Config) {
+		cfg.BatchSize = n
+	}
+}
+
+// WithMonotonic returns an Option that makes NewV7 strictly increasing.
+func WithMonotonic(enabled bool) Option {
+	return func(cfg *Config) {
+		cfg.Monotonic = enabled
+	}
+}
+
+// Generator produces version 4 and version 7 UUIDs. It is safe for concurrent use.
+type Generator struct {
+	monotonic bool
+	batches   *randutil.Batches
+
+	// now returns the current time; replaced in tests.
+	now func() time.Time
+
+	mu     sync.Mutex
+	lastMs int64
+	lastA  uint16
+}
+
+// defaultGenerator backs the package-level NewV4 and NewV7.
+var defaultGenerator = func() *Generator {
+	g, err := NewGenerator()
+	if err != nil {
+		panic(err)
+	}
+	return g
+}()
+
+// NewGenerator returns a Generator configured by opts.
+//
+// It returns ErrNilReader if the configured reader is nil and ErrBatchSizeInvalid if
+// the batch size is not positive.
+func NewGenerator(opts ...Option) (*Generator, error) {
+	cfg := Config{
+		Reader:    prng.Reader,
+		BatchSize: defaultBatchSize,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.Reader == nil {
+		return nil, ErrNilReader
+	}
+	if cfg.BatchSize <= 0 {
+		return nil, ErrBatchSizeInvalid
+	}
+
+	return &Generator{
+		monotonic: cfg.Monotonic,
+		batches:   randutil.NewBatches(cfg.Reader, len(UUID{}), cfg.BatchSize),
+		now:       time.Now,
+	}, nil
+}
+
+// NewV4 returns a random (version 4) UUID from the default Generator.
+func NewV4() (UUID, error) {
+	return defaultGenerator.NewV4()
+}
+
+// NewV7 returns a Unix time-ordered (version 7) UUID from the default Generator, which
+// is not monotonic.
+func NewV7() (UUID, error) {
+	return defaultGenerator.NewV7()
+}
+
+// NewV4 returns a random (version 4) UUID.
+//
+// It returns an error only if the underlying reader fails.
+func (g *Generator) NewV4() (UUID, error) {
+	var u UUID
+	if err := g.random(&u); err != nil {
+		return Nil, err
+	}
+	u[6] = u[6]&0x0f | 0x40 // version 4
+	u[8] = u[8]&0x3f | 0x80 // variant 10
+	return u, nil
+}
+
+// NewV7 returns a Unix time-ordered (version 7) UUID: a 48-bit millisecond timestamp
+// followed by 74 random bits, or by 12 bits of sub-millisecond precision and 62 random
+// bits in monotonic mode.
+//
+// It returns an error only if the underlying reader fails.
+func (g *Generator) NewV7() (UUID, error) {
+	var u UUID
+	if err := g.random(&u); err != nil {
+		return Nil, err
+	}
+
+	t := g.now()
+	ms := t.UnixMilli()
+	randA := binary.BigEndian.Uint16(u[6:8]) & 0x0fff
+	if g.monotonic {
+		ms, randA = g.next(ms, uint16(t.Nanosecond()%1e6*4096/1e6))
+	}
+
+	u[0] = byte(ms >> 40)
+	u[1] = byte(ms >> 32)
+	u[2] = byte(ms >> 24)
+	u[3] = byte(ms >> 16)
+	u[4] = byte(ms >> 8)
+	u[5] = byte(ms)
+	binary.BigEndian.PutUint16(u[6:8], 0x7000|randA) // version 7
+	u[8] = u[8]&0x3f | 0x80                          // variant 10
+	return u, nil
+}
+
+// next returns the timestamp and rand_a for a monotonic version 7 UUID, given the
+// current millisecond and sub-millisecond fraction (0-4095). If the clock has not moved
+// past the previous UUID, the previous value is incremented instead.
+func (g *Generator) next(ms int64, frac uint16) (int64, uint16) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if ms < g.lastMs || (ms == g.lastMs && frac <= g.lastA) {
+		ms, frac = g.lastMs, g.lastA+1
+		if frac > 0x0fff {
+			ms, frac = ms+1, 0
+		}
+	}
+	g.lastMs, g.lastA = ms, frac
+	return ms, frac
+}
+
+// random fills u with 16 random bytes from the generator's pooled batches.
+func (g *Generator) random(u *UUID) error {
+	return g.batches.Read(u[:])
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package uuid
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sixafter/prng-chacha"
+	"github.com/sixafter/prng-chacha/internal/race"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingReader wraps a reader and counts Read calls.
+type countingReader struct {
+	r     io.Reader
+	mu    sync.Mutex
+	calls int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	c.mu.Lock()
+	c.calls++
+	c.mu.Unlock()
+	return c.r.Read(p)
+}
+
+// errReader always fails.
+type errReader struct{}
+
+var errRead = errors.New("read failed")
+
+func (errReader) Read([]byte) (int, error) { return 0, errRead }
+
+// TestGenerator_Validation verifies that invalid configurations are rejected.
+func TestGenerator_Validation(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	g, err := NewGenerator(WithReader(nil))
+	is.ErrorIs(err, ErrNilReader)
+	is.Nil(g)
+
+	g, err = NewGenerator(WithBatchSize(0))
+	is.ErrorIs(err, ErrBatchSizeInvalid)
+	is.Nil(g)
+}
+
+// TestGenerator_V4 verifies the version and variant bits and uniqueness.
+func TestGenerator_V4(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	seen := make(map[UUID]struct{})
+	for i := 0; i < 10000; i++ {
+		u, err := NewV4()
+		is.NoError(err)
+		is.Equal(byte(0x40), u[6]&0xf0, "version bits")
+		is.Equal(byte(0x80), u[8]&0xc0, "variant bits")
+		_, dup := seen[u]
+		is.False(dup)
+		seen[u] = struct{}{}
+	}
+}
+
+// TestGenerator_V7 verifies the layout of version 7 UUIDs from a fixed source and clock.
+func TestGenerator_V7(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	src := bytes.Repeat([]byte{0xff}, 16)
+	g, err := NewGenerator(WithReader(bytes.NewReader(src)), WithBatchSize(1))
+	is.NoError(err)
+	g.now = func() time.Time { return time.UnixMilli(0x0123456789ab) }
+
+	u, err := g.NewV7()
+	is.NoError(err)
+	is.Equal("01234567-89ab-7fff-bfff-ffffffffffff", u.String())
+	is.Equal(int64(0x0123456789ab), u.Time().UnixMilli())
+}
+
+// TestGenerator_V7_Monotonic verifies strict ordering under a stalled or regressing
+// clock, including the carry from rand_a into the timestamp.
+func TestGenerator_V7_Monotonic(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	g, err := NewGenerator(WithMonotonic(true))
+	is.NoError(err)
+	now := time.UnixMilli(1_700_000_000_000)
+	g.now = func() time.Time { return now }
+
+	prev := Must(g.NewV7())
+	for i := 0; i < 5000; i++ {
+		if i == 2500 {
+			now = now.Add(-time.Second) // clock steps backwards
+		}
+		u := Must(g.NewV7())
+		is.Equal(1, bytes.Compare(u[:], prev[:]), "UUID %d not greater than its predecessor", i)
+		is.Equal(7, u.Version())
+		prev = u
+	}
+	// 5001 UUIDs in one stalled millisecond overflow the 4096 rand_a values once.
+	is.Equal(now.Add(time.Second).UnixMilli()+1, prev.Time().UnixMilli())
+}
+
+// TestGenerator_V7_MonotonicConcurrent verifies uniqueness and per-call ordering across
+// goroutines with the real clock.
+func TestGenerator_V7_MonotonicConcurrent(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	g, err := NewGenerator(WithMonotonic(true))
+	is.NoError(err)
+
+	const goroutines, perG = 8, 1000
+	results := make([][]UUID, goroutines)
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < perG; j++ {
+				u, err := g.NewV7()
+				if err != nil {
+					t.Errorf("NewV7 failed: %v", err)
+					return
+				}
+				results[i] = append(results[i], u)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	all := make([]UUID, 0, goroutines*perG)
+	for _, r := range results {
+		is.True(slices.IsSortedFunc(r, func(a, b UUID) int { return bytes.Compare(a[:], b[:]) }))
+		all = append(all, r...)
+	}
+	slices.SortFunc(all, func(a, b UUID) int { return bytes.Compare(a[:], b[:]) })
+	is.Len(slices.Compact(all), goroutines*perG, "duplicate UUIDs")
+}
+
+// TestGenerator_Batching verifies that random bytes are read once per batch.
+func TestGenerator_Batching(t *testing.T) {
+	if race.Enabled {
+		t.Skip("sync.Pool drops batches at random under the race detector")
+	}
+	is := assert.New(t)
+
+	src := &countingReader{r: prng.Reader}
+	g, err := NewGenerator(WithReader(src), WithBatchSize(64))
+	is.NoError(err)
+
+	for i := 0; i < 64; i++ {
+		_, err = g.NewV4()
+		is.NoError(err)
+	}
+	// A pool may occasionally drop a batch, but with a single goroutine 64 UUIDs should
+	// come from very few reads.
+	is.LessOrEqual(src.calls, 8)
+	is.GreaterOrEqual(src.calls, 1)
+}
+
+// TestGenerator_ReadError verifies that reader errors are returned and not cached.
+func TestGenerator_ReadError(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	g, err := NewGenerator(WithReader(errReader{}))
+	is.NoError(err)
+
+	u, err := g.NewV4()
+	is.ErrorIs(err, errRead)
+	is.Equal(Nil, u)
+
+	u, err = g.NewV7()
+	is.ErrorIs(err, errRead)
+	is.Equal(Nil, u)
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package uuid generates RFC 9562 version 4 (random) and version 7 (Unix time-ordered)
+// UUIDs from the prng package.
+//
+// Unlike calling uuid.SetRand(prng.Reader) on a third-party package, generation does not
+// mutate global state and random bytes are read in batches from a pool, so most UUIDs
+// cost no Read call at all.
+//
+// Example:
+//
+//	id, err := uuid.NewV7()
+//	if err != nil {
+//	    // handle error
+//	}
+//	fmt.Println(id) // 0190e7a4-5c2b-7b7e-9f0a-3d1c2b4a5e6f
+package uuid
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidFormat = fmt.Errorf("uuid: invalid format, expected xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx")
+)
+
+// UUID is a 128-bit universally unique identifier as defined by RFC 9562.
+type UUID [16]byte
+
+// Nil is the nil UUID, with all 128 bits set to zero.
+var Nil UUID
+
+// encodedLen is the length of the canonical string form.
+const encodedLen = 36
+
+// Version returns the version number stored in u (4 or 7 for UUIDs generated by this
+// package).
+func (u UUID) Version() int {
+	return int(u[6] >> 4)
+}
+
+// Time returns the Unix timestamp, with millisecond precision, embedded in a version 7
+// UUID. It returns the zero time for other versions.
+func (u UUID) Time() time.Time {
+	if u.Version() != 7 {
+		return time.Time{}
+	}
+	ms := int64(u[0])<<40 | int64(u[1])<<32 | int64(u[2])<<24 | int64(u[3])<<16 | int64(u[4])<<8 | int64(u[5])
+	return time.UnixMilli(ms)
+}
+
+// String returns the canonical lower-case form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx.
+func (u UUID) String() string {
+	var buf [encodedLen]byte
+	encode(buf[:], u)
+	return string(buf[:])
+}
+
+// AppendText implements encoding.TextAppender. It appends the canonical form to b.
+func (u UUID) AppendText(b []byte) ([]byte, error) {
+	n := len(b)
+	b = append(b, make([]byte, encodedLen)...)
+	encode(b[n:], u)
+	return b, nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (u UUID) MarshalText() ([]byte, error) {
+	return u.AppendText(make([]byte, 0, encodedLen))
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts the canonical form in
+// either case.
+func (u *UUID) UnmarshalText(text []byte) error {
+	id, err := parse(text)
+	if err != nil {
+		return err
+	}
+	*u = id
+	return nil
+}
+
+// Parse decodes s in the canonical form, in either case, into a UUID.
+//
+// It returns ErrInvalidFormat if s is not a canonical UUID string.
+func Parse(s string) (UUID, error) {
+	return parse([]byte(s))
+}
+
+// Must returns u, panicking if err is non-nil. It simplifies initialization of
+// package-level variables, e.g. var id = uuid.Must(uuid.NewV4()).
+func Must(u UUID, err error) UUID {
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// encode writes the canonical form of u into dst, which must hold encodedLen bytes.
+func encode(dst []byte, u UUID) {
+	hex.Encode(dst[0:8], u[0:4])
+	dst[8] = '-'
+	hex.Encode(dst[9:13], u[4:6])
+	dst[13] = '-'
+	hex.Encode(dst[14:18], u[6:8])
+	dst[18] = '-'
+	hex.Encode(dst[19:23], u[8:10])
+	dst[23] = '-'
+	hex.Encode(dst[24:], u[10:])
+}
+
+// parse decodes the canonical form.
+func parse(text []byte) (UUID, error) {
+	var u UUID
+	if len(text) != encodedLen || text[8] != '-' || text[13] != '-' || text[18] != '-' || text[23] != '-' {
+		return Nil, ErrInvalidFormat
+	}
+	// Offsets of the 16 hex pairs in the canonical form.
+	for i, off := range [16]int{0, 2, 4, 6, 9, 11, 14, 16, 19, 21, 24, 26, 28, 30, 32, 34} {
+		hi, ok1 := fromHex(text[off])
+		lo, ok2 := fromHex(text[off+1])
+		if !ok1 || !ok2 {
+			return Nil, ErrInvalidFormat
+		}
+		u[i] = hi<<4 | lo
+	}
+	return u, nil
+}
+
+// fromHex returns the value of a hexadecimal digit in either case.
+func fromHex(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
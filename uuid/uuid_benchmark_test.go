@@ -0,0 +1,123 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package uuid
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// benchConcurrent runs fn across the given number of goroutines, distributing b.N
+// iterations as evenly as possible.
+func benchConcurrent(b *testing.B, fn func(), goroutines int) {
+	nPerG := b.N / goroutines
+	rem := b.N % goroutines
+	var wg sync.WaitGroup
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < goroutines; i++ {
+		it := nPerG
+		if i < rem {
+			it++
+		}
+		wg.Add(1)
+		go func(it int) {
+			defer wg.Done()
+			for j := 0; j < it; j++ {
+				fn()
+			}
+		}(it)
+	}
+	wg.Wait()
+}
+
+// BenchmarkUUID_v4_Native_Serial measures NewV4 in a serial loop. Compare with
+// BenchmarkUUID_v4_CSPRNG_Serial in the prng package, which feeds prng.Reader to
+// github.com/google/uuid.
+func BenchmarkUUID_v4_Native_Serial(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		_, _ = NewV4()
+	}
+}
+
+// BenchmarkUUID_v4_Native_Parallel measures NewV4 with RunParallel.
+func BenchmarkUUID_v4_Native_Parallel(b *testing.B) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = NewV4()
+		}
+	})
+}
+
+// BenchmarkUUID_v4_Native_Concurrent measures NewV4 across goroutine counts.
+func BenchmarkUUID_v4_Native_Concurrent(b *testing.B) {
+	for _, gr := range []int{2, 4, 8, 16, 32, 64, 128, 256} {
+		b.Run("Goroutines_"+strconv.Itoa(gr), func(b *testing.B) {
+			benchConcurrent(b, func() { _, _ = NewV4() }, gr)
+		})
+	}
+}
+
+// BenchmarkUUID_v4_Native_BatchSize measures the effect of the batch size on NewV4.
+func BenchmarkUUID_v4_Native_BatchSize(b *testing.B) {
+	for _, n := range []int{1, 8, 32, 128} {
+		b.Run("Batch_"+strconv.Itoa(n), func(b *testing.B) {
+			g, err := NewGenerator(WithBatchSize(n))
+			if err != nil {
+				b.Fatalf("NewGenerator failed: %v", err)
+			}
+			b.ReportAllocs()
+			for b.Loop() {
+				_, _ = g.NewV4()
+			}
+		})
+	}
+}
+
+// BenchmarkUUID_v4_Native_String measures NewV4 followed by String.
+func BenchmarkUUID_v4_Native_String(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		u, _ := NewV4()
+		_ = u.String()
+	}
+}
+
+// BenchmarkUUID_v7_Native_Serial measures NewV7 in a serial loop.
+func BenchmarkUUID_v7_Native_Serial(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		_, _ = NewV7()
+	}
+}
+
+// BenchmarkUUID_v7_Native_Parallel measures NewV7 with RunParallel.
+func BenchmarkUUID_v7_Native_Parallel(b *testing.B) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = NewV7()
+		}
+	})
+}
+
+// BenchmarkUUID_v7_Native_Monotonic_Parallel measures monotonic NewV7 with RunParallel,
+// where calls serialize on the generator's clock state.
+func BenchmarkUUID_v7_Native_Monotonic_Parallel(b *testing.B) {
+	g, err := NewGenerator(WithMonotonic(true))
+	if err != nil {
+		b.Fatalf("NewGenerator failed: %v", err)
+	}
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = g.NewV7()
+		}
+	})
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2024-2026 Six After, Inc
+//
+// This source code is licensed under the Apache 2.0 License found in the
+// LICENSE file in the root directory of this source tree.
+
+package uuid
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	googleuuid "github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestUUID_String verifies the canonical form against github.com/google/uuid.
+func TestUUID_String(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	for i := 0; i < 100; i++ {
+		u := Must(NewV4())
+		is.Equal(googleuuid.UUID(u).String(), u.String())
+
+		text, err := u.MarshalText()
+		is.NoError(err)
+		is.Equal(u.String(), string(text))
+
+		b, err := u.AppendText([]byte("id="))
+		is.NoError(err)
+		is.Equal("id="+u.String(), string(b))
+	}
+	is.Equal("00000000-0000-0000-0000-000000000000", Nil.String())
+}
+
+// TestUUID_Parse verifies round trips, case insensitivity and rejection of malformed input.
+func TestUUID_Parse(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	u := Must(NewV7())
+	got, err := Parse(u.String())
+	is.NoError(err)
+	is.Equal(u, got)
+
+	got, err = Parse("F81D4FAE-7DEC-11D0-A765-00A0C91E6BF6")
+	is.NoError(err)
+	is.Equal(UUID(googleuuid.MustParse("f81d4fae-7dec-11d0-a765-00a0c91e6bf6")), got)
+
+	invalid := []string{
+		"",
+		"f81d4fae7dec11d0a76500a0c91e6bf6",
+		"f81d4fae-7dec-11d0-a765-00a0c91e6bf",
+		"f81d4fae-7dec-11d0-a765-00a0c91e6bf6a",
+		"f81d4fae_7dec-11d0-a765-00a0c91e6bf6",
+		"f81d4fae-7dec-11d0-a765-00a0c91e6bg6",
+		"urn:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6",
+	}
+	for _, s := range invalid {
+		_, err := Parse(s)
+		is.ErrorIs(err, ErrInvalidFormat, "input %q", s)
+	}
+}
+
+// TestUUID_JSON verifies that UUIDs round-trip through encoding/json as strings.
+func TestUUID_JSON(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	type record struct {
+		ID UUID `json:"id"`
+	}
+	in := record{ID: Must(NewV4())}
+	b, err := json.Marshal(in)
+	is.NoError(err)
+	is.Equal(`{"id":"`+in.ID.String()+`"}`, string(b))
+
+	var out record
+	is.NoError(json.Unmarshal(b, &out))
+	is.Equal(in, out)
+
+	is.Error(json.Unmarshal([]byte(`{"id":"not-a-uuid"}`), &out))
+}
+
+// TestUUID_VersionAndTime verifies the accessors for both versions.
+func TestUUID_VersionAndTime(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	v4 := Must(NewV4())
+	is.Equal(4, v4.Version())
+	is.True(v4.Time().IsZero())
+
+	before := time.Now().Truncate(time.Millisecond)
+	v7 := Must(NewV7())
+	after := time.Now()
+	is.Equal(7, v7.Version())
+	is.False(v7.Time().Before(before))
+	is.False(v7.Time().After(after))
+}
+
+// TestUUID_Must verifies that Must panics on error.
+func TestUUID_Must(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() { Must(Nil, ErrInvalidFormat) })
+}